@@ -0,0 +1,161 @@
+package api
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// POST /api/alerts/receive/alertmanager
+func ReceiveAlertmanagerAlerts(c *m.ReqContext, dto dtos.AlertmanagerWebhookPayload) Response {
+	alerts := make([]*alerting.ReceivedAlert, 0, len(dto.Alerts))
+
+	for _, alert := range dto.Alerts {
+		received := &alerting.ReceivedAlert{
+			Title:   alert.Labels["alertname"],
+			Message: alert.Annotations["summary"],
+			State:   m.AlertStateAlerting,
+			Labels:  alert.Labels,
+			Time:    alert.StartsAt,
+		}
+
+		if received.Message == "" {
+			received.Message = alert.Annotations["description"]
+		}
+
+		if alert.Status == "resolved" {
+			received.State = m.AlertStateOK
+			received.Time = alert.EndsAt
+		}
+
+		alerts = append(alerts, received)
+	}
+
+	return receiveAlerts(c, alerts)
+}
+
+// POST /api/alerts/receive
+func ReceiveAlerts(c *m.ReqContext, dto dtos.ReceivedAlertsPayload) Response {
+	alerts := make([]*alerting.ReceivedAlert, 0, len(dto.Alerts))
+
+	for _, alert := range dto.Alerts {
+		if alert.Title == "" {
+			return Error(400, "Received alerts require a title", nil)
+		}
+
+		received := &alerting.ReceivedAlert{
+			Title:       alert.Title,
+			Message:     alert.Message,
+			State:       m.AlertStateType(alert.State),
+			Labels:      alert.Labels,
+			EvalMatches: make([]*alerting.EvalMatch, 0, len(alert.Values)),
+		}
+
+		if alert.Time > 0 {
+			received.Time = time.Unix(0, alert.Time*int64(time.Millisecond))
+		}
+
+		metrics := make([]string, 0, len(alert.Values))
+		for metric := range alert.Values {
+			metrics = append(metrics, metric)
+		}
+		sort.Strings(metrics)
+
+		for _, metric := range metrics {
+			received.EvalMatches = append(received.EvalMatches, &alerting.EvalMatch{
+				Metric: metric,
+				Value:  alert.Values[metric],
+				Tags:   alert.Labels,
+			})
+		}
+
+		alerts = append(alerts, received)
+	}
+
+	return receiveAlerts(c, alerts)
+}
+
+func receiveAlerts(c *m.ReqContext, alerts []*alerting.ReceivedAlert) Response {
+	dashboardUid := c.Query("dashboardUid")
+	if dashboardUid == "" {
+		return Error(400, "The dashboardUid query parameter is required", nil)
+	}
+
+	dash, rsp := getDashboardHelper(c.OrgId, "", 0, dashboardUid)
+	if rsp != nil {
+		return rsp
+	}
+
+	if canSave, err := canSaveByDashboardID(c, dash.Id); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	cmd := alerting.ReceiveAlertsCommand{
+		OrgId:        c.OrgId,
+		DashboardId:  dash.Id,
+		PanelId:      c.QueryInt64("panelId"),
+		NotifierUids: make([]string, 0),
+		Alerts:       alerts,
+	}
+
+	for _, uid := range strings.Split(c.Query("notifiers"), ",") {
+		if uid = strings.TrimSpace(uid); uid != "" {
+			cmd.NotifierUids = append(cmd.NotifierUids, uid)
+		}
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		if validationErr, ok := err.(alerting.ValidationError); ok {
+			return Error(422, validationErr.Error(), nil)
+		}
+		return Error(500, "Failed to receive alerts", err)
+	}
+
+	return JSON(200, util.DynMap{
+		"message":  "Alerts received",
+		"received": len(alerts),
+		"changed":  cmd.Changed,
+	})
+}
+
+// GET /api/alerts/received
+func GetReceivedAlerts(c *m.ReqContext) Response {
+	query := m.GetAlertInstancesQuery{
+		OrgId:       c.OrgId,
+		AlertId:     0,
+		DashboardId: c.QueryInt64("dashboardId"),
+		State:       c.QueryStrings("state"),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return Error(500, "List received alerts failed", err)
+	}
+
+	// received alerts are only visible to users that can view their dashboard
+	canView := make(map[int64]bool)
+	res := make([]*m.AlertInstance, 0, len(query.Result))
+	for _, instance := range query.Result {
+		allowed, checked := canView[instance.DashboardId]
+		if !checked {
+			var err error
+			allowed, err = guardian.New(instance.DashboardId, c.OrgId, c.SignedInUser).CanView()
+			if err != nil {
+				return Error(500, "Error while checking permissions for received alerts", err)
+			}
+			canView[instance.DashboardId] = allowed
+		}
+
+		if allowed {
+			res = append(res, instance)
+		}
+	}
+
+	return JSON(200, res)
+}
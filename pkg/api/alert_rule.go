@@ -0,0 +1,200 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+)
+
+// GET /api/alert-rules
+func GetAlertRules(c *m.ReqContext) Response {
+	folderIDs := make([]int64, 0)
+	for _, id := range c.QueryStrings("folderId") {
+		folderID, err := strconv.ParseInt(id, 10, 64)
+		if err == nil {
+			folderIDs = append(folderIDs, folderID)
+		}
+	}
+
+	query := m.GetAlertsQuery{
+		OrgId:      c.OrgId,
+		Standalone: true,
+		FolderIDs:  folderIDs,
+		Limit:      c.QueryInt64("limit"),
+		User:       c.SignedInUser,
+		Query:      c.Query("query"),
+	}
+
+	states := c.QueryStrings("state")
+	if len(states) > 0 {
+		query.State = states
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return Error(500, "List alert rules failed", err)
+	}
+
+	for _, alert := range query.Result {
+		alert.Url = m.GetAlertRuleUrl(alert.Id)
+	}
+
+	return JSON(200, query.Result)
+}
+
+// GET /api/alert-rules/:alertId
+func GetAlertRule(c *m.ReqContext) Response {
+	alert, rsp := getAlertRuleForRequest(c, c.ParamsInt64(":alertId"))
+	if rsp != nil {
+		return rsp
+	}
+
+	if rsp := checkAlertRuleFolderPermission(c, alert.FolderId, false); rsp != nil {
+		return rsp
+	}
+
+	return JSON(200, alert)
+}
+
+// POST /api/alert-rules
+func CreateAlertRule(c *m.ReqContext, dto dtos.SaveAlertRuleCommand) Response {
+	return saveAlertRule(c, 0, dto)
+}
+
+// PUT /api/alert-rules/:alertId
+func UpdateAlertRule(c *m.ReqContext, dto dtos.SaveAlertRuleCommand) Response {
+	alert, rsp := getAlertRuleForRequest(c, c.ParamsInt64(":alertId"))
+	if rsp != nil {
+		return rsp
+	}
+
+	// moving a rule needs edit permission on both folders
+	if rsp := checkAlertRuleFolderPermission(c, alert.FolderId, true); rsp != nil {
+		return rsp
+	}
+
+	return saveAlertRule(c, alert.Id, dto)
+}
+
+// DELETE /api/alert-rules/:alertId
+func DeleteAlertRule(c *m.ReqContext) Response {
+	alert, rsp := getAlertRuleForRequest(c, c.ParamsInt64(":alertId"))
+	if rsp != nil {
+		return rsp
+	}
+
+	if rsp := checkAlertRuleFolderPermission(c, alert.FolderId, true); rsp != nil {
+		return rsp
+	}
+
+	cmd := m.DeleteAlertRuleCommand{Id: alert.Id, OrgId: c.OrgId}
+	if err := bus.Dispatch(&cmd); err != nil {
+		return toAlertRuleError(err, "Failed to delete alert rule")
+	}
+
+	return Success("Alert rule deleted")
+}
+
+func saveAlertRule(c *m.ReqContext, id int64, dto dtos.SaveAlertRuleCommand) Response {
+	if dto.FolderId != 0 {
+		s := dashboards.NewFolderService(c.OrgId, c.SignedInUser)
+		if _, err := s.GetFolderByID(dto.FolderId); err != nil {
+			return toFolderError(err)
+		}
+	}
+
+	if rsp := checkAlertRuleFolderPermission(c, dto.FolderId, true); rsp != nil {
+		return rsp
+	}
+
+	// the settings are the alert json a graph panel would have
+	settings := dto.Settings
+	settings.Set("name", dto.Name)
+	settings.Set("message", dto.Message)
+	settings.Set("frequency", dto.Frequency)
+	settings.Set("for", dto.For)
+
+	validateCmd := m.ValidateAlertRuleCommand{
+		OrgId:    c.OrgId,
+		User:     c.SignedInUser,
+		Settings: settings,
+	}
+
+	if err := bus.Dispatch(&validateCmd); err != nil {
+		if validationErr, ok := err.(alerting.ValidationError); ok {
+			return Error(422, validationErr.Error(), nil)
+		}
+		if err == m.ErrDataSourceAccessDenied {
+			return Error(403, "Access denied to datasource", err)
+		}
+		return Error(500, "Failed to validate alert rule", err)
+	}
+
+	cmd := m.SaveAlertRuleCommand{
+		Id:        id,
+		OrgId:     c.OrgId,
+		FolderId:  dto.FolderId,
+		Name:      validateCmd.Result.Name,
+		Message:   validateCmd.Result.Message,
+		Frequency: validateCmd.Result.Frequency,
+		For:       validateCmd.Result.For,
+		Settings:  validateCmd.Result.Settings,
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		return toAlertRuleError(err, "Failed to save alert rule")
+	}
+
+	return JSON(200, cmd.Result)
+}
+
+func getAlertRuleForRequest(c *m.ReqContext, id int64) (*m.Alert, Response) {
+	query := m.GetAlertByIdQuery{Id: id}
+	if err := bus.Dispatch(&query); err != nil || query.Result.OrgId != c.OrgId {
+		return nil, toAlertRuleError(m.ErrAlertRuleNotFound, "")
+	}
+
+	if !query.Result.IsStandalone() {
+		return nil, toAlertRuleError(m.ErrAlertRuleIsDashboardAlert, "")
+	}
+
+	return query.Result, nil
+}
+
+// checkAlertRuleFolderPermission checks the permissions of the user on the
+// folder of an alert rule, the General folder is id 0.
+func checkAlertRuleFolderPermission(c *m.ReqContext, folderID int64, edit bool) Response {
+	g := guardian.New(folderID, c.OrgId, c.SignedInUser)
+
+	var allowed bool
+	var err error
+	if edit {
+		allowed, err = g.CanEdit()
+	} else {
+		allowed, err = g.CanView()
+	}
+	if err != nil {
+		return Error(500, "Error while checking permissions for alert rule", err)
+	}
+	if !allowed {
+		return Error(403, "Access denied to this folder and alert rule", nil)
+	}
+
+	return nil
+}
+
+func toAlertRuleError(err error, message string) Response {
+	if err == m.ErrAlertRuleNotFound {
+		return Error(404, err.Error(), nil)
+	}
+
+	if err == m.ErrAlertRuleIsDashboardAlert {
+		return Error(400, err.Error(), nil)
+	}
+
+	return Error(500, message, err)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertRuleApiEndpoint(t *testing.T) {
+	Convey("Given standalone alert rules in a folder", t, func() {
+		viewerRole := m.ROLE_VIEWER
+		editorRole := m.ROLE_EDITOR
+
+		alerts := map[int64]*m.Alert{
+			1: {Id: 1, OrgId: TestOrgID, FolderId: 5, Name: "rule"},
+			2: {Id: 2, OrgId: TestOrgID, DashboardId: 3, PanelId: 1, Name: "dashboard alert"},
+			3: {Id: 3, OrgId: 2, FolderId: 5, Name: "rule of other org"},
+		}
+
+		setupHandlers := func(aclMockResp []*m.DashboardAclInfoDTO) {
+			bus.AddHandler("test", func(query *m.GetAlertByIdQuery) error {
+				query.Result = alerts[query.Id]
+				if query.Result == nil {
+					return m.ErrAlertRuleNotFound
+				}
+				return nil
+			})
+
+			bus.AddHandler("test", func(query *m.GetDashboardAclInfoListQuery) error {
+				query.Result = aclMockResp
+				return nil
+			})
+
+			bus.AddHandler("test", func(query *m.GetTeamsByUserQuery) error {
+				query.Result = []*m.TeamDTO{}
+				return nil
+			})
+		}
+
+		defaultAcl := []*m.DashboardAclInfoDTO{
+			{Role: &viewerRole, Permission: m.PERMISSION_VIEW},
+			{Role: &editorRole, Permission: m.PERMISSION_EDIT},
+		}
+
+		loggedInUserScenarioWithRole("When calling GET on", "GET", "/api/alert-rules?folderId=5&state=alerting", "/api/alert-rules", m.ROLE_VIEWER, func(sc *scenarioContext) {
+			var getAlertsQuery *m.GetAlertsQuery
+			bus.AddHandler("test", func(query *m.GetAlertsQuery) error {
+				getAlertsQuery = query
+				query.Result = []*m.AlertListItemDTO{{Id: 1, FolderId: 5}}
+				return nil
+			})
+
+			sc.handlerFunc = GetAlertRules
+			sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+
+			So(sc.resp.Code, ShouldEqual, 200)
+			So(getAlertsQuery.Standalone, ShouldBeTrue)
+			So(getAlertsQuery.FolderIDs, ShouldResemble, []int64{5})
+			So(getAlertsQuery.State, ShouldResemble, []string{"alerting"})
+			So(getAlertsQuery.Result[0].Url, ShouldEqual, "/alerting/list?ruleId=1")
+		})
+
+		loggedInUserScenarioWithRole("When calling GET on", "GET", "/api/alert-rules/1", "/api/alert-rules/:alertId", m.ROLE_VIEWER, func(sc *scenarioContext) {
+			setupHandlers(defaultAcl)
+
+			sc.handlerFunc = GetAlertRule
+			sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+
+			So(sc.resp.Code, ShouldEqual, 200)
+		})
+
+		loggedInUserScenarioWithRole("When calling GET on", "GET", "/api/alert-rules/2", "/api/alert-rules/:alertId", m.ROLE_VIEWER, func(sc *scenarioContext) {
+			setupHandlers(defaultAcl)
+
+			sc.handlerFunc = GetAlertRule
+			sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+
+			So(sc.resp.Code, ShouldEqual, 400)
+		})
+
+		loggedInUserScenarioWithRole("When calling GET on", "GET", "/api/alert-rules/3", "/api/alert-rules/:alertId", m.ROLE_VIEWER, func(sc *scenarioContext) {
+			setupHandlers(defaultAcl)
+
+			sc.handlerFunc = GetAlertRule
+			sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+
+			So(sc.resp.Code, ShouldEqual, 404)
+		})
+
+		loggedInUserScenarioWithRole("When editor not in the folder ACL calls DELETE on", "DELETE", "/api/alert-rules/1", "/api/alert-rules/:alertId", m.ROLE_EDITOR, func(sc *scenarioContext) {
+			setupHandlers([]*m.DashboardAclInfoDTO{})
+
+			var deleteCmd *m.DeleteAlertRuleCommand
+			bus.AddHandler("test", func(cmd *m.DeleteAlertRuleCommand) error {
+				deleteCmd = cmd
+				return nil
+			})
+
+			sc.handlerFunc = DeleteAlertRule
+			sc.fakeReqWithParams("DELETE", sc.url, map[string]string{}).exec()
+
+			So(sc.resp.Code, ShouldEqual, 403)
+			So(deleteCmd, ShouldBeNil)
+		})
+
+		loggedInUserScenarioWithRole("When editor calls DELETE on", "DELETE", "/api/alert-rules/1", "/api/alert-rules/:alertId", m.ROLE_EDITOR, func(sc *scenarioContext) {
+			setupHandlers(defaultAcl)
+
+			var deleteCmd *m.DeleteAlertRuleCommand
+			bus.AddHandler("test", func(cmd *m.DeleteAlertRuleCommand) error {
+				deleteCmd = cmd
+				return nil
+			})
+
+			sc.handlerFunc = DeleteAlertRule
+			sc.fakeReqWithParams("DELETE", sc.url, map[string]string{}).exec()
+
+			So(sc.resp.Code, ShouldEqual, 200)
+			So(deleteCmd.Id, ShouldEqual, 1)
+			So(deleteCmd.OrgId, ShouldEqual, TestOrgID)
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// GET /api/alerts/silences
+func GetAlertSilences(c *m.ReqContext) Response {
+	query := m.GetAlertSilencesQuery{
+		OrgId: c.OrgId,
+		State: c.Query("state"),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return Error(500, "List alert silences failed", err)
+	}
+
+	return JSON(200, query.Result)
+}
+
+// GET /api/alerts/silences/:silenceId
+func GetAlertSilence(c *m.ReqContext) Response {
+	query := m.GetAlertSilenceByIdQuery{
+		OrgId: c.OrgId,
+		Id:    c.ParamsInt64(":silenceId"),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		if err == m.ErrAlertSilenceNotFound {
+			return Error(404, "Alert silence not found", nil)
+		}
+		return Error(500, "Failed to get alert silence", err)
+	}
+
+	return JSON(200, query.Result)
+}
+
+// POST /api/alerts/silences
+func CreateAlertSilence(c *m.ReqContext, cmd m.CreateAlertSilenceCommand) Response {
+	cmd.OrgId = c.OrgId
+	cmd.CreatedBy = c.UserId
+
+	if cmd.StartsAt.IsZero() {
+		cmd.StartsAt = time.Now()
+	}
+
+	if err := cmd.Validate(); err != nil {
+		return Error(400, err.Error(), err)
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		return Error(500, "Failed to create alert silence", err)
+	}
+
+	return JSON(200, cmd.Result)
+}
+
+// DELETE /api/alerts/silences/:silenceId
+func DeleteAlertSilence(c *m.ReqContext) Response {
+	cmd := m.DeleteAlertSilenceCommand{
+		OrgId: c.OrgId,
+		Id:    c.ParamsInt64(":silenceId"),
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		if err == m.ErrAlertSilenceNotFound {
+			return Error(404, "Alert silence not found", nil)
+		}
+		return Error(500, "Failed to delete alert silence", err)
+	}
+
+	return Success("Alert silence deleted")
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/alerting"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/tsdb"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func ValidateOrgAlert(c *m.ReqContext) {
@@ -118,6 +120,10 @@ func GetAlerts(c *m.ReqContext) Response {
 	}
 
 	for _, alert := range query.Result {
+		if alert.DashboardId == 0 {
+			alert.Url = m.GetAlertRuleUrl(alert.Id)
+			continue
+		}
 		alert.Url = m.GetDashboardUrl(alert.DashboardUid, alert.DashboardSlug)
 	}
 
@@ -170,6 +176,48 @@ func AlertTest(c *m.ReqContext, dto dtos.AlertTestCommand) Response {
 	return JSON(200, dtoRes)
 }
 
+// POST /api/alerts/backtest
+func AlertBacktest(c *m.ReqContext, dto dtos.AlertBacktestCommand) Response {
+	if _, idErr := dto.Dashboard.Get("id").Int64(); idErr != nil {
+		return Error(400, "The dashboard needs to be saved at least once before you can backtest an alert rule", nil)
+	}
+
+	if dto.To == "" {
+		dto.To = "now"
+	}
+
+	timeRange := tsdb.NewTimeRange(dto.From, dto.To)
+	from, err := timeRange.ParseFrom()
+	if err != nil {
+		return Error(400, "Invalid from time", err)
+	}
+	to, err := timeRange.ParseTo()
+	if err != nil {
+		return Error(400, "Invalid to time", err)
+	}
+
+	backendCmd := alerting.AlertBacktestCommand{
+		OrgId:     c.OrgId,
+		Dashboard: dto.Dashboard,
+		PanelId:   dto.PanelId,
+		User:      c.SignedInUser,
+		From:      from,
+		To:        to,
+	}
+
+	if err := bus.Dispatch(&backendCmd); err != nil {
+		if validationErr, ok := err.(alerting.ValidationError); ok {
+			return Error(422, validationErr.Error(), nil)
+		}
+		if err == m.ErrDataSourceAccessDenied {
+			return Error(403, "Access denied to datasource", err)
+		}
+		return Error(500, "Failed to backtest rule", err)
+	}
+
+	return JSON(200, backendCmd.Result)
+}
+
 // GET /api/alerts/:id
 func GetAlert(c *m.ReqContext) Response {
 	id := c.ParamsInt64(":alertId")
@@ -182,6 +230,82 @@ func GetAlert(c *m.ReqContext) Response {
 	return JSON(200, &query.Result)
 }
 
+// GET /api/alerts/:id/instances
+func GetAlertInstances(c *m.ReqContext) Response {
+	query := m.GetAlertInstancesQuery{
+		OrgId:   c.OrgId,
+		AlertId: c.ParamsInt64(":alertId"),
+		State:   c.QueryStrings("state"),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return Error(500, "List alert instances failed", err)
+	}
+
+	return JSON(200, query.Result)
+}
+
+// GET /api/alerts/:alertId/history
+func GetAlertHistory(c *m.ReqContext) Response {
+	perPage := c.QueryInt("perpage")
+	if perPage <= 0 {
+		perPage = 100
+	}
+
+	page := c.QueryInt("page")
+	if page < 1 {
+		page = 1
+	}
+
+	query := m.GetAlertHistoryQuery{
+		OrgId:   c.OrgId,
+		AlertId: c.ParamsInt64(":alertId"),
+		State:   c.QueryStrings("state"),
+		From:    c.QueryInt64("from"),
+		To:      c.QueryInt64("to"),
+		Page:    page,
+		Limit:   perPage,
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return Error(500, "List alert history failed", err)
+	}
+
+	query.Result.Page = page
+	query.Result.PerPage = perPage
+
+	return JSON(200, query.Result)
+}
+
+// GET /api/alerts/:alertId/escalations
+func GetAlertEscalations(c *m.ReqContext) Response {
+	query := m.GetAlertEscalationsQuery{OrgId: c.OrgId, AlertId: c.ParamsInt64(":alertId")}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return Error(500, "List alert escalations failed", err)
+	}
+
+	return JSON(200, query.Result)
+}
+
+// POST /api/alerts/:alertId/acknowledge
+// POST /api/alerts/:alertId/instances/:instanceId/acknowledge
+func AcknowledgeAlert(c *m.ReqContext, cmd m.AcknowledgeAlertCommand) Response {
+	cmd.OrgId = c.OrgId
+	cmd.AlertId = c.ParamsInt64(":alertId")
+	cmd.InstanceId = c.ParamsInt64(":instanceId")
+	cmd.UserId = c.UserId
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		if err == m.ErrAlertEscalationNotFound {
+			return Error(404, "No active escalation to acknowledge", err)
+		}
+		return Error(500, "Failed to acknowledge alert", err)
+	}
+
+	return Success("Alert acknowledged")
+}
+
 func GetAlertNotifiers(c *m.ReqContext) Response {
 	return JSON(200, alerting.GetNotifiers())
 }
@@ -218,6 +342,10 @@ func GetAlertNotificationByID(c *m.ReqContext) Response {
 func CreateAlertNotification(c *m.ReqContext, cmd m.CreateAlertNotificationCommand) Response {
 	cmd.OrgId = c.OrgId
 
+	if err := alerting.ValidateNotificationTemplates(cmd.Settings); err != nil {
+		return Error(400, err.Error(), err)
+	}
+
 	if err := bus.Dispatch(&cmd); err != nil {
 		return Error(500, "Failed to create alert notification", err)
 	}
@@ -228,6 +356,14 @@ func CreateAlertNotification(c *m.ReqContext, cmd m.CreateAlertNotificationComma
 func UpdateAlertNotification(c *m.ReqContext, cmd m.UpdateAlertNotificationCommand) Response {
 	cmd.OrgId = c.OrgId
 
+	if rsp := validateAlertNotificationEditable(c.OrgId, cmd.Id); rsp != nil {
+		return rsp
+	}
+
+	if err := alerting.ValidateNotificationTemplates(cmd.Settings); err != nil {
+		return Error(400, err.Error(), err)
+	}
+
 	if err := bus.Dispatch(&cmd); err != nil {
 		return Error(500, "Failed to update alert notification", err)
 	}
@@ -241,6 +377,10 @@ func DeleteAlertNotification(c *m.ReqContext) Response {
 		Id:    c.ParamsInt64("notificationId"),
 	}
 
+	if rsp := validateAlertNotificationEditable(c.OrgId, cmd.Id); rsp != nil {
+		return rsp
+	}
+
 	if err := bus.Dispatch(&cmd); err != nil {
 		return Error(500, "Failed to delete alert notification", err)
 	}
@@ -248,22 +388,52 @@ func DeleteAlertNotification(c *m.ReqContext) Response {
 	return Success("Notification deleted")
 }
 
+func validateAlertNotificationEditable(orgId int64, id int64) Response {
+	query := &m.GetAlertNotificationsQuery{OrgId: orgId, Id: id}
+	if err := bus.Dispatch(query); err != nil {
+		return Error(500, "Failed to get alert notification", err)
+	}
+
+	if query.Result == nil {
+		return Error(404, "Alert notification not found", nil)
+	}
+
+	if query.Result.ReadOnly {
+		return Error(403, "Cannot change read-only alert notification", m.ErrAlertNotificationIsReadOnly)
+	}
+
+	return nil
+}
+
 //POST /api/alert-notifications/test
 func NotificationTest(c *m.ReqContext, dto dtos.NotificationTestCommand) Response {
 	cmd := &alerting.NotificationTestCommand{
 		Name:     dto.Name,
 		Type:     dto.Type,
 		Settings: dto.Settings,
+		Preview:  dto.Preview,
 	}
 
 	if err := bus.Dispatch(cmd); err != nil {
 		if err == m.ErrSmtpNotEnabled {
 			return Error(412, err.Error(), err)
 		}
+		if _, ok := err.(alerting.ValidationError); ok {
+			return Error(400, err.Error(), err)
+		}
 		return Error(500, "Failed to send alert notifications", err)
 	}
 
-	return Success("Test notification sent")
+	message := "Test notification sent"
+	if cmd.Preview {
+		message = "Test notification rendered"
+	}
+
+	return JSON(200, util.DynMap{
+		"message": message,
+		"title":   cmd.Title,
+		"body":    cmd.Body,
+	})
 }
 
 //POST /api/alerts/:alertId/pause
@@ -276,7 +446,13 @@ func PauseAlert(c *m.ReqContext, dto dtos.PauseAlertCommand) Response {
 		return Error(500, "Get Alert failed", err)
 	}
 
-	guardian := guardian.New(query.Result.DashboardId, c.OrgId, c.SignedInUser)
+	// standalone alert rules are protected by the permissions of their folder
+	dashboardID := query.Result.DashboardId
+	if query.Result.IsStandalone() {
+		dashboardID = query.Result.FolderId
+	}
+
+	guardian := guardian.New(dashboardID, c.OrgId, c.SignedInUser)
 	if canEdit, err := guardian.CanEdit(); err != nil || !canEdit {
 		if err != nil {
 			return Error(500, "Error while checking permissions for Alert", err)
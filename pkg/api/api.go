@@ -330,10 +330,31 @@ func (hs *HTTPServer) registerRoutes() {
 
 		apiRoute.Group("/alerts", func(alertsRoute routing.RouteRegister) {
 			alertsRoute.Post("/test", bind(dtos.AlertTestCommand{}), Wrap(AlertTest))
+			alertsRoute.Post("/backtest", bind(dtos.AlertBacktestCommand{}), Wrap(AlertBacktest))
+			alertsRoute.Post("/receive", reqEditorRole, bind(dtos.ReceivedAlertsPayload{}), Wrap(ReceiveAlerts))
+			alertsRoute.Post("/receive/alertmanager", reqEditorRole, bind(dtos.AlertmanagerWebhookPayload{}), Wrap(ReceiveAlertmanagerAlerts))
+			alertsRoute.Get("/received", Wrap(GetReceivedAlerts))
 			alertsRoute.Post("/:alertId/pause", reqEditorRole, bind(dtos.PauseAlertCommand{}), Wrap(PauseAlert))
 			alertsRoute.Get("/:alertId", ValidateOrgAlert, Wrap(GetAlert))
+			alertsRoute.Get("/:alertId/instances", ValidateOrgAlert, Wrap(GetAlertInstances))
+			alertsRoute.Get("/:alertId/history", ValidateOrgAlert, Wrap(GetAlertHistory))
+			alertsRoute.Get("/:alertId/escalations", ValidateOrgAlert, Wrap(GetAlertEscalations))
+			alertsRoute.Post("/:alertId/acknowledge", reqEditorRole, ValidateOrgAlert, bind(m.AcknowledgeAlertCommand{}), Wrap(AcknowledgeAlert))
+			alertsRoute.Post("/:alertId/instances/:instanceId/acknowledge", reqEditorRole, ValidateOrgAlert, bind(m.AcknowledgeAlertCommand{}), Wrap(AcknowledgeAlert))
 			alertsRoute.Get("/", Wrap(GetAlerts))
 			alertsRoute.Get("/states-for-dashboard", Wrap(GetAlertStatesForDashboard))
+			alertsRoute.Get("/silences", Wrap(GetAlertSilences))
+			alertsRoute.Post("/silences", reqEditorRole, bind(m.CreateAlertSilenceCommand{}), Wrap(CreateAlertSilence))
+			alertsRoute.Get("/silences/:silenceId", Wrap(GetAlertSilence))
+			alertsRoute.Delete("/silences/:silenceId", reqEditorRole, Wrap(DeleteAlertSilence))
+		})
+
+		apiRoute.Group("/alert-rules", func(alertRulesRoute routing.RouteRegister) {
+			alertRulesRoute.Get("/", Wrap(GetAlertRules))
+			alertRulesRoute.Post("/", reqEditorRole, bind(dtos.SaveAlertRuleCommand{}), Wrap(CreateAlertRule))
+			alertRulesRoute.Get("/:alertId", Wrap(GetAlertRule))
+			alertRulesRoute.Put("/:alertId", reqEditorRole, bind(dtos.SaveAlertRuleCommand{}), Wrap(UpdateAlertRule))
+			alertRulesRoute.Delete("/:alertId", reqEditorRole, Wrap(DeleteAlertRule))
 		})
 
 		apiRoute.Get("/alert-notifications", Wrap(GetAlertNotifications))
@@ -341,6 +362,10 @@ func (hs *HTTPServer) registerRoutes() {
 
 		apiRoute.Group("/alert-notifications", func(alertNotifications routing.RouteRegister) {
 			alertNotifications.Post("/test", bind(dtos.NotificationTestCommand{}), Wrap(NotificationTest))
+			alertNotifications.Get("/deliveries", Wrap(GetNotificationDeliveries))
+			alertNotifications.Post("/deliveries/:deliveryId/retry", Wrap(RetryNotificationDelivery))
+			alertNotifications.Delete("/deliveries/:deliveryId", Wrap(DeleteNotificationDelivery))
+			alertNotifications.Get("/:notificationId/deliveries", Wrap(GetNotificationDeliveries))
 			alertNotifications.Post("/", bind(m.CreateAlertNotificationCommand{}), Wrap(CreateAlertNotification))
 			alertNotifications.Put("/:notificationId", bind(m.UpdateAlertNotificationCommand{}), Wrap(UpdateAlertNotification))
 			alertNotifications.Get("/:notificationId", Wrap(GetAlertNotificationByID))
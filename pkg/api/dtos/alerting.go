@@ -50,6 +50,7 @@ func formatShort(interval time.Duration) string {
 func NewAlertNotification(notification *models.AlertNotification) *AlertNotification {
 	return &AlertNotification{
 		Id:                    notification.Id,
+		Uid:                   notification.Uid,
 		Name:                  notification.Name,
 		Type:                  notification.Type,
 		IsDefault:             notification.IsDefault,
@@ -59,11 +60,13 @@ func NewAlertNotification(notification *models.AlertNotification) *AlertNotifica
 		SendReminder:          notification.SendReminder,
 		DisableResolveMessage: notification.DisableResolveMessage,
 		Settings:              notification.Settings,
+		ReadOnly:              notification.ReadOnly,
 	}
 }
 
 type AlertNotification struct {
 	Id                    int64            `json:"id"`
+	Uid                   string           `json:"uid"`
 	Name                  string           `json:"name"`
 	Type                  string           `json:"type"`
 	IsDefault             bool             `json:"isDefault"`
@@ -73,6 +76,7 @@ type AlertNotification struct {
 	Created               time.Time        `json:"created"`
 	Updated               time.Time        `json:"updated"`
 	Settings              *simplejson.Json `json:"settings"`
+	ReadOnly              bool             `json:"readOnly"`
 }
 
 type AlertTestCommand struct {
@@ -80,6 +84,13 @@ type AlertTestCommand struct {
 	PanelId   int64            `json:"panelId" binding:"Required"`
 }
 
+type AlertBacktestCommand struct {
+	Dashboard *simplejson.Json `json:"dashboard" binding:"Required"`
+	PanelId   int64            `json:"panelId" binding:"Required"`
+	From      string           `json:"from" binding:"Required"`
+	To        string           `json:"to"`
+}
+
 type AlertTestResult struct {
 	Firing         bool                  `json:"firing"`
 	State          models.AlertStateType `json:"state"`
@@ -108,6 +119,7 @@ type NotificationTestCommand struct {
 	DisableResolveMessage bool             `json:"disableResolveMessage"`
 	Frequency             string           `json:"frequency"`
 	Settings              *simplejson.Json `json:"settings"`
+	Preview               bool             `json:"preview"`
 }
 
 type PauseAlertCommand struct {
@@ -118,3 +130,51 @@ type PauseAlertCommand struct {
 type PauseAllAlertsCommand struct {
 	Paused bool `json:"paused"`
 }
+
+// SaveAlertRuleCommand creates or updates a standalone alert rule. Settings
+// hold the rest of the alert json a graph panel would have, like the
+// conditions, notifications and no data handling.
+type SaveAlertRuleCommand struct {
+	FolderId  int64            `json:"folderId"`
+	Name      string           `json:"name" binding:"Required"`
+	Message   string           `json:"message"`
+	Frequency string           `json:"frequency" binding:"Required"`
+	For       string           `json:"for"`
+	Settings  *simplejson.Json `json:"settings" binding:"Required"`
+}
+
+// AlertmanagerWebhookPayload is the payload sent by the webhook receiver of
+// a Prometheus Alertmanager.
+type AlertmanagerWebhookPayload struct {
+	Version           string               `json:"version"`
+	GroupKey          string               `json:"groupKey"`
+	Status            string               `json:"status"`
+	Receiver          string               `json:"receiver"`
+	GroupLabels       map[string]string    `json:"groupLabels"`
+	CommonLabels      map[string]string    `json:"commonLabels"`
+	CommonAnnotations map[string]string    `json:"commonAnnotations"`
+	ExternalURL       string               `json:"externalURL"`
+	Alerts            []*AlertmanagerAlert `json:"alerts"`
+}
+
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+}
+
+type ReceivedAlertsPayload struct {
+	Alerts []*ReceivedAlert `json:"alerts" binding:"Required"`
+}
+
+type ReceivedAlert struct {
+	Title   string                `json:"title"`
+	Message string                `json:"message"`
+	State   string                `json:"state"`
+	Labels  map[string]string     `json:"labels"`
+	Values  map[string]null.Float `json:"values"`
+	Time    int64                 `json:"time"`
+}
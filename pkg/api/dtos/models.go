@@ -41,9 +41,10 @@ type CurrentUser struct {
 }
 
 type MetricRequest struct {
-	From    string             `json:"from"`
-	To      string             `json:"to"`
-	Queries []*simplejson.Json `json:"queries"`
+	From            string             `json:"from"`
+	To              string             `json:"to"`
+	Queries         []*simplejson.Json `json:"queries"`
+	Transformations []interface{}      `json:"transformations"`
 }
 
 type UserStars struct {
@@ -33,7 +33,12 @@ func (hs *HTTPServer) QueryMetrics(c *m.ReqContext, reqDto dtos.MetricRequest) R
 		return Error(500, "Unable to load datasource meta data", err)
 	}
 
-	request := &tsdb.TsdbQuery{TimeRange: timeRange}
+	transformations, err := tsdb.NewTransformations(reqDto.Transformations)
+	if err != nil {
+		return Error(400, err.Error(), err)
+	}
+
+	request := &tsdb.TsdbQuery{TimeRange: timeRange, SkipCache: c.SkipCache, Transformations: transformations}
 
 	for _, query := range reqDto.Queries {
 		request.Queries = append(request.Queries, &tsdb.Query{
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// GET /api/alert-notifications/deliveries
+// GET /api/alert-notifications/:notificationId/deliveries
+func GetNotificationDeliveries(c *m.ReqContext) Response {
+	query := &m.GetNotificationDeliveriesQuery{
+		OrgId:      c.OrgId,
+		NotifierId: c.QueryInt64("notifierId"),
+		States:     make([]m.NotificationDeliveryState, 0),
+		Limit:      c.QueryInt("limit"),
+	}
+
+	if notifierId := c.ParamsInt64(":notificationId"); notifierId != 0 {
+		query.NotifierId = notifierId
+	}
+
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+
+	for _, state := range c.QueryStrings("state") {
+		deliveryState := m.NotificationDeliveryState(state)
+		if !deliveryState.IsValid() {
+			return Error(400, "Invalid notification delivery state "+state, nil)
+		}
+		query.States = append(query.States, deliveryState)
+	}
+
+	if err := bus.Dispatch(query); err != nil {
+		return Error(500, "Failed to get notification deliveries", err)
+	}
+
+	return JSON(200, query.Result)
+}
+
+// POST /api/alert-notifications/deliveries/:deliveryId/retry
+func RetryNotificationDelivery(c *m.ReqContext) Response {
+	cmd := &m.RetryNotificationDeliveryCommand{
+		OrgId: c.OrgId,
+		Id:    c.ParamsInt64(":deliveryId"),
+	}
+
+	if err := bus.Dispatch(cmd); err != nil {
+		if err == m.ErrNotificationDeliveryNotFound {
+			return Error(404, "Failed notification delivery not found", err)
+		}
+		return Error(500, "Failed to retry notification delivery", err)
+	}
+
+	return Success("Notification delivery queued for retry")
+}
+
+// DELETE /api/alert-notifications/deliveries/:deliveryId
+func DeleteNotificationDelivery(c *m.ReqContext) Response {
+	query := &m.GetNotificationDeliveryQuery{
+		OrgId: c.OrgId,
+		Id:    c.ParamsInt64(":deliveryId"),
+	}
+
+	if err := bus.Dispatch(query); err != nil {
+		if err == m.ErrNotificationDeliveryNotFound {
+			return Error(404, "Notification delivery not found", err)
+		}
+		return Error(500, "Failed to get notification delivery", err)
+	}
+
+	cmd := &m.DeleteNotificationDeliveryCommand{OrgId: c.OrgId, Id: query.Id}
+	if err := bus.Dispatch(cmd); err != nil {
+		return Error(500, "Failed to delete notification delivery", err)
+	}
+
+	return Success("Notification delivery deleted")
+}
@@ -42,22 +42,29 @@ var (
 	M_Api_Dashboard_Insert               prometheus.Counter
 	M_Alerting_Result_State              *prometheus.CounterVec
 	M_Alerting_Notification_Sent         *prometheus.CounterVec
+	M_Alerting_Skipped_Evaluations       prometheus.Counter
+	M_Alerting_Notification_Failed       *prometheus.CounterVec
+	M_Alerting_Notification_Dead_Letters *prometheus.CounterVec
 	M_Aws_CloudWatch_GetMetricStatistics prometheus.Counter
 	M_Aws_CloudWatch_ListMetrics         prometheus.Counter
 	M_Aws_CloudWatch_GetMetricData       prometheus.Counter
 	M_DB_DataSource_QueryById            prometheus.Counter
+	M_DataSource_Query_Cache             *prometheus.CounterVec
 
 	// Timers
-	M_DataSource_ProxyReq_Timer prometheus.Summary
-	M_Alerting_Execution_Time   prometheus.Summary
+	M_DataSource_ProxyReq_Timer     prometheus.Summary
+	M_Alerting_Execution_Time       prometheus.Summary
+	M_Alerting_Notification_Latency *prometheus.SummaryVec
 
 	// StatTotals
-	M_Alerting_Active_Alerts prometheus.Gauge
-	M_StatTotal_Dashboards   prometheus.Gauge
-	M_StatTotal_Users        prometheus.Gauge
-	M_StatActive_Users       prometheus.Gauge
-	M_StatTotal_Orgs         prometheus.Gauge
-	M_StatTotal_Playlists    prometheus.Gauge
+	M_Alerting_Active_Alerts   prometheus.Gauge
+	M_Alerting_Cluster_Servers prometheus.Gauge
+	M_Alerting_Queue_Depth     prometheus.Gauge
+	M_StatTotal_Dashboards     prometheus.Gauge
+	M_StatTotal_Users          prometheus.Gauge
+	M_StatActive_Users         prometheus.Gauge
+	M_StatTotal_Orgs           prometheus.Gauge
+	M_StatTotal_Playlists      prometheus.Gauge
 
 	// M_Grafana_Version is a gauge that contains build info about this binary
 	//
@@ -226,6 +233,24 @@ func init() {
 		Namespace: exporterName,
 	}, []string{"type"})
 
+	M_Alerting_Skipped_Evaluations = newCounterStartingAtZero(prometheus.CounterOpts{
+		Name:      "alerting_skipped_evaluations_total",
+		Help:      "counter for how many alert evaluations were skipped because the evaluation queue was full",
+		Namespace: exporterName,
+	})
+
+	M_Alerting_Notification_Failed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "alerting_notification_failed_total",
+		Help:      "counter for how many attempts to send alert notifications failed",
+		Namespace: exporterName,
+	}, []string{"type"})
+
+	M_Alerting_Notification_Dead_Letters = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "alerting_notification_dead_letters_total",
+		Help:      "counter for how many alert notifications were given up after failing",
+		Namespace: exporterName,
+	}, []string{"type"})
+
 	M_Aws_CloudWatch_GetMetricStatistics = newCounterStartingAtZero(prometheus.CounterOpts{
 		Name:      "aws_cloudwatch_get_metric_statistics_total",
 		Help:      "counter for getting metric statistics from aws",
@@ -250,6 +275,12 @@ func init() {
 		Namespace: exporterName,
 	})
 
+	M_DataSource_Query_Cache = newCounterVecStartingAtZero(prometheus.CounterOpts{
+		Name:      "datasource_query_cache_total",
+		Help:      "counter for data source queries answered from the query cache (hit) or by the data source (miss)",
+		Namespace: exporterName,
+	}, []string{"result"}, "hit", "miss")
+
 	M_DataSource_ProxyReq_Timer = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name:      "api_dataproxy_request_all_milliseconds",
 		Help:      "summary for dataproxy request duration",
@@ -262,12 +293,30 @@ func init() {
 		Namespace: exporterName,
 	})
 
+	M_Alerting_Notification_Latency = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name:      "alerting_notification_latency_milliseconds",
+		Help:      "summary of the time from the first attempt to send an alert notification until it was delivered",
+		Namespace: exporterName,
+	}, []string{"type"})
+
 	M_Alerting_Active_Alerts = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name:      "alerting_active_alerts",
 		Help:      "amount of active alerts",
 		Namespace: exporterName,
 	})
 
+	M_Alerting_Cluster_Servers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "alerting_cluster_servers",
+		Help:      "amount of servers sharing the evaluation of alert rules",
+		Namespace: exporterName,
+	})
+
+	M_Alerting_Queue_Depth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "alerting_evaluation_queue_depth",
+		Help:      "amount of alert evaluations waiting for a free worker",
+		Namespace: exporterName,
+	})
+
 	M_StatTotal_Dashboards = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name:      "stat_totals_dashboard",
 		Help:      "total amount of dashboards",
@@ -347,11 +396,18 @@ func initMetricVars() {
 		M_Api_Dashboard_Insert,
 		M_Alerting_Result_State,
 		M_Alerting_Notification_Sent,
+		M_Alerting_Skipped_Evaluations,
+		M_Alerting_Notification_Failed,
+		M_Alerting_Notification_Dead_Letters,
+		M_Alerting_Notification_Latency,
 		M_Aws_CloudWatch_GetMetricStatistics,
 		M_Aws_CloudWatch_ListMetrics,
 		M_Aws_CloudWatch_GetMetricData,
 		M_DB_DataSource_QueryById,
+		M_DataSource_Query_Cache,
 		M_Alerting_Active_Alerts,
+		M_Alerting_Cluster_Servers,
+		M_Alerting_Queue_Depth,
 		M_StatTotal_Dashboards,
 		M_StatTotal_Users,
 		M_StatActive_Users,
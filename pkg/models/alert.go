@@ -1,11 +1,13 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 type AlertStateType string
@@ -37,6 +39,9 @@ const (
 var (
 	ErrCannotChangeStateOnPausedAlert = fmt.Errorf("Cannot change state on pause alert")
 	ErrRequiresNewState               = fmt.Errorf("update alert state requires a new state.")
+	ErrAlertRuleNotFound              = errors.New("Alert rule not found")
+	ErrAlertNotFound                  = errors.New("could not find alert")
+	ErrAlertRuleIsDashboardAlert      = errors.New("Alert rule is part of a dashboard, edit it in the dashboard")
 )
 
 func (s AlertStateType) IsValid() bool {
@@ -70,6 +75,7 @@ type Alert struct {
 	OrgId          int64
 	DashboardId    int64
 	PanelId        int64
+	FolderId       int64
 	Name           string
 	Message        string
 	Severity       string //Unused
@@ -94,6 +100,22 @@ func (alert *Alert) ValidToSave() bool {
 	return alert.DashboardId != 0 && alert.OrgId != 0 && alert.PanelId != 0
 }
 
+// IsStandalone returns true for alert rules that are not part of a
+// dashboard panel but placed in a folder of their own.
+func (alert *Alert) IsStandalone() bool {
+	return alert.DashboardId == 0
+}
+
+// GetAlertRuleUrl returns the html url for a standalone alert rule
+func GetAlertRuleUrl(id int64) string {
+	return fmt.Sprintf("%s/alerting/list?ruleId=%d", setting.AppSubUrl, id)
+}
+
+// GetFullAlertRuleUrl returns the full url for a standalone alert rule
+func GetFullAlertRuleUrl(id int64) string {
+	return fmt.Sprintf("%salerting/list?ruleId=%d", setting.AppUrl, id)
+}
+
 func (alert *Alert) ShouldUpdateState(newState AlertStateType) bool {
 	return alert.State != newState
 }
@@ -144,6 +166,26 @@ type SaveAlertsCommand struct {
 	Alerts []*Alert
 }
 
+// SaveAlertRuleCommand creates a standalone alert rule, or updates it when
+// the id is set.
+type SaveAlertRuleCommand struct {
+	Id        int64
+	OrgId     int64
+	FolderId  int64
+	Name      string
+	Message   string
+	Frequency int64
+	For       time.Duration
+	Settings  *simplejson.Json
+
+	Result *Alert
+}
+
+type DeleteAlertRuleCommand struct {
+	Id    int64
+	OrgId int64
+}
+
 type PauseAlertCommand struct {
 	OrgId       int64
 	AlertIds    []int64
@@ -176,6 +218,11 @@ type GetAlertsQuery struct {
 	Query        string
 	User         *SignedInUser
 
+	// Standalone limits the result to alert rules that are not part of a
+	// dashboard, FolderIDs filters these by folder.
+	Standalone bool
+	FolderIDs  []int64
+
 	Result []*AlertListItemDTO
 }
 
@@ -202,6 +249,7 @@ type AlertListItemDTO struct {
 	DashboardUid   string           `json:"dashboardUid"`
 	DashboardSlug  string           `json:"dashboardSlug"`
 	PanelId        int64            `json:"panelId"`
+	FolderId       int64            `json:"folderId"`
 	Name           string           `json:"name"`
 	State          AlertStateType   `json:"state"`
 	NewStateDate   time.Time        `json:"newStateDate"`
@@ -227,6 +275,16 @@ type UpdateDashboardAlertsCommand struct {
 	User      *SignedInUser
 }
 
+// ValidateAlertRuleCommand looks up the datasources of the queries of a
+// standalone alert rule and validates its settings.
+type ValidateAlertRuleCommand struct {
+	OrgId    int64
+	User     *SignedInUser
+	Settings *simplejson.Json
+
+	Result *Alert
+}
+
 type ValidateDashboardAlertsCommand struct {
 	UserId    int64
 	OrgId     int64
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"errors"
+)
+
+var (
+	ErrAlertEscalationNotFound = errors.New("No active escalation found for alert")
+	ErrAlertEscalationConflict = errors.New("Alert escalation was advanced by another server")
+)
+
+// AlertEscalationState tracks how far an escalation chain has advanced for
+// an alert or an instance of a multi-dimensional alert. Step is the index
+// of the last notified step, -1 before the first one. Times are epoch
+// seconds and NextStepAt is 0 when no steps are left.
+type AlertEscalationState struct {
+	Id             int64  `json:"id"`
+	OrgId          int64  `json:"-"`
+	AlertId        int64  `json:"alertId"`
+	InstanceId     int64  `json:"instanceId"`
+	NotifierId     int64  `json:"notifierId"`
+	Step           int    `json:"step"`
+	StartedAt      int64  `json:"startedAt"`
+	NextStepAt     int64  `json:"nextStepAt"`
+	AcknowledgedAt int64  `json:"acknowledgedAt"`
+	AcknowledgedBy int64  `json:"acknowledgedBy"`
+	Comment        string `json:"comment"`
+}
+
+func (s *AlertEscalationState) IsAcknowledged() bool {
+	return s.AcknowledgedAt > 0
+}
+
+// StartAlertEscalationCommand creates the escalation state or restarts it
+// from the first step, clearing a previous acknowledgement.
+type StartAlertEscalationCommand struct {
+	OrgId      int64
+	AlertId    int64
+	InstanceId int64
+	NotifierId int64
+	NextStepAt int64
+
+	Result *AlertEscalationState
+}
+
+// AdvanceAlertEscalationCommand moves an escalation from Step to NextStep.
+// It fails with ErrAlertEscalationConflict when the state is no longer at
+// Step or was acknowledged in the meantime.
+type AdvanceAlertEscalationCommand struct {
+	Id         int64
+	Step       int
+	NextStep   int
+	NextStepAt int64
+}
+
+type DeleteAlertEscalationCommand struct {
+	OrgId      int64
+	AlertId    int64
+	InstanceId int64
+	NotifierId int64
+}
+
+type AcknowledgeAlertCommand struct {
+	OrgId      int64  `json:"-"`
+	AlertId    int64  `json:"-"`
+	InstanceId int64  `json:"-"`
+	UserId     int64  `json:"-"`
+	Comment    string `json:"comment"`
+}
+
+type GetAlertEscalationQuery struct {
+	OrgId      int64
+	AlertId    int64
+	InstanceId int64
+	NotifierId int64
+
+	Result *AlertEscalationState
+}
+
+type GetAlertEscalationsQuery struct {
+	OrgId   int64
+	AlertId int64
+
+	Result []*AlertEscalationState
+}
+
+// GetDueAlertEscalationsQuery returns the unacknowledged escalations whose
+// next step is due at Now.
+type GetDueAlertEscalationsQuery struct {
+	Now int64
+
+	Result []*AlertEscalationState
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// AlertHeartbeat marks a server that evaluates alert rules as alive.
+// Created and Updated are epoch seconds.
+type AlertHeartbeat struct {
+	Id       int64
+	ServerId string
+	Created  int64
+	Updated  int64
+}
+
+type AlertHeartbeatCommand struct {
+	ServerId string
+}
+
+type DeleteAlertHeartbeatCommand struct {
+	ServerId string
+}
+
+type DeleteStaleAlertHeartbeatsCommand struct {
+	OlderThan   time.Time
+	DeletedRows int64
+}
+
+// GetActiveAlertServersQuery returns the ids of the servers with a
+// heartbeat after Since, sorted by id.
+type GetActiveAlertServersQuery struct {
+	Since time.Time
+
+	Result []string
+}
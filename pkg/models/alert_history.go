@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// AlertHistory is a single state transition of an alert rule or of one
+// instance of a multi-dimensional rule, together with the evaluation
+// that caused it.
+type AlertHistory struct {
+	Id         int64             `json:"id"`
+	OrgId      int64             `json:"-"`
+	AlertId    int64             `json:"alertId"`
+	InstanceId int64             `json:"instanceId"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	PrevState  AlertStateType    `json:"prevState"`
+	NewState   AlertStateType    `json:"newState"`
+	EvalData   *simplejson.Json  `json:"evalData"`
+	Error      string            `json:"error"`
+	DurationMs int64             `json:"durationMs"`
+	Epoch      int64             `json:"epoch"`
+}
+
+type SaveAlertHistoryCommand struct {
+	OrgId      int64
+	AlertId    int64
+	InstanceId int64
+	Labels     map[string]string
+	PrevState  AlertStateType
+	NewState   AlertStateType
+	EvalData   *simplejson.Json
+	Error      string
+	Duration   time.Duration
+	Time       time.Time
+
+	Result *AlertHistory
+}
+
+// DeleteExpiredAlertHistoryCommand removes history entries older than
+// OlderThan.
+type DeleteExpiredAlertHistoryCommand struct {
+	OlderThan time.Time
+
+	DeletedRows int64
+}
+
+// GetAlertHistoryQuery returns the state transitions of an alert, newest
+// first. From and To are epoch milliseconds and optional.
+type GetAlertHistoryQuery struct {
+	OrgId   int64
+	AlertId int64
+	State   []string
+	From    int64
+	To      int64
+	Page    int
+	Limit   int
+
+	Result AlertHistoryQueryResult
+}
+
+type AlertHistoryQueryResult struct {
+	TotalCount int64           `json:"totalCount"`
+	History    []*AlertHistory `json:"history"`
+	Page       int             `json:"page"`
+	PerPage    int             `json:"perPage"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// AlertInstance is the persisted state of a single series (label set)
+// of a multi-dimensional alert rule.
+type AlertInstance struct {
+	Id           int64             `json:"id"`
+	OrgId        int64             `json:"-"`
+	AlertId      int64             `json:"alertId"`
+	DashboardId  int64             `json:"dashboardId"`
+	Labels       map[string]string `json:"labels"`
+	LabelsHash   string            `json:"-"`
+	State        AlertStateType    `json:"state"`
+	EvalData     *simplejson.Json  `json:"evalData"`
+	NewStateDate time.Time         `json:"newStateDate"`
+	StateChanges int64             `json:"stateChanges"`
+
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// AlertInstanceLabelsHash returns a stable identifier for a label set.
+func AlertInstanceLabelsHash(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+
+	hash := sha1.Sum([]byte(strings.Join(pairs, "\xff")))
+	return hex.EncodeToString(hash[:])
+}
+
+type SetAlertInstanceStateCommand struct {
+	AlertId     int64
+	OrgId       int64
+	DashboardId int64
+	Labels      map[string]string
+	State       AlertStateType
+	EvalData    *simplejson.Json
+
+	Result AlertInstance
+}
+
+type DeleteAlertInstanceCommand struct {
+	Id      int64
+	AlertId int64
+	OrgId   int64
+}
+
+type GetAlertInstancesQuery struct {
+	Id          int64
+	AlertId     int64
+	OrgId       int64
+	DashboardId int64
+	LabelsHash  string
+	State       []string
+
+	Result []*AlertInstance
+}
@@ -12,6 +12,7 @@ var (
 	ErrAlertNotificationStateNotFound        = errors.New("alert notification state not found")
 	ErrAlertNotificationStateVersionConflict = errors.New("alert notification state update version conflict")
 	ErrAlertNotificationStateAlreadyExist    = errors.New("alert notification state already exists.")
+	ErrAlertNotificationIsReadOnly           = errors.New("Alert notification is readonly. Can only be updated from configuration.")
 )
 
 type AlertNotificationStateType string
@@ -20,10 +21,12 @@ var (
 	AlertNotificationStatePending   = AlertNotificationStateType("pending")
 	AlertNotificationStateCompleted = AlertNotificationStateType("completed")
 	AlertNotificationStateUnknown   = AlertNotificationStateType("unknown")
+	AlertNotificationStateSilenced  = AlertNotificationStateType("silenced")
 )
 
 type AlertNotification struct {
 	Id                    int64            `json:"id"`
+	Uid                   string           `json:"uid"`
 	OrgId                 int64            `json:"-"`
 	Name                  string           `json:"name"`
 	Type                  string           `json:"type"`
@@ -32,11 +35,13 @@ type AlertNotification struct {
 	Frequency             time.Duration    `json:"frequency"`
 	IsDefault             bool             `json:"isDefault"`
 	Settings              *simplejson.Json `json:"settings"`
+	ReadOnly              bool             `json:"readOnly"`
 	Created               time.Time        `json:"created"`
 	Updated               time.Time        `json:"updated"`
 }
 
 type CreateAlertNotificationCommand struct {
+	Uid                   string           `json:"uid"`
 	Name                  string           `json:"name"  binding:"Required"`
 	Type                  string           `json:"type"  binding:"Required"`
 	SendReminder          bool             `json:"sendReminder"`
@@ -45,12 +50,14 @@ type CreateAlertNotificationCommand struct {
 	IsDefault             bool             `json:"isDefault"`
 	Settings              *simplejson.Json `json:"settings"`
 
-	OrgId  int64 `json:"-"`
-	Result *AlertNotification
+	OrgId    int64 `json:"-"`
+	ReadOnly bool  `json:"-"`
+	Result   *AlertNotification
 }
 
 type UpdateAlertNotificationCommand struct {
 	Id                    int64            `json:"id"  binding:"Required"`
+	Uid                   string           `json:"uid"`
 	Name                  string           `json:"name"  binding:"Required"`
 	Type                  string           `json:"type"  binding:"Required"`
 	SendReminder          bool             `json:"sendReminder"`
@@ -59,8 +66,9 @@ type UpdateAlertNotificationCommand struct {
 	IsDefault             bool             `json:"isDefault"`
 	Settings              *simplejson.Json `json:"settings"  binding:"Required"`
 
-	OrgId  int64 `json:"-"`
-	Result *AlertNotification
+	OrgId    int64 `json:"-"`
+	ReadOnly bool  `json:"-"`
+	Result   *AlertNotification
 }
 
 type DeleteAlertNotificationCommand struct {
@@ -76,6 +84,13 @@ type GetAlertNotificationsQuery struct {
 	Result *AlertNotification
 }
 
+type GetAlertNotificationsWithUidQuery struct {
+	Uid   string
+	OrgId int64
+
+	Result *AlertNotification
+}
+
 type GetAlertNotificationsToSendQuery struct {
 	Ids   []int64
 	OrgId int64
@@ -93,11 +108,13 @@ type AlertNotificationState struct {
 	Id                           int64
 	OrgId                        int64
 	AlertId                      int64
+	InstanceId                   int64
 	NotifierId                   int64
 	State                        AlertNotificationStateType
 	Version                      int64
 	UpdatedAt                    int64
 	AlertRuleStateUpdatedVersion int64
+	ExternalId                   string
 }
 
 type SetAlertNotificationStateToPendingCommand struct {
@@ -105,6 +122,10 @@ type SetAlertNotificationStateToPendingCommand struct {
 	AlertRuleStateUpdatedVersion int64
 	Version                      int64
 
+	// Silenced marks the notification as held back by a silence instead of
+	// being sent, it is sent once the silence has ended
+	Silenced bool
+
 	ResultVersion int64
 }
 
@@ -113,9 +134,18 @@ type SetAlertNotificationStateToCompleteCommand struct {
 	Version int64
 }
 
+// SetAlertNotificationStateExternalIdCommand stores the id of the ticket or
+// message a notifier created for the alert, e.g. a Jira issue key. It does
+// not change the version of the notification state.
+type SetAlertNotificationStateExternalIdCommand struct {
+	Id         int64
+	ExternalId string
+}
+
 type GetOrCreateNotificationStateQuery struct {
 	OrgId      int64
 	AlertId    int64
+	InstanceId int64
 	NotifierId int64
 
 	Result *AlertNotificationState
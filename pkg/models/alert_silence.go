@@ -0,0 +1,151 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var (
+	ErrAlertSilenceNotFound         = errors.New("Alert silence not found")
+	ErrAlertSilenceInvalidTimeRange = errors.New("Alert silence must end after it starts")
+	ErrAlertSilenceMissingMatchers  = errors.New("Alert silence requires at least one matcher")
+)
+
+type AlertSilenceMatcherType string
+
+const (
+	AlertSilenceMatchDashboard AlertSilenceMatcherType = "dashboard"
+	AlertSilenceMatchPanel     AlertSilenceMatcherType = "panel"
+	AlertSilenceMatchRule      AlertSilenceMatcherType = "rule"
+	AlertSilenceMatchTag       AlertSilenceMatcherType = "tag"
+	AlertSilenceMatchLabel     AlertSilenceMatcherType = "label"
+)
+
+func (t AlertSilenceMatcherType) IsValid() bool {
+	switch t {
+	case AlertSilenceMatchDashboard, AlertSilenceMatchPanel, AlertSilenceMatchRule, AlertSilenceMatchTag, AlertSilenceMatchLabel:
+		return true
+	}
+	return false
+}
+
+// AlertSilenceMatcher matches a single property of an alert. Dashboard
+// matchers compare the dashboard uid, tag matchers any of the dashboard
+// tags and label matchers the series label with the given name.
+type AlertSilenceMatcher struct {
+	Type    AlertSilenceMatcherType `json:"type"`
+	Name    string                  `json:"name,omitempty"`
+	Value   string                  `json:"value"`
+	IsRegex bool                    `json:"isRegex"`
+}
+
+func (m *AlertSilenceMatcher) Validate() error {
+	if !m.Type.IsValid() {
+		return fmt.Errorf("Invalid alert silence matcher type %q", m.Type)
+	}
+
+	if m.Type == AlertSilenceMatchLabel && m.Name == "" {
+		return errors.New("Alert silence label matcher requires a name")
+	}
+
+	if m.IsRegex {
+		if _, err := regexp.Compile(m.Value); err != nil {
+			return fmt.Errorf("Invalid alert silence matcher regex %q: %v", m.Value, err)
+		}
+	}
+
+	return nil
+}
+
+// MatchValue returns true if value satisfies the matcher. Regular
+// expressions are anchored so they have to match the whole value.
+func (m *AlertSilenceMatcher) MatchValue(value string) bool {
+	if !m.IsRegex {
+		return m.Value == value
+	}
+
+	re, err := regexp.Compile("^(?:" + m.Value + ")$")
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(value)
+}
+
+type AlertSilence struct {
+	Id        int64                  `json:"id"`
+	OrgId     int64                  `json:"-"`
+	Comment   string                 `json:"comment"`
+	CreatedBy int64                  `json:"createdBy"`
+	StartsAt  time.Time              `json:"startsAt"`
+	EndsAt    time.Time              `json:"endsAt"`
+	Matchers  []*AlertSilenceMatcher `json:"matchers"`
+
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// IsActive returns true if the silence applies at the given time.
+func (s *AlertSilence) IsActive(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+type CreateAlertSilenceCommand struct {
+	Comment  string                 `json:"comment"`
+	StartsAt time.Time              `json:"startsAt"`
+	EndsAt   time.Time              `json:"endsAt" binding:"Required"`
+	Matchers []*AlertSilenceMatcher `json:"matchers"`
+
+	OrgId     int64 `json:"-"`
+	CreatedBy int64 `json:"-"`
+	Result    *AlertSilence
+}
+
+func (cmd *CreateAlertSilenceCommand) Validate() error {
+	if len(cmd.Matchers) == 0 {
+		return ErrAlertSilenceMissingMatchers
+	}
+
+	if !cmd.EndsAt.After(cmd.StartsAt) {
+		return ErrAlertSilenceInvalidTimeRange
+	}
+
+	for _, matcher := range cmd.Matchers {
+		if err := matcher.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type DeleteAlertSilenceCommand struct {
+	Id    int64
+	OrgId int64
+}
+
+// DeleteExpiredAlertSilencesCommand removes silences that ended before
+// EndedBefore.
+type DeleteExpiredAlertSilencesCommand struct {
+	EndedBefore time.Time
+
+	DeletedRows int64
+}
+
+type GetAlertSilenceByIdQuery struct {
+	Id    int64
+	OrgId int64
+
+	Result *AlertSilence
+}
+
+// GetAlertSilencesQuery returns the silences of an organization, optionally
+// filtered by state which is one of active, pending or expired.
+type GetAlertSilencesQuery struct {
+	OrgId int64
+	State string
+
+	Result []*AlertSilence
+}
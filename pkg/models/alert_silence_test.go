@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertSilenceModel(t *testing.T) {
+	Convey("Testing alert silence model", t, func() {
+		now := time.Now()
+
+		Convey("Should validate create command", func() {
+			cmd := &CreateAlertSilenceCommand{StartsAt: now, EndsAt: now.Add(time.Hour)}
+			So(cmd.Validate(), ShouldEqual, ErrAlertSilenceMissingMatchers)
+
+			cmd.Matchers = []*AlertSilenceMatcher{{Type: AlertSilenceMatchRule, Value: "cpu"}}
+			So(cmd.Validate(), ShouldBeNil)
+
+			cmd.EndsAt = now
+			So(cmd.Validate(), ShouldEqual, ErrAlertSilenceInvalidTimeRange)
+		})
+
+		Convey("Should validate matchers", func() {
+			So((&AlertSilenceMatcher{Type: "unknown", Value: "a"}).Validate(), ShouldNotBeNil)
+			So((&AlertSilenceMatcher{Type: AlertSilenceMatchLabel, Value: "a"}).Validate(), ShouldNotBeNil)
+			So((&AlertSilenceMatcher{Type: AlertSilenceMatchRule, Value: "(", IsRegex: true}).Validate(), ShouldNotBeNil)
+		})
+
+		Convey("Should match whole values with regex", func() {
+			matcher := &AlertSilenceMatcher{Type: AlertSilenceMatchRule, Value: "web-.*", IsRegex: true}
+			So(matcher.MatchValue("web-1"), ShouldBeTrue)
+			So(matcher.MatchValue("old-web-1"), ShouldBeFalse)
+		})
+
+		Convey("Should be active between start and end", func() {
+			silence := &AlertSilence{StartsAt: now, EndsAt: now.Add(time.Hour)}
+			So(silence.IsActive(now), ShouldBeTrue)
+			So(silence.IsActive(now.Add(time.Hour)), ShouldBeFalse)
+			So(silence.IsActive(now.Add(-time.Second)), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/securejsondata"
+)
+
+var (
+	ErrNotificationDeliveryNotFound = errors.New("Notification delivery not found")
+	ErrNotificationDeliveryConflict = errors.New("Notification delivery was claimed by another server")
+)
+
+type NotificationDeliveryState string
+
+const (
+	NotificationDeliveryPending   NotificationDeliveryState = "pending"
+	NotificationDeliveryDelivered NotificationDeliveryState = "delivered"
+	NotificationDeliveryFailed    NotificationDeliveryState = "failed"
+)
+
+func (s NotificationDeliveryState) IsValid() bool {
+	return s == NotificationDeliveryPending || s == NotificationDeliveryDelivered || s == NotificationDeliveryFailed
+}
+
+// NotificationDelivery is a webhook sent for a notification channel. Failed
+// deliveries stay pending until they are retried successfully or reach the
+// maximum attempts, failed deliveries are the dead letters. The request is
+// stored encrypted since urls, headers and bodies often contain secrets.
+// Times are epoch seconds.
+type NotificationDelivery struct {
+	Id            int64                         `json:"id"`
+	OrgId         int64                         `json:"-"`
+	AlertId       int64                         `json:"alertId"`
+	NotifierId    int64                         `json:"notifierId"`
+	NotifierType  string                        `json:"notifierType"`
+	State         NotificationDeliveryState     `json:"state"`
+	Attempts      int                           `json:"attempts"`
+	LastError     string                        `json:"lastError"`
+	Request       securejsondata.SecureJsonData `json:"-"`
+	NextAttemptAt int64                         `json:"nextAttemptAt"`
+	Created       int64                         `json:"created"`
+	Updated       int64                         `json:"updated"`
+}
+
+// NotificationDeliveryTarget is the notification channel a webhook is sent
+// for. Webhooks sent with a target in their context are tracked as
+// notification deliveries and retried when they fail.
+type NotificationDeliveryTarget struct {
+	OrgId        int64
+	AlertId      int64
+	NotifierId   int64
+	NotifierType string
+}
+
+type notificationDeliveryTargetKey struct{}
+
+func WithNotificationDeliveryTarget(ctx context.Context, target *NotificationDeliveryTarget) context.Context {
+	return context.WithValue(ctx, notificationDeliveryTargetKey{}, target)
+}
+
+func NotificationDeliveryTargetFromContext(ctx context.Context) *NotificationDeliveryTarget {
+	target, _ := ctx.Value(notificationDeliveryTargetKey{}).(*NotificationDeliveryTarget)
+	return target
+}
+
+type CreateNotificationDeliveryCommand struct {
+	Target        *NotificationDeliveryTarget
+	State         NotificationDeliveryState
+	Attempts      int
+	LastError     string
+	Request       map[string]string
+	NextAttemptAt int64
+
+	Result *NotificationDelivery
+}
+
+// ClaimNotificationDeliveryCommand counts a new attempt of a pending
+// delivery and hides it from other servers until RetryAt. It fails with
+// ErrNotificationDeliveryConflict when the delivery was already claimed.
+type ClaimNotificationDeliveryCommand struct {
+	Id       int64
+	Attempts int
+	RetryAt  int64
+}
+
+type SetNotificationDeliveryStateCommand struct {
+	Id            int64
+	State         NotificationDeliveryState
+	LastError     string
+	NextAttemptAt int64
+}
+
+// RetryNotificationDeliveryCommand moves a dead letter back to the queue.
+type RetryNotificationDeliveryCommand struct {
+	OrgId int64
+	Id    int64
+}
+
+type DeleteNotificationDeliveryCommand struct {
+	OrgId int64
+	Id    int64
+}
+
+// DeleteExpiredNotificationDeliveriesCommand removes delivered and failed
+// deliveries that were last updated before OlderThan.
+type DeleteExpiredNotificationDeliveriesCommand struct {
+	OlderThan time.Time
+
+	DeletedRows int64
+}
+
+type GetNotificationDeliveryQuery struct {
+	OrgId int64
+	Id    int64
+
+	Result *NotificationDelivery
+}
+
+type GetNotificationDeliveriesQuery struct {
+	OrgId      int64
+	NotifierId int64
+	States     []NotificationDeliveryState
+	Limit      int
+
+	Result []*NotificationDelivery
+}
+
+// GetDueNotificationDeliveriesQuery returns the pending deliveries whose
+// next attempt is due at Now.
+type GetDueNotificationDeliveriesQuery struct {
+	Now   int64
+	Limit int
+
+	Result []*NotificationDelivery
+}
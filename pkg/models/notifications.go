@@ -26,6 +26,11 @@ type SendWebhookSync struct {
 	HttpMethod  string
 	HttpHeader  map[string]string
 	ContentType string
+
+	// ResponseBody is set to the body of a successful response. It stays
+	// empty when a failed notification was queued for retry, senders that
+	// need the response must send without a delivery target.
+	ResponseBody []byte
 }
 
 type SendResetPasswordEmailCommand struct {
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrQueryCacheItemNotFound = errors.New("Query cache item not found")
+
+// QueryCacheItem is a cached response of a datasource query. Expires is
+// epoch seconds.
+type QueryCacheItem struct {
+	Id       int64
+	CacheKey string
+	Data     string
+	Expires  int64
+}
+
+// GetQueryCacheItemQuery returns the item with the key unless it expired.
+type GetQueryCacheItemQuery struct {
+	CacheKey string
+
+	Result *QueryCacheItem
+}
+
+type SetQueryCacheItemCommand struct {
+	CacheKey string
+	Data     string
+	Expires  time.Time
+}
+
+type DeleteExpiredQueryCacheItemsCommand struct {
+	DeletedRows int64
+}
@@ -0,0 +1,111 @@
+package alerting
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// validateStandaloneAlertRule resolves the datasources of the queries of a
+// standalone alert rule and validates its settings. Queries reference a
+// datasource by datasourceId or by name in datasource, the default
+// datasource is used when neither is set.
+func validateStandaloneAlertRule(cmd *m.ValidateAlertRuleCommand) error {
+	if cmd.Settings == nil {
+		return ValidationError{Reason: "Alert rule is missing settings"}
+	}
+
+	frequency, err := getTimeDurationStringToSeconds(cmd.Settings.Get("frequency").MustString())
+	if err != nil {
+		return ValidationError{Reason: "Could not parse frequency"}
+	}
+
+	rawFor := cmd.Settings.Get("for").MustString()
+	var forValue time.Duration
+	if rawFor != "" {
+		forValue, err = time.ParseDuration(rawFor)
+		if err != nil {
+			return ValidationError{Reason: "Could not parse for"}
+		}
+	}
+
+	alert := &m.Alert{
+		OrgId:     cmd.OrgId,
+		Name:      cmd.Settings.Get("name").MustString(),
+		Message:   cmd.Settings.Get("message").MustString(),
+		Frequency: frequency,
+		For:       forValue,
+		Settings:  cmd.Settings,
+	}
+	if alert.Name == "" {
+		return ValidationError{Reason: "Alert rule is missing a name"}
+	}
+
+	for _, condition := range cmd.Settings.Get("conditions").MustArray() {
+		jsonCondition := simplejson.NewFromAny(condition)
+
+		jsonQueries := []*simplejson.Json{jsonCondition.Get("query")}
+		if queries, hasQueries := jsonCondition.CheckGet("queries"); hasQueries {
+			jsonQueries = make([]*simplejson.Json, 0)
+			for _, query := range queries.MustArray() {
+				jsonQueries = append(jsonQueries, simplejson.NewFromAny(query))
+			}
+		}
+
+		for _, jsonQuery := range jsonQueries {
+			if err := resolveAlertRuleQuery(cmd.OrgId, cmd.User, jsonQuery); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := NewRuleFromDBAlert(alert); err != nil {
+		return err
+	}
+
+	cmd.Result = alert
+	return nil
+}
+
+// resolveAlertRuleQuery stores the id of the datasource on a query of a
+// standalone alert rule.
+func resolveAlertRuleQuery(orgID int64, user *m.SignedInUser, jsonQuery *simplejson.Json) error {
+	refID := ""
+	if params := jsonQuery.Get("params").MustArray(); len(params) == 3 {
+		refID, _ = params[0].(string)
+	}
+	if refID == "" {
+		return ValidationError{Reason: "Alert rule query needs params with a ref id, from and to"}
+	}
+
+	if _, hasModel := jsonQuery.CheckGet("model"); !hasModel {
+		return ValidationError{Reason: fmt.Sprintf("Alert rule query(%s) is missing its model", refID)}
+	}
+
+	var datasource *m.DataSource
+	if dsID := jsonQuery.Get("datasourceId").MustInt64(); dsID != 0 {
+		query := &m.GetDataSourceByIdQuery{Id: dsID, OrgId: orgID}
+		if err := bus.Dispatch(query); err != nil {
+			return ValidationError{Reason: fmt.Sprintf("Data source used by alert rule query(%s) not found, datasourceId=%d", refID, dsID)}
+		}
+		datasource = query.Result
+	} else {
+		dsName := jsonQuery.Get("datasource").MustString()
+		ds, err := lookupDatasource(orgID, dsName)
+		if err != nil {
+			return ValidationError{Reason: fmt.Sprintf("Data source used by alert rule query(%s) not found, datasource=%s", refID, dsName)}
+		}
+		datasource = ds
+	}
+
+	if err := checkDatasourcePermission(user, datasource); err != nil {
+		return err
+	}
+
+	jsonQuery.Set("datasourceId", datasource.Id)
+	jsonQuery.Get("model").Set("refId", refID)
+	return nil
+}
@@ -0,0 +1,137 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStandaloneAlertRuleValidation(t *testing.T) {
+	Convey("Validating standalone alert rules", t, func() {
+		RegisterCondition("query", func(model *simplejson.Json, index int) (Condition, error) {
+			return &FakeCondition{}, nil
+		})
+
+		defaultDs := &m.DataSource{Id: 12, OrgId: 1, Name: "I am default", IsDefault: true}
+		graphite2Ds := &m.DataSource{Id: 15, OrgId: 1, Name: "graphite2"}
+
+		bus.AddHandler("test", func(query *m.GetDataSourcesQuery) error {
+			query.Result = []*m.DataSource{defaultDs, graphite2Ds}
+			return nil
+		})
+
+		bus.AddHandler("test", func(query *m.GetDataSourceByNameQuery) error {
+			if query.Name == graphite2Ds.Name {
+				query.Result = graphite2Ds
+				return nil
+			}
+			return m.ErrDataSourceNotFound
+		})
+
+		bus.AddHandler("test", func(query *m.GetDataSourceByIdQuery) error {
+			if query.Id == graphite2Ds.Id {
+				query.Result = graphite2Ds
+				return nil
+			}
+			return m.ErrDataSourceNotFound
+		})
+
+		allowedDatasources := []*m.DataSource{defaultDs, graphite2Ds}
+		bus.AddHandler("test", func(query *m.DatasourcesPermissionFilterQuery) error {
+			query.Result = make([]*m.DataSource, 0)
+			for _, ds := range query.Datasources {
+				for _, allowed := range allowedDatasources {
+					if ds.Id == allowed.Id {
+						query.Result = append(query.Result, ds)
+					}
+				}
+			}
+			return nil
+		})
+
+		settingsJSON := func(query string) *simplejson.Json {
+			json, err := simplejson.NewJson([]byte(`{
+				"name": "cpu rule",
+				"message": "cpu is high",
+				"frequency": "1m",
+				"for": "5m",
+				"conditions": [{
+					"type": "query",
+					"evaluator": {"type": "gt", "params": [1]},
+					"reducer": {"type": "avg", "params": []},
+					"query": ` + query + `
+				}]
+			}`))
+			So(err, ShouldBeNil)
+			return json
+		}
+
+		validate := func(settings *simplejson.Json) (*m.ValidateAlertRuleCommand, error) {
+			cmd := &m.ValidateAlertRuleCommand{
+				OrgId:    1,
+				User:     &m.SignedInUser{OrgId: 1, OrgRole: m.ROLE_EDITOR},
+				Settings: settings,
+			}
+			return cmd, validateStandaloneAlertRule(cmd)
+		}
+
+		Convey("Uses the default datasource when none is set", func() {
+			cmd, err := validate(settingsJSON(`{"params": ["A", "5m", "now"], "model": {"target": "cpu"}}`))
+			So(err, ShouldBeNil)
+
+			query := simplejson.NewFromAny(cmd.Result.Settings.Get("conditions").MustArray()[0]).Get("query")
+			So(query.Get("datasourceId").MustInt64(), ShouldEqual, defaultDs.Id)
+			So(query.Get("model").Get("refId").MustString(), ShouldEqual, "A")
+
+			So(cmd.Result.Name, ShouldEqual, "cpu rule")
+			So(cmd.Result.Message, ShouldEqual, "cpu is high")
+			So(cmd.Result.Frequency, ShouldEqual, 60)
+			So(cmd.Result.For, ShouldEqual, 5*time.Minute)
+		})
+
+		Convey("Looks up the datasource by name", func() {
+			cmd, err := validate(settingsJSON(`{"params": ["A", "5m", "now"], "datasource": "graphite2", "model": {}}`))
+			So(err, ShouldBeNil)
+
+			query := simplejson.NewFromAny(cmd.Result.Settings.Get("conditions").MustArray()[0]).Get("query")
+			So(query.Get("datasourceId").MustInt64(), ShouldEqual, graphite2Ds.Id)
+		})
+
+		Convey("Looks up the datasource by id", func() {
+			cmd, err := validate(settingsJSON(`{"params": ["A", "5m", "now"], "datasourceId": 15, "model": {}}`))
+			So(err, ShouldBeNil)
+
+			query := simplejson.NewFromAny(cmd.Result.Settings.Get("conditions").MustArray()[0]).Get("query")
+			So(query.Get("datasourceId").MustInt64(), ShouldEqual, graphite2Ds.Id)
+		})
+
+		Convey("Fails for an unknown datasource", func() {
+			_, err := validate(settingsJSON(`{"params": ["A", "5m", "now"], "datasource": "unknown", "model": {}}`))
+			So(err, ShouldHaveSameTypeAs, ValidationError{})
+		})
+
+		Convey("Fails when the user cannot query the datasource", func() {
+			allowedDatasources = []*m.DataSource{defaultDs}
+
+			_, err := validate(settingsJSON(`{"params": ["A", "5m", "now"], "datasourceId": 15, "model": {}}`))
+			So(err, ShouldEqual, m.ErrDataSourceAccessDenied)
+		})
+
+		Convey("Fails for a query without a model", func() {
+			_, err := validate(settingsJSON(`{"params": ["A", "5m", "now"]}`))
+			So(err, ShouldHaveSameTypeAs, ValidationError{})
+		})
+
+		Convey("Fails for an invalid frequency", func() {
+			settings := settingsJSON(`{"params": ["A", "5m", "now"], "model": {}}`)
+			settings.Set("frequency", "often")
+
+			_, err := validate(settings)
+			So(err, ShouldHaveSameTypeAs, ValidationError{})
+		})
+	})
+}
@@ -0,0 +1,243 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// backtestMaxEvaluations limits how many evaluations a single backtest may
+// run against the datasources.
+const backtestMaxEvaluations = 1000
+
+// AlertBacktestCommand evaluates the alert rule of a panel at its frequency
+// over a past time range without saving state or sending notifications.
+type AlertBacktestCommand struct {
+	Dashboard *simplejson.Json
+	PanelId   int64
+	OrgId     int64
+	User      *m.SignedInUser
+	From      time.Time
+	To        time.Time
+
+	Result *BacktestResult
+}
+
+type BacktestResult struct {
+	Evaluations   int                     `json:"evaluations"`
+	StateChanges  []*BacktestStateChange  `json:"stateChanges"`
+	Notifications []*BacktestNotification `json:"notifications"`
+}
+
+type BacktestStateChange struct {
+	Time        time.Time         `json:"time"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	PrevState   m.AlertStateType  `json:"prevState"`
+	NewState    m.AlertStateType  `json:"newState"`
+	EvalMatches []*EvalMatch      `json:"evalMatches"`
+	Error       string            `json:"error,omitempty"`
+}
+
+type BacktestNotification struct {
+	Time         time.Time         `json:"time"`
+	NotifierId   int64             `json:"notifierId"`
+	NotifierName string            `json:"notifierName"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	State        m.AlertStateType  `json:"state"`
+	Reminder     bool              `json:"reminder"`
+}
+
+type backtestNotifier struct {
+	model    *m.AlertNotification
+	notifier Notifier
+}
+
+func init() {
+	bus.AddHandler("alerting", handleAlertBacktestCommand)
+}
+
+func handleAlertBacktestCommand(cmd *AlertBacktestCommand) error {
+	rule, err := getPanelAlertRule(cmd.Dashboard, cmd.PanelId, cmd.OrgId, cmd.User)
+	if err != nil {
+		return err
+	}
+
+	if !cmd.From.Before(cmd.To) {
+		return ValidationError{Reason: "Backtest time range must end after it starts"}
+	}
+
+	if rule.Frequency <= 0 {
+		rule.Frequency = 60
+	}
+
+	frequency := time.Duration(rule.Frequency) * time.Second
+	if evaluations := cmd.To.Sub(cmd.From)/frequency + 1; evaluations > backtestMaxEvaluations {
+		return ValidationError{Reason: fmt.Sprintf("Backtest would evaluate the rule %d times, the limit is %d. Shorten the time range.", evaluations, backtestMaxEvaluations)}
+	}
+
+	notifiers, err := getBacktestNotifiers(rule)
+	if err != nil {
+		return err
+	}
+
+	cmd.Result, err = backtestRule(rule, NewEvalHandler(), notifiers, cmd.From, cmd.To)
+	return err
+}
+
+func getBacktestNotifiers(rule *Rule) ([]*backtestNotifier, error) {
+	query := &m.GetAlertNotificationsToSendQuery{OrgId: rule.OrgId, Ids: rule.Notifications}
+	if err := bus.Dispatch(query); err != nil {
+		return nil, err
+	}
+
+	notifiers := make([]*backtestNotifier, 0, len(query.Result))
+	for _, model := range query.Result {
+		notifier, err := createNotifier(model)
+		if err != nil {
+			continue
+		}
+		notifiers = append(notifiers, &backtestNotifier{model: model, notifier: notifier})
+	}
+
+	return notifiers, nil
+}
+
+// backtestRule steps through the time range at the rule frequency and
+// records the state changes and the notifications they would have caused.
+func backtestRule(rule *Rule, handler EvalHandler, notifiers []*backtestNotifier, from time.Time, to time.Time) (*BacktestResult, error) {
+	result := &BacktestResult{
+		StateChanges:  make([]*BacktestStateChange, 0),
+		Notifications: make([]*BacktestNotification, 0),
+	}
+
+	rule.State = m.AlertStateUnknown
+	rule.LastStateChange = from
+
+	frequency := time.Duration(rule.Frequency) * time.Second
+	instances := make(map[string]*Instance)
+	notifierStates := make(map[string]*m.AlertNotificationState)
+
+	notify := func(evalContext *EvalContext, labels map[string]string) {
+		for _, n := range notifiers {
+			key := fmt.Sprintf("%d/%s", n.model.Id, m.AlertInstanceLabelsHash(labels))
+			state, exist := notifierStates[key]
+			if !exist {
+				state = &m.AlertNotificationState{State: m.AlertNotificationStateCompleted}
+				notifierStates[key] = state
+			}
+
+			if !n.notifier.ShouldNotify(evalContext.Ctx, evalContext, state) {
+				continue
+			}
+
+			state.UpdatedAt = evalContext.EvalTime.Unix()
+			result.Notifications = append(result.Notifications, &BacktestNotification{
+				Time:         evalContext.EvalTime,
+				NotifierId:   n.model.Id,
+				NotifierName: n.model.Name,
+				Labels:       labels,
+				State:        evalContext.Rule.State,
+				Reminder:     evalContext.PrevAlertState == evalContext.Rule.State,
+			})
+		}
+	}
+
+	for evalTime := from; !evalTime.After(to); evalTime = evalTime.Add(frequency) {
+		ctx, cancel := context.WithTimeout(context.Background(), backtestTimeout)
+
+		evalContext := NewEvalContext(ctx, rule)
+		evalContext.IsTestRun = true
+		evalContext.EvalTime = evalTime
+
+		handler.Eval(evalContext)
+		cancel()
+
+		if evalContext.Error == m.ErrDataSourceAccessDenied {
+			return nil, evalContext.Error
+		}
+
+		result.Evaluations++
+		rule.State = evalContext.GetNewState()
+
+		if evalContext.ShouldUpdateAlertState() {
+			rule.LastStateChange = evalTime
+			result.StateChanges = append(result.StateChanges, newBacktestStateChange(evalContext, nil, evalContext.PrevAlertState, rule.State, evalContext.EvalMatches))
+		}
+
+		if !rule.MultiDimensional {
+			notify(evalContext, nil)
+			continue
+		}
+
+		restoreBacktestInstances(evalContext, instances)
+		evalContext.UpdateInstanceStates()
+
+		for _, instance := range evalContext.Instances {
+			key := m.AlertInstanceLabelsHash(instance.Labels)
+
+			if instance.ShouldUpdateState() {
+				instance.LastStateChange = evalTime
+				result.StateChanges = append(result.StateChanges, newBacktestStateChange(evalContext, instance.Labels, instance.PrevState, instance.State, instance.EvalMatches))
+			}
+
+			notify(evalContext.ForInstance(instance), instance.Labels)
+
+			if instance.Missing && instance.State == m.AlertStateOK {
+				delete(instances, key)
+			} else {
+				instances[key] = instance
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// restoreBacktestInstances carries the instance states of the previous
+// evaluation over, the same way loadInstanceStates does with stored ones.
+func restoreBacktestInstances(evalContext *EvalContext, instances map[string]*Instance) {
+	seen := make(map[string]bool)
+	for _, instance := range evalContext.Instances {
+		key := m.AlertInstanceLabelsHash(instance.Labels)
+		seen[key] = true
+
+		if prev, exist := instances[key]; exist {
+			instance.PrevState = prev.State
+			instance.LastStateChange = prev.LastStateChange
+		}
+	}
+
+	for key, prev := range instances {
+		if seen[key] {
+			continue
+		}
+
+		evalContext.Instances = append(evalContext.Instances, &Instance{
+			Labels:          prev.Labels,
+			EvalMatches:     make([]*EvalMatch, 0),
+			PrevState:       prev.State,
+			LastStateChange: prev.LastStateChange,
+			Missing:         true,
+		})
+	}
+}
+
+func newBacktestStateChange(evalContext *EvalContext, labels map[string]string, prevState, newState m.AlertStateType, matches []*EvalMatch) *BacktestStateChange {
+	change := &BacktestStateChange{
+		Time:        evalContext.EvalTime,
+		Labels:      labels,
+		PrevState:   prevState,
+		NewState:    newState,
+		EvalMatches: matches,
+	}
+
+	if evalContext.Error != nil {
+		change.Error = evalContext.Error.Error()
+	}
+
+	return change
+}
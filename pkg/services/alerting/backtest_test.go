@@ -0,0 +1,73 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeBacktestEvalHandler struct {
+	firing func(evalTime time.Time) bool
+}
+
+func (handler *fakeBacktestEvalHandler) Eval(evalContext *EvalContext) {
+	evalContext.Firing = handler.firing(evalContext.EvalTime)
+	evalContext.NoDataFound = false
+}
+
+type fakeBacktestNotifier struct {
+	fakeGroupedNotifier
+}
+
+func (n *fakeBacktestNotifier) ShouldNotify(ctx context.Context, evalContext *EvalContext, state *m.AlertNotificationState) bool {
+	return evalContext.PrevAlertState != evalContext.Rule.State && evalContext.Rule.State != m.AlertStatePending
+}
+
+func TestAlertRuleBacktest(t *testing.T) {
+	Convey("Backtesting alert rules", t, func() {
+		from := time.Unix(1500000000, 0)
+		to := from.Add(6 * time.Minute)
+
+		handler := &fakeBacktestEvalHandler{firing: func(evalTime time.Time) bool {
+			minute := evalTime.Sub(from) / time.Minute
+			return minute >= 2 && minute <= 5
+		}}
+
+		notifiers := []*backtestNotifier{
+			{model: &m.AlertNotification{Id: 7, Name: "ops"}, notifier: &fakeBacktestNotifier{}},
+		}
+
+		Convey("Should step through the range at the rule frequency", func() {
+			rule := &Rule{Frequency: 60, NoDataState: m.NoDataSetNoData}
+
+			result, err := backtestRule(rule, handler, notifiers, from, to)
+			So(err, ShouldBeNil)
+			So(result.Evaluations, ShouldEqual, 7)
+			So(len(result.StateChanges), ShouldEqual, 3)
+			So(result.StateChanges[1].NewState, ShouldEqual, m.AlertStateAlerting)
+			So(result.StateChanges[1].Time, ShouldEqual, from.Add(2*time.Minute))
+
+			So(len(result.Notifications), ShouldEqual, 3)
+			So(result.Notifications[1].NotifierName, ShouldEqual, "ops")
+			So(result.Notifications[2].State, ShouldEqual, m.AlertStateOK)
+			So(result.Notifications[2].Time, ShouldEqual, to)
+		})
+
+		Convey("Should honour For", func() {
+			rule := &Rule{Frequency: 60, For: 2 * time.Minute, NoDataState: m.NoDataSetNoData}
+
+			result, err := backtestRule(rule, handler, notifiers, from, to)
+			So(err, ShouldBeNil)
+
+			states := make([]m.AlertStateType, 0)
+			for _, change := range result.StateChanges {
+				states = append(states, change.NewState)
+			}
+			So(states, ShouldResemble, []m.AlertStateType{m.AlertStateOK, m.AlertStatePending, m.AlertStateAlerting, m.AlertStateOK})
+			So(result.StateChanges[2].Time, ShouldEqual, from.Add(5*time.Minute))
+		})
+	})
+}
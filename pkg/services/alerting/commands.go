@@ -8,6 +8,7 @@ import (
 func init() {
 	bus.AddHandler("alerting", updateDashboardAlerts)
 	bus.AddHandler("alerting", validateDashboardAlerts)
+	bus.AddHandler("alerting", validateStandaloneAlertRule)
 }
 
 func validateDashboardAlerts(cmd *m.ValidateDashboardAlertsCommand) error {
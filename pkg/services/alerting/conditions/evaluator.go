@@ -3,10 +3,14 @@ package conditions
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/null"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/tsdb"
 )
 
 var (
@@ -18,6 +22,44 @@ type AlertEvaluator interface {
 	Eval(reducedValue null.Float) bool
 }
 
+// seriesEvaluator is implemented by evaluators that compare the reduced
+// value against a baseline computed from the series itself.
+type seriesEvaluator interface {
+	EvalSeries(series *tsdb.TimeSeries, reducedValue null.Float) bool
+}
+
+// baselineEvaluator is implemented by evaluators that compare the reduced
+// value against the same query executed for an earlier time range.
+type baselineEvaluator interface {
+	TimeShift() time.Duration
+	WithBaseline(baseline tsdb.TimeSeriesSlice, reducer QueryReducer) AlertEvaluator
+}
+
+func evalSeries(evaluator AlertEvaluator, series *tsdb.TimeSeries, reducedValue null.Float) bool {
+	if e, ok := evaluator.(seriesEvaluator); ok {
+		return e.EvalSeries(series, reducedValue)
+	}
+
+	return evaluator.Eval(reducedValue)
+}
+
+// resolveBaseline returns an evaluator that can be used for the current
+// evaluation. Evaluators comparing against an earlier time range get the
+// series returned by execute for that time range.
+func resolveBaseline(evaluator AlertEvaluator, reducer QueryReducer, execute func(timeShift time.Duration) (tsdb.TimeSeriesSlice, error)) (AlertEvaluator, error) {
+	e, ok := evaluator.(baselineEvaluator)
+	if !ok {
+		return evaluator, nil
+	}
+
+	baseline, err := execute(e.TimeShift())
+	if err != nil {
+		return nil, err
+	}
+
+	return e.WithBaseline(baseline, reducer), nil
+}
+
 type NoValueEvaluator struct{}
 
 func (e *NoValueEvaluator) Eval(reducedValue null.Float) bool {
@@ -123,9 +165,221 @@ func NewAlertEvaluator(model *simplejson.Json) (AlertEvaluator, error) {
 		return &NoValueEvaluator{}, nil
 	}
 
+	switch typ {
+	case "zscore":
+		return newZScoreEvaluator(model)
+	case "percent_change":
+		return newPercentChangeEvaluator(model)
+	case "holt_winters":
+		return newHoltWintersEvaluator(model)
+	}
+
 	return nil, fmt.Errorf("Evaluator invalid evaluator type: %s", typ)
 }
 
+const (
+	zScoreMinRelativeStdDev = 0.001
+	zScoreMinStdDev         = 1e-9
+)
+
+// ZScoreEvaluator fires when the reduced value is more than Threshold
+// standard deviations away from the mean of the earlier points of the series.
+type ZScoreEvaluator struct {
+	Threshold float64
+}
+
+func newZScoreEvaluator(model *simplejson.Json) (*ZScoreEvaluator, error) {
+	params := model.Get("params").MustArray()
+	threshold, err := evaluatorFloatParam(params, 0, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	if threshold <= 0 {
+		return nil, alerting.ValidationError{Reason: "Z-score evaluator requires a positive threshold"}
+	}
+
+	return &ZScoreEvaluator{Threshold: threshold}, nil
+}
+
+func (e *ZScoreEvaluator) Eval(reducedValue null.Float) bool {
+	return false
+}
+
+func (e *ZScoreEvaluator) EvalSeries(series *tsdb.TimeSeries, reducedValue null.Float) bool {
+	values := baselineValues(series)
+	if !reducedValue.Valid || len(values) < 2 {
+		return false
+	}
+
+	// a flat baseline has no deviation to compare against, a minimum
+	// deviation relative to the mean lets a spike fire while rounding noise
+	// stays below the threshold
+	mean, stddev := tsdb.MeanAndStdDev(values)
+	stddev = math.Max(stddev, math.Max(math.Abs(mean)*zScoreMinRelativeStdDev, zScoreMinStdDev))
+
+	return math.Abs(reducedValue.Float64-mean)/stddev > e.Threshold
+}
+
+// PercentChangeEvaluator fires when the reduced value changed by more than
+// Threshold percent compared to the same series Offset ago.
+type PercentChangeEvaluator struct {
+	Threshold float64
+	Offset    time.Duration
+
+	baseline map[string]null.Float
+}
+
+func newPercentChangeEvaluator(model *simplejson.Json) (*PercentChangeEvaluator, error) {
+	params := model.Get("params").MustArray()
+	threshold, err := evaluatorFloatParam(params, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(params) < 2 {
+		return nil, alerting.ValidationError{Reason: "Percent change evaluator requires a time shift parameter"}
+	}
+
+	shift, _ := params[1].(string)
+	offset, err := tsdb.ParseTimeShift(shift)
+	if err != nil || offset <= 0 {
+		return nil, alerting.ValidationError{Reason: fmt.Sprintf("Percent change evaluator has invalid time shift %v", params[1])}
+	}
+
+	return &PercentChangeEvaluator{Threshold: math.Abs(threshold), Offset: offset}, nil
+}
+
+func (e *PercentChangeEvaluator) Eval(reducedValue null.Float) bool {
+	return false
+}
+
+func (e *PercentChangeEvaluator) TimeShift() time.Duration {
+	return e.Offset
+}
+
+func (e *PercentChangeEvaluator) WithBaseline(baseline tsdb.TimeSeriesSlice, reducer QueryReducer) AlertEvaluator {
+	values := make(map[string]null.Float)
+	for _, series := range baseline {
+		values[m.AlertInstanceLabelsHash(seriesLabels(series))] = reducer.Reduce(series)
+	}
+
+	return &PercentChangeEvaluator{Threshold: e.Threshold, Offset: e.Offset, baseline: values}
+}
+
+func (e *PercentChangeEvaluator) EvalSeries(series *tsdb.TimeSeries, reducedValue null.Float) bool {
+	previous, exist := e.baseline[m.AlertInstanceLabelsHash(seriesLabels(series))]
+	if !exist || !previous.Valid || !reducedValue.Valid || previous.Float64 == 0 {
+		return false
+	}
+
+	change := (reducedValue.Float64 - previous.Float64) / math.Abs(previous.Float64) * 100
+	return math.Abs(change) > e.Threshold
+}
+
+// HoltWintersEvaluator predicts the latest value with double exponential
+// smoothing of the earlier points and fires when the reduced value is more
+// than Deviations standard deviations of the prediction error away from it.
+type HoltWintersEvaluator struct {
+	Deviations float64
+	Smoothing  float64
+	Trend      float64
+}
+
+func newHoltWintersEvaluator(model *simplejson.Json) (*HoltWintersEvaluator, error) {
+	params := model.Get("params").MustArray()
+
+	deviations, err := evaluatorFloatParam(params, 0, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	smoothing, err := evaluatorFloatParam(params, 1, 0.5)
+	if err != nil {
+		return nil, err
+	}
+
+	trend, err := evaluatorFloatParam(params, 2, 0.5)
+	if err != nil {
+		return nil, err
+	}
+
+	if deviations <= 0 {
+		return nil, alerting.ValidationError{Reason: "Holt-Winters evaluator requires a positive number of deviations"}
+	}
+
+	if smoothing <= 0 || smoothing >= 1 || trend <= 0 || trend >= 1 {
+		return nil, alerting.ValidationError{Reason: "Holt-Winters smoothing and trend factors must be between 0 and 1"}
+	}
+
+	return &HoltWintersEvaluator{Deviations: deviations, Smoothing: smoothing, Trend: trend}, nil
+}
+
+func (e *HoltWintersEvaluator) Eval(reducedValue null.Float) bool {
+	return false
+}
+
+func (e *HoltWintersEvaluator) EvalSeries(series *tsdb.TimeSeries, reducedValue null.Float) bool {
+	values := baselineValues(series)
+	if !reducedValue.Valid || len(values) < 3 {
+		return false
+	}
+
+	predicted, residuals := e.predict(values)
+	_, stddev := tsdb.MeanAndStdDev(residuals)
+
+	return math.Abs(reducedValue.Float64-predicted) > e.Deviations*stddev
+}
+
+// predict returns the forecast for the value following values together
+// with the one step ahead prediction errors for values.
+func (e *HoltWintersEvaluator) predict(values []float64) (float64, []float64) {
+	level := values[0]
+	trend := values[1] - values[0]
+	residuals := make([]float64, 0, len(values)-1)
+
+	for _, value := range values[1:] {
+		forecast := level + trend
+		residuals = append(residuals, value-forecast)
+
+		previousLevel := level
+		level = e.Smoothing*value + (1-e.Smoothing)*(level+trend)
+		trend = e.Trend*(level-previousLevel) + (1-e.Trend)*trend
+	}
+
+	return level + trend, residuals
+}
+
+// baselineValues returns the valid values of the series except the latest
+// one, which is the value being evaluated.
+func baselineValues(series *tsdb.TimeSeries) []float64 {
+	values := make([]float64, 0, len(series.Points))
+	for _, point := range series.Points {
+		if point[0].Valid {
+			values = append(values, point[0].Float64)
+		}
+	}
+
+	if len(values) == 0 {
+		return values
+	}
+
+	return values[:len(values)-1]
+}
+
+func evaluatorFloatParam(params []interface{}, index int, defaultValue float64) (float64, error) {
+	if len(params) <= index {
+		return defaultValue, nil
+	}
+
+	param, ok := params[index].(json.Number)
+	if !ok {
+		return 0, alerting.ValidationError{Reason: "Evaluator has invalid parameter"}
+	}
+
+	return param.Float64()
+}
+
 func inSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
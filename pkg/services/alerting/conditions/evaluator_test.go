@@ -2,11 +2,13 @@ package conditions
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/grafana/grafana/pkg/components/null"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/tsdb"
 )
 
 func evalutorScenario(json string, reducedValue float64, datapoints ...float64) bool {
@@ -61,3 +63,81 @@ func TestEvalutors(t *testing.T) {
 		})
 	})
 }
+
+func seriesEvaluatorScenario(json string, values ...float64) bool {
+	jsonModel, err := simplejson.NewJson([]byte(json))
+	So(err, ShouldBeNil)
+
+	evaluator, err := NewAlertEvaluator(jsonModel)
+	So(err, ShouldBeNil)
+
+	points := make(tsdb.TimeSeriesPoints, 0)
+	for i, v := range values {
+		points = append(points, tsdb.NewTimePoint(null.FloatFrom(v), float64(i)))
+	}
+
+	series := tsdb.NewTimeSeries("test", points)
+	return evalSeries(evaluator, series, NewSimpleReducer("last").Reduce(series))
+}
+
+func TestAnomalyEvaluators(t *testing.T) {
+	Convey("zscore", t, func() {
+		So(seriesEvaluatorScenario(`{"type": "zscore", "params": [3] }`, 10, 11, 9, 10, 11, 9, 10, 30), ShouldBeTrue)
+		So(seriesEvaluatorScenario(`{"type": "zscore", "params": [3] }`, 10, 11, 9, 10, 11, 9, 10, 11), ShouldBeFalse)
+		So(seriesEvaluatorScenario(`{"type": "zscore", "params": [3] }`, 10, 11, 9, 10, 11, 9, 10, -10), ShouldBeTrue)
+		So(seriesEvaluatorScenario(`{"type": "zscore", "params": [3] }`, 10, 30), ShouldBeFalse)
+		So(seriesEvaluatorScenario(`{"type": "zscore", "params": [3] }`, 100, 100, 100, 100.0001), ShouldBeFalse)
+		So(seriesEvaluatorScenario(`{"type": "zscore", "params": [3] }`, 100, 100, 100, 100), ShouldBeFalse)
+		So(seriesEvaluatorScenario(`{"type": "zscore", "params": [3] }`, 100, 100, 100, 150), ShouldBeTrue)
+		So(seriesEvaluatorScenario(`{"type": "zscore", "params": [3] }`, 0, 0, 0, 5), ShouldBeTrue)
+		So(evalutorScenario(`{"type": "zscore", "params": [3] }`, 30), ShouldBeFalse)
+	})
+
+	Convey("holt_winters", t, func() {
+		So(seriesEvaluatorScenario(`{"type": "holt_winters", "params": [3, 0.5, 0.5] }`, 1, 2, 3, 4, 5, 6, 7, 8), ShouldBeFalse)
+		So(seriesEvaluatorScenario(`{"type": "holt_winters", "params": [3, 0.5, 0.5] }`, 1, 2, 3, 4, 5, 6, 7, 20), ShouldBeTrue)
+		So(seriesEvaluatorScenario(`{"type": "holt_winters", "params": [3] }`, 10, 12, 10, 12, 10, 12, 10, 12), ShouldBeFalse)
+		So(seriesEvaluatorScenario(`{"type": "holt_winters", "params": [3] }`, 10, 12, 10, 12, 10, 12, 10, 50), ShouldBeTrue)
+	})
+
+	Convey("percent_change", t, func() {
+		jsonModel, err := simplejson.NewJson([]byte(`{"type": "percent_change", "params": [50, "1d"] }`))
+		So(err, ShouldBeNil)
+
+		evaluator, err := NewAlertEvaluator(jsonModel)
+		So(err, ShouldBeNil)
+		So(evaluator.(baselineEvaluator).TimeShift(), ShouldEqual, 24*time.Hour)
+
+		reducer := NewSimpleReducer("last")
+		baseline := tsdb.TimeSeriesSlice{
+			&tsdb.TimeSeries{Name: "cpu", Tags: map[string]string{"host": "a"}, Points: tsdb.NewTimeSeriesPointsFromArgs(10, 1)},
+			&tsdb.TimeSeries{Name: "cpu", Tags: map[string]string{"host": "b"}, Points: tsdb.NewTimeSeriesPointsFromArgs(10, 1)},
+		}
+		withBaseline := evaluator.(baselineEvaluator).WithBaseline(baseline, reducer)
+
+		eval := func(host string, value float64) bool {
+			series := &tsdb.TimeSeries{Name: "cpu", Tags: map[string]string{"host": host}, Points: tsdb.NewTimeSeriesPointsFromArgs(value, 2)}
+			return evalSeries(withBaseline, series, reducer.Reduce(series))
+		}
+
+		So(eval("a", 20), ShouldBeTrue)
+		So(eval("a", 14), ShouldBeFalse)
+		So(eval("b", 4), ShouldBeTrue)
+		So(eval("c", 100), ShouldBeFalse)
+	})
+
+	Convey("anomaly evaluators validate params", t, func() {
+		for _, json := range []string{
+			`{"type": "zscore", "params": [-1] }`,
+			`{"type": "percent_change", "params": [10] }`,
+			`{"type": "percent_change", "params": [10, "yesterday"] }`,
+			`{"type": "holt_winters", "params": [3, 1.5, 0.5] }`,
+		} {
+			jsonModel, err := simplejson.NewJson([]byte(json))
+			So(err, ShouldBeNil)
+
+			_, err = NewAlertEvaluator(jsonModel)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package conditions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/tsdb"
+)
+
+func init() {
+	alerting.RegisterCondition("math", func(model *simplejson.Json, index int) (alerting.Condition, error) {
+		return NewMathCondition(model, index)
+	})
+}
+
+// MathCondition combines the results of several queries with a math
+// expression, e.g. `$A / $B * 100`, and evaluates the resulting series.
+type MathCondition struct {
+	Index         int
+	Queries       map[string]*AlertQuery
+	Expression    string
+	Reducer       QueryReducer
+	Evaluator     AlertEvaluator
+	Operator      string
+	HandleRequest tsdb.HandleRequestFunc
+
+	expr tsdb.MathExpr
+}
+
+func (c *MathCondition) Eval(context *alerting.EvalContext) (*alerting.ConditionResult, error) {
+	seriesList, err := c.evalExpression(context, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if context.IsTestRun {
+		context.Logs = append(context.Logs, &alerting.ResultLogEntry{
+			Message: fmt.Sprintf("Condition[%d]: Math Expression Result", c.Index),
+			Data:    seriesList,
+		})
+	}
+
+	evaluator, err := resolveBaseline(c.Evaluator, c.Reducer, func(timeShift time.Duration) (tsdb.TimeSeriesSlice, error) {
+		return c.evalExpression(context, timeShift)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return evalSeriesList(context, c.Index, seriesList, c.Reducer, evaluator, c.Operator), nil
+}
+
+// evalExpression executes the queries for their time range shifted back by
+// timeShift and evaluates the expression on the results.
+func (c *MathCondition) evalExpression(context *alerting.EvalContext, timeShift time.Duration) (tsdb.TimeSeriesSlice, error) {
+	vars := make(map[string]tsdb.TimeSeriesSlice)
+	now := context.Now().Add(-timeShift)
+
+	for refId, query := range c.Queries {
+		timeRange := tsdb.NewFakeTimeRange(query.From, query.To, now)
+
+		series, err := executeAlertQuery(context, c.Index, query, timeRange, c.HandleRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		vars[refId] = series
+	}
+
+	value, err := c.expr.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if value.IsScalar {
+		return tsdb.TimeSeriesSlice{
+			tsdb.NewTimeSeries(c.Expression, tsdb.TimeSeriesPoints{tsdb.NewTimePoint(value.Scalar, 0)}),
+		}, nil
+	}
+
+	return value.Series, nil
+}
+
+func NewMathCondition(model *simplejson.Json, index int) (*MathCondition, error) {
+	condition := &MathCondition{
+		Index:         index,
+		Queries:       make(map[string]*AlertQuery),
+		HandleRequest: tsdb.HandleRequest,
+	}
+
+	for _, queryModel := range model.Get("queries").MustArray() {
+		queryJson := simplejson.NewFromAny(queryModel)
+
+		refId, _ := queryJson.Get("params").GetIndex(0).String()
+		if refId == "" {
+			return nil, alerting.ValidationError{Reason: "Math condition query is missing refId"}
+		}
+
+		query, err := newAlertQuery(queryJson)
+		if err != nil {
+			return nil, err
+		}
+
+		condition.Queries[refId] = query
+	}
+
+	if len(condition.Queries) == 0 {
+		return nil, alerting.ValidationError{Reason: "Math condition requires at least one query"}
+	}
+
+	condition.Expression = model.Get("expression").MustString()
+	expr, err := tsdb.ParseMathExpression(condition.Expression)
+	if err != nil {
+		return nil, alerting.ValidationError{Reason: "Invalid math expression: " + err.Error()}
+	}
+
+	for _, name := range expr.Vars() {
+		if _, exist := condition.Queries[name]; !exist {
+			return nil, alerting.ValidationError{Reason: fmt.Sprintf("Math expression references unknown query $%s", name)}
+		}
+	}
+	condition.expr = expr
+
+	reducerJson := model.Get("reducer")
+	if reducerJson.Get("type").MustString() == "" {
+		reducerJson = simplejson.NewFromAny(map[string]interface{}{"type": "last"})
+	}
+
+	reducer, err := NewReducerFromJson(reducerJson)
+	if err != nil {
+		return nil, err
+	}
+	condition.Reducer = reducer
+
+	evaluator, err := NewAlertEvaluator(model.Get("evaluator"))
+	if err != nil {
+		return nil, err
+	}
+	condition.Evaluator = evaluator
+
+	condition.Operator = model.Get("operator").Get("type").MustString("and")
+
+	return condition, nil
+}
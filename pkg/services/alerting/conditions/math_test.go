@@ -0,0 +1,119 @@
+package conditions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMathCondition(t *testing.T) {
+	Convey("when evaluating math condition", t, func() {
+		bus.AddHandler("test", func(query *m.GetDataSourceByIdQuery) error {
+			query.Result = &m.DataSource{Id: query.Id, Type: "graphite"}
+			return nil
+		})
+
+		series := map[int64]tsdb.TimeSeriesSlice{
+			1: {tsdb.NewTimeSeries("errors", tsdb.NewTimeSeriesPointsFromArgs(5, 1, 20, 2))},
+			2: {tsdb.NewTimeSeries("requests", tsdb.NewTimeSeriesPointsFromArgs(100, 1, 100, 2))},
+		}
+
+		newCondition := func(expression string, evaluator string) (*MathCondition, error) {
+			jsonModel, err := simplejson.NewJson([]byte(`{
+				"type": "math",
+				"queries": [
+					{"params": ["A", "5m", "now"], "datasourceId": 1, "model": {"target": "errors"}},
+					{"params": ["B", "5m", "now"], "datasourceId": 2, "model": {"target": "requests"}}
+				],
+				"expression": "` + expression + `",
+				"reducer": {"type": "last"},
+				"evaluator": ` + evaluator + `
+			}`))
+			So(err, ShouldBeNil)
+
+			condition, err := NewMathCondition(jsonModel, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			condition.HandleRequest = func(ctx context.Context, dsInfo *m.DataSource, req *tsdb.TsdbQuery) (*tsdb.Response, error) {
+				return &tsdb.Response{
+					Results: map[string]*tsdb.QueryResult{
+						"A": {Series: series[dsInfo.Id]},
+					},
+				}, nil
+			}
+
+			return condition, nil
+		}
+
+		evalContext := &alerting.EvalContext{Rule: &alerting.Rule{}}
+
+		Convey("Can read math condition from json model", func() {
+			condition, err := newCondition("$A / $B * 100", `{"type": "gt", "params": [10]}`)
+			So(err, ShouldBeNil)
+			So(len(condition.Queries), ShouldEqual, 2)
+			So(condition.Queries["B"].DatasourceId, ShouldEqual, 2)
+			So(condition.Expression, ShouldEqual, "$A / $B * 100")
+			So(condition.Operator, ShouldEqual, "and")
+		})
+
+		Convey("Should fire when error ratio is above threshold", func() {
+			condition, err := newCondition("$A / $B * 100", `{"type": "gt", "params": [10]}`)
+			So(err, ShouldBeNil)
+
+			cr, err := condition.Eval(evalContext)
+			So(err, ShouldBeNil)
+			So(cr.Firing, ShouldBeTrue)
+			So(cr.EvalMatches[0].Value.Float64, ShouldEqual, 20)
+		})
+
+		Convey("Should not fire when error ratio is below threshold", func() {
+			condition, err := newCondition("$A / $B * 100", `{"type": "gt", "params": [25]}`)
+			So(err, ShouldBeNil)
+
+			cr, err := condition.Eval(evalContext)
+			So(err, ShouldBeNil)
+			So(cr.Firing, ShouldBeFalse)
+		})
+
+		Convey("Should evaluate reduced results", func() {
+			condition, err := newCondition("max($A) - min($A)", `{"type": "gt", "params": [10]}`)
+			So(err, ShouldBeNil)
+
+			cr, err := condition.Eval(evalContext)
+			So(err, ShouldBeNil)
+			So(cr.Firing, ShouldBeTrue)
+		})
+
+		Convey("Should fail validation on unknown query", func() {
+			_, err := newCondition("$A / $C", `{"type": "gt", "params": [10]}`)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Should fail validation on invalid expression", func() {
+			_, err := newCondition("$A / (", `{"type": "gt", "params": [10]}`)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Should fail validation on queries without a time range", func() {
+			for _, params := range []string{`["A"]`, `["A", 5, "now"]`} {
+				jsonModel, err := simplejson.NewJson([]byte(`{
+					"type": "math",
+					"queries": [{"params": ` + params + `, "datasourceId": 1, "model": {}}],
+					"expression": "$A"
+				}`))
+				So(err, ShouldBeNil)
+
+				_, err = NewMathCondition(jsonModel, 0)
+				So(err, ShouldHaveSameTypeAs, alerting.ValidationError{})
+			}
+		})
+	})
+}
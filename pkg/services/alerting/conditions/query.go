@@ -31,27 +31,43 @@ type QueryCondition struct {
 }
 
 type AlertQuery struct {
-	Model        *simplejson.Json
-	DatasourceId int64
-	From         string
-	To           string
+	Model           *simplejson.Json
+	DatasourceId    int64
+	From            string
+	To              string
+	Table           *tsdb.TableSeriesOptions
+	Transformations []tsdb.Transformation
 }
 
 func (c *QueryCondition) Eval(context *alerting.EvalContext) (*alerting.ConditionResult, error) {
-	timeRange := tsdb.NewTimeRange(c.Query.From, c.Query.To)
+	timeRange := tsdb.NewFakeTimeRange(c.Query.From, c.Query.To, context.Now())
 
 	seriesList, err := c.executeQuery(context, timeRange)
 	if err != nil {
 		return nil, err
 	}
 
+	evaluator, err := resolveBaseline(c.Evaluator, c.Reducer, func(timeShift time.Duration) (tsdb.TimeSeriesSlice, error) {
+		return c.executeQuery(context, tsdb.NewFakeTimeRange(c.Query.From, c.Query.To, context.Now().Add(-timeShift)))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return evalSeriesList(context, c.Index, seriesList, c.Reducer, evaluator, c.Operator), nil
+}
+
+// evalSeriesList reduces and evaluates every series and builds the
+// condition result from the outcome.
+func evalSeriesList(context *alerting.EvalContext, index int, seriesList tsdb.TimeSeriesSlice, reducer QueryReducer, evaluator AlertEvaluator, operator string) *alerting.ConditionResult {
 	emptySerieCount := 0
 	evalMatchCount := 0
 	var matches []*alerting.EvalMatch
+	var seriesResults []*alerting.SeriesResult
 
 	for _, series := range seriesList {
-		reducedValue := c.Reducer.Reduce(series)
-		evalMatch := c.Evaluator.Eval(reducedValue)
+		reducedValue := reducer.Reduce(series)
+		evalMatch := evalSeries(evaluator, series, reducedValue)
 
 		if !reducedValue.Valid {
 			emptySerieCount++
@@ -59,25 +75,33 @@ func (c *QueryCondition) Eval(context *alerting.EvalContext) (*alerting.Conditio
 
 		if context.IsTestRun {
 			context.Logs = append(context.Logs, &alerting.ResultLogEntry{
-				Message: fmt.Sprintf("Condition[%d]: Eval: %v, Metric: %s, Value: %s", c.Index, evalMatch, series.Name, reducedValue),
+				Message: fmt.Sprintf("Condition[%d]: Eval: %v, Metric: %s, Value: %s", index, evalMatch, series.Name, reducedValue),
 			})
 		}
 
+		match := &alerting.EvalMatch{
+			Metric: series.Name,
+			Value:  reducedValue,
+			Tags:   series.Tags,
+		}
+
 		if evalMatch {
 			evalMatchCount++
-
-			matches = append(matches, &alerting.EvalMatch{
-				Metric: series.Name,
-				Value:  reducedValue,
-				Tags:   series.Tags,
-			})
+			matches = append(matches, match)
 		}
+
+		seriesResults = append(seriesResults, &alerting.SeriesResult{
+			Labels:      seriesLabels(series),
+			Firing:      evalMatch,
+			NoDataFound: !reducedValue.Valid,
+			EvalMatch:   match,
+		})
 	}
 
 	// handle no series special case
 	if len(seriesList) == 0 {
 		// eval condition for null value
-		evalMatch := c.Evaluator.Eval(null.FloatFromPtr(nil))
+		evalMatch := evaluator.Eval(null.FloatFromPtr(nil))
 
 		if context.IsTestRun {
 			context.Logs = append(context.Logs, &alerting.ResultLogEntry{
@@ -92,16 +116,36 @@ func (c *QueryCondition) Eval(context *alerting.EvalContext) (*alerting.Conditio
 	}
 
 	return &alerting.ConditionResult{
-		Firing:      evalMatchCount > 0,
-		NoDataFound: emptySerieCount == len(seriesList),
-		Operator:    c.Operator,
-		EvalMatches: matches,
-	}, nil
+		Firing:        evalMatchCount > 0,
+		NoDataFound:   emptySerieCount == len(seriesList),
+		Operator:      operator,
+		EvalMatches:   matches,
+		SeriesResults: seriesResults,
+	}
+}
+
+// seriesLabels returns the label set identifying a series. Series without
+// tags are identified by their name.
+func seriesLabels(series *tsdb.TimeSeries) map[string]string {
+	labels := make(map[string]string)
+	for k, v := range series.Tags {
+		labels[k] = v
+	}
+
+	if len(labels) == 0 {
+		labels["__name__"] = series.Name
+	}
+
+	return labels
 }
 
 func (c *QueryCondition) executeQuery(context *alerting.EvalContext, timeRange *tsdb.TimeRange) (tsdb.TimeSeriesSlice, error) {
+	return executeAlertQuery(context, c.Index, &c.Query, timeRange, c.HandleRequest)
+}
+
+func executeAlertQuery(context *alerting.EvalContext, index int, query *AlertQuery, timeRange *tsdb.TimeRange, handleRequest tsdb.HandleRequestFunc) (tsdb.TimeSeriesSlice, error) {
 	getDsInfo := &m.GetDataSourceByIdQuery{
-		Id:    c.Query.DatasourceId,
+		Id:    query.DatasourceId,
 		OrgId: context.Rule.OrgId,
 	}
 
@@ -109,10 +153,10 @@ func (c *QueryCondition) executeQuery(context *alerting.EvalContext, timeRange *
 		return nil, fmt.Errorf("Could not find datasource %v", err)
 	}
 
-	req := c.getRequestForAlertRule(getDsInfo.Result, timeRange)
+	req := getRequestForAlertQuery(query, getDsInfo.Result, timeRange)
 	result := make(tsdb.TimeSeriesSlice, 0)
 
-	resp, err := c.HandleRequest(context.Ctx, getDsInfo.Result, req)
+	resp, err := handleRequest(context.Ctx, getDsInfo.Result, req)
 	if err != nil {
 		if err == gocontext.DeadlineExceeded {
 			return nil, fmt.Errorf("Alert execution exceeded the timeout")
@@ -126,12 +170,25 @@ func (c *QueryCondition) executeQuery(context *alerting.EvalContext, timeRange *
 			return nil, fmt.Errorf("tsdb.HandleRequest() response error %v", v)
 		}
 
-		result = append(result, v.Series...)
+		series := v.Series
+		for _, table := range v.Tables {
+			if query.Table == nil {
+				break
+			}
+
+			tableSeries, err := tsdb.TableToTimeSeries(table, query.Table, timeRange)
+			if err != nil {
+				return nil, err
+			}
+			series = append(series, tableSeries...)
+		}
+
+		result = append(result, series...)
 
 		if context.IsTestRun {
 			context.Logs = append(context.Logs, &alerting.ResultLogEntry{
-				Message: fmt.Sprintf("Condition[%d]: Query Result", c.Index),
-				Data:    v.Series,
+				Message: fmt.Sprintf("Condition[%d]: Query Result", index),
+				Data:    series,
 			})
 		}
 	}
@@ -139,13 +196,14 @@ func (c *QueryCondition) executeQuery(context *alerting.EvalContext, timeRange *
 	return result, nil
 }
 
-func (c *QueryCondition) getRequestForAlertRule(datasource *m.DataSource, timeRange *tsdb.TimeRange) *tsdb.TsdbQuery {
+func getRequestForAlertQuery(query *AlertQuery, datasource *m.DataSource, timeRange *tsdb.TimeRange) *tsdb.TsdbQuery {
 	req := &tsdb.TsdbQuery{
-		TimeRange: timeRange,
+		TimeRange:       timeRange,
+		Transformations: query.Transformations,
 		Queries: []*tsdb.Query{
 			{
 				RefId:      "A",
-				Model:      c.Query.Model,
+				Model:      query.Model,
 				DataSource: datasource,
 			},
 		},
@@ -159,24 +217,17 @@ func NewQueryCondition(model *simplejson.Json, index int) (*QueryCondition, erro
 	condition.Index = index
 	condition.HandleRequest = tsdb.HandleRequest
 
-	queryJson := model.Get("query")
-
-	condition.Query.Model = queryJson.Get("model")
-	condition.Query.From = queryJson.Get("params").MustArray()[1].(string)
-	condition.Query.To = queryJson.Get("params").MustArray()[2].(string)
-
-	if err := validateFromValue(condition.Query.From); err != nil {
+	query, err := newAlertQuery(model.Get("query"))
+	if err != nil {
 		return nil, err
 	}
+	condition.Query = *query
 
-	if err := validateToValue(condition.Query.To); err != nil {
+	reducer, err := NewReducerFromJson(model.Get("reducer"))
+	if err != nil {
 		return nil, err
 	}
-
-	condition.Query.DatasourceId = queryJson.Get("datasourceId").MustInt64()
-
-	reducerJson := model.Get("reducer")
-	condition.Reducer = NewSimpleReducer(reducerJson.Get("type").MustString())
+	condition.Reducer = reducer
 
 	evaluatorJson := model.Get("evaluator")
 	evaluator, err := NewAlertEvaluator(evaluatorJson)
@@ -192,6 +243,50 @@ func NewQueryCondition(model *simplejson.Json, index int) (*QueryCondition, erro
 	return &condition, nil
 }
 
+func newAlertQuery(queryJson *simplejson.Json) (*AlertQuery, error) {
+	query := &AlertQuery{}
+	query.Model = queryJson.Get("model")
+
+	from, err := queryJson.Get("params").GetIndex(1).String()
+	if err != nil {
+		return nil, alerting.ValidationError{Reason: "Query is missing the from parameter"}
+	}
+	query.From = from
+
+	to, err := queryJson.Get("params").GetIndex(2).String()
+	if err != nil {
+		return nil, alerting.ValidationError{Reason: "Query is missing the to parameter"}
+	}
+	query.To = to
+
+	if err := validateFromValue(query.From); err != nil {
+		return nil, err
+	}
+
+	if err := validateToValue(query.To); err != nil {
+		return nil, err
+	}
+
+	// tables are only turned into series when the query has table options,
+	// other tables like log lines or raw documents are ignored
+	if tableJson, hasTable := queryJson.CheckGet("table"); hasTable {
+		table, err := tsdb.NewTableSeriesOptions(tableJson)
+		if err != nil {
+			return nil, alerting.ValidationError{Reason: err.Error()}
+		}
+		query.Table = table
+	}
+
+	transformations, err := tsdb.NewTransformations(queryJson.Get("transformations").MustArray())
+	if err != nil {
+		return nil, alerting.ValidationError{Reason: err.Error()}
+	}
+	query.Transformations = transformations
+
+	query.DatasourceId = queryJson.Get("datasourceId").MustInt64()
+	return query, nil
+}
+
 func validateFromValue(from string) error {
 	fromRaw := strings.Replace(from, "now-", "", 1)
 
@@ -3,6 +3,7 @@ package conditions
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/null"
@@ -71,6 +72,21 @@ func TestQueryCondition(t *testing.T) {
 				So(cr.Firing, ShouldBeTrue)
 			})
 
+			Convey("Should return a series result per series", func() {
+				ctx.series = tsdb.TimeSeriesSlice{
+					&tsdb.TimeSeries{Name: "cpu", Tags: map[string]string{"host": "a"}, Points: tsdb.NewTimeSeriesPointsFromArgs(120, 0)},
+					tsdb.NewTimeSeries("test2", tsdb.NewTimeSeriesPointsFromArgs(0, 0)),
+				}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(len(cr.SeriesResults), ShouldEqual, 2)
+				So(cr.SeriesResults[0].Labels, ShouldResemble, map[string]string{"host": "a"})
+				So(cr.SeriesResults[0].Firing, ShouldBeTrue)
+				So(cr.SeriesResults[1].Labels, ShouldResemble, map[string]string{"__name__": "test2"})
+				So(cr.SeriesResults[1].Firing, ShouldBeFalse)
+			})
+
 			Convey("No series", func() {
 				Convey("Should set NoDataFound when condition is gt", func() {
 					ctx.series = tsdb.TimeSeriesSlice{}
@@ -137,6 +153,100 @@ func TestQueryCondition(t *testing.T) {
 				})
 			})
 		})
+		queryConditionScenario("Given last() and percent change above 50% compared to a day ago", func(ctx *queryConditionTestContext) {
+			ctx.reducer = `{"type": "last"}`
+			ctx.evaluator = `{"type": "percent_change", "params": [50, "1d"]}`
+			ctx.baseline = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(100, 0))}
+
+			Convey("should fire when value doubled", func() {
+				ctx.series = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(200, 0))}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeTrue)
+			})
+
+			Convey("should not fire when value barely changed", func() {
+				ctx.series = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(110, 0))}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeFalse)
+			})
+		})
+
+		queryConditionScenario("Given a table result and last() > 100", func(ctx *queryConditionTestContext) {
+			ctx.reducer = `{"type": "last"}`
+			ctx.evaluator = `{"type": "gt", "params": [100]}`
+			ctx.table = `{"valueColumn": "revenue"}`
+			ctx.tables = []*tsdb.Table{
+				{
+					Columns: []tsdb.TableColumn{{Text: "shop"}, {Text: "orders"}, {Text: "revenue"}},
+					Rows: []tsdb.RowValues{
+						{"berlin", int64(3), float64(150)},
+						{"paris", int64(7), float64(80)},
+					},
+				},
+			}
+
+			Convey("Should evaluate every row as an alert instance", func() {
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeTrue)
+				So(cr.EvalMatches, ShouldHaveLength, 1)
+				So(cr.EvalMatches[0].Metric, ShouldEqual, "revenue{shop=berlin}")
+				So(cr.SeriesResults, ShouldHaveLength, 2)
+				So(cr.SeriesResults[0].Labels, ShouldResemble, map[string]string{"shop": "berlin"})
+				So(cr.SeriesResults[1].Firing, ShouldBeFalse)
+			})
+
+			Convey("Should fail when the value column is missing", func() {
+				ctx.table = `{"valueColumn": "profit"}`
+				_, err := ctx.exec()
+
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Should ignore tables of queries without table options", func() {
+				ctx.table = ""
+				ctx.tables[0].Columns = []tsdb.TableColumn{{Text: "time"}, {Text: "line"}}
+				ctx.tables[0].Rows = []tsdb.RowValues{{float64(1500000000000), "started"}}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeFalse)
+				So(cr.NoDataFound, ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Reading the transformations of alert queries", t, func() {
+		newQuery := func(transformations string) (*AlertQuery, error) {
+			queryJson, err := simplejson.NewJson([]byte(`{
+				"params": ["A", "5m", "now"],
+				"datasourceId": 1,
+				"model": {"target": "servers.*.cpu"},
+				"transformations": ` + transformations + `
+			}`))
+			So(err, ShouldBeNil)
+			return newAlertQuery(queryJson)
+		}
+
+		Convey("Attaches the transformations to the request", func() {
+			query, err := newQuery(`[{"type": "filter_by_name", "pattern": "web"}, {"type": "time_shift", "shift": "1h"}]`)
+			So(err, ShouldBeNil)
+			So(query.Transformations, ShouldHaveLength, 2)
+
+			req := getRequestForAlertQuery(query, &m.DataSource{Id: 1}, tsdb.NewTimeRange("5m", "now"))
+			So(req.Transformations, ShouldResemble, query.Transformations)
+		})
+
+		Convey("Fails validation on unknown transformations", func() {
+			_, err := newQuery(`[{"type": "pivot"}]`)
+
+			So(err, ShouldHaveSameTypeAs, alerting.ValidationError{})
+		})
 	})
 }
 
@@ -144,6 +254,9 @@ type queryConditionTestContext struct {
 	reducer   string
 	evaluator string
 	series    tsdb.TimeSeriesSlice
+	baseline  tsdb.TimeSeriesSlice
+	tables    []*tsdb.Table
+	table     string
 	result    *alerting.EvalContext
 	condition *QueryCondition
 }
@@ -151,12 +264,18 @@ type queryConditionTestContext struct {
 type queryConditionScenarioFunc func(c *queryConditionTestContext)
 
 func (ctx *queryConditionTestContext) exec() (*alerting.ConditionResult, error) {
+	table := ""
+	if ctx.table != "" {
+		table = `,
+              "table": ` + ctx.table
+	}
+
 	jsonModel, err := simplejson.NewJson([]byte(`{
             "type": "query",
             "query":  {
               "params": ["A", "5m", "now"],
               "datasourceId": 1,
-              "model": {"target": "aliasByNode(statsd.fakesite.counters.session_start.mobile.count, 4)"}
+              "model": {"target": "aliasByNode(statsd.fakesite.counters.session_start.mobile.count, 4)"}` + table + `
             },
             "reducer":` + ctx.reducer + `,
             "evaluator":` + ctx.evaluator + `
@@ -169,9 +288,14 @@ func (ctx *queryConditionTestContext) exec() (*alerting.ConditionResult, error)
 	ctx.condition = condition
 
 	condition.HandleRequest = func(context context.Context, dsInfo *m.DataSource, req *tsdb.TsdbQuery) (*tsdb.Response, error) {
+		series := ctx.series
+		if req.TimeRange.MustGetTo().Before(time.Now().Add(-time.Hour)) {
+			series = ctx.baseline
+		}
+
 		return &tsdb.Response{
 			Results: map[string]*tsdb.QueryResult{
-				"A": {Series: ctx.series},
+				"A": {Series: series, Tables: ctx.tables},
 			},
 		}, nil
 	}
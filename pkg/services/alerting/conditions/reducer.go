@@ -1,165 +1,80 @@
 package conditions
 
 import (
-	"math"
-
-	"sort"
+	"fmt"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/alerting"
 	"github.com/grafana/grafana/pkg/tsdb"
 )
 
+var paramReducerTypes = []string{"percentile", "count_above"}
+
 type QueryReducer interface {
 	Reduce(timeSeries *tsdb.TimeSeries) null.Float
 }
 
+// SimpleReducer reduces a series to a single value. Params holds the
+// arguments of parameterised reducers, e.g. the percentile for
+// `percentile` or the threshold for `count_above`.
 type SimpleReducer struct {
-	Type string
+	Type   string
+	Params []float64
 }
 
 func (s *SimpleReducer) Reduce(series *tsdb.TimeSeries) null.Float {
-	if len(series.Points) == 0 {
-		return null.FloatFromPtr(nil)
-	}
+	return tsdb.ReduceSeries(s.Type, s.Params, series)
+}
 
-	value := float64(0)
-	allNull := true
-
-	switch s.Type {
-	case "avg":
-		validPointsCount := 0
-		for _, point := range series.Points {
-			if point[0].Valid {
-				value += point[0].Float64
-				validPointsCount += 1
-				allNull = false
-			}
-		}
-		if validPointsCount > 0 {
-			value = value / float64(validPointsCount)
-		}
-	case "sum":
-		for _, point := range series.Points {
-			if point[0].Valid {
-				value += point[0].Float64
-				allNull = false
-			}
-		}
-	case "min":
-		value = math.MaxFloat64
-		for _, point := range series.Points {
-			if point[0].Valid {
-				allNull = false
-				if value > point[0].Float64 {
-					value = point[0].Float64
-				}
-			}
-		}
-	case "max":
-		value = -math.MaxFloat64
-		for _, point := range series.Points {
-			if point[0].Valid {
-				allNull = false
-				if value < point[0].Float64 {
-					value = point[0].Float64
-				}
-			}
-		}
-	case "count":
-		value = float64(len(series.Points))
-		allNull = false
-	case "last":
-		points := series.Points
-		for i := len(points) - 1; i >= 0; i-- {
-			if points[i][0].Valid {
-				value = points[i][0].Float64
-				allNull = false
-				break
-			}
-		}
-	case "median":
-		var values []float64
-		for _, v := range series.Points {
-			if v[0].Valid {
-				allNull = false
-				values = append(values, v[0].Float64)
-			}
-		}
-		if len(values) >= 1 {
-			sort.Float64s(values)
-			length := len(values)
-			if length%2 == 1 {
-				value = values[(length-1)/2]
-			} else {
-				value = (values[(length/2)-1] + values[length/2]) / 2
-			}
-		}
-	case "diff":
-		var (
-			points = series.Points
-			first  float64
-			i      int
-		)
-		// get the newest point
-		for i = len(points) - 1; i >= 0; i-- {
-			if points[i][0].Valid {
-				allNull = false
-				first = points[i][0].Float64
-				break
-			}
-		}
-		// get the oldest point
-		points = points[0:i]
-		for i := 0; i < len(points); i++ {
-			if points[i][0].Valid {
-				allNull = false
-				value = first - points[i][0].Float64
-				break
-			}
-		}
-	case "percent_diff":
-		var (
-			points = series.Points
-			first  float64
-			i      int
-		)
-		// get the newest point
-		for i = len(points) - 1; i >= 0; i-- {
-			if points[i][0].Valid {
-				allNull = false
-				first = points[i][0].Float64
-				break
-			}
-		}
-		// get the oldest point
-		points = points[0:i]
-		for i := 0; i < len(points); i++ {
-			if points[i][0].Valid {
-				allNull = false
-				val := (first - points[i][0].Float64) / points[i][0].Float64 * 100
-				value = math.Abs(val)
-				break
-			}
-		}
-	case "count_non_null":
-		for _, v := range series.Points {
-			if v[0].Valid {
-				value++
-			}
+// NewSimpleReducer creates a reducer without parameters. Percentiles can
+// be given in short form, e.g. `p95`.
+func NewSimpleReducer(typ string) *SimpleReducer {
+	typ, params := tsdb.ParseReducer(typ)
+	return &SimpleReducer{Type: typ, Params: params}
+}
+
+// NewReducerFromJson creates a reducer from a model like
+// `{"type": "percentile", "params": [95]}`.
+func NewReducerFromJson(model *simplejson.Json) (*SimpleReducer, error) {
+	reducer := NewSimpleReducer(model.Get("type").MustString())
+
+	for i := range model.Get("params").MustArray() {
+		param, err := reducerParam(model.Get("params").GetIndex(i))
+		if err != nil {
+			return nil, alerting.ValidationError{Reason: fmt.Sprintf("Reducer %s has invalid parameter", reducer.Type)}
 		}
 
-		if value > 0 {
-			allNull = false
+		if reducer.Type == "percentile" && len(reducer.Params) > 0 {
+			// keep the percentile given in short form
+			continue
 		}
+		reducer.Params = append(reducer.Params, param)
 	}
 
-	if allNull {
-		return null.FloatFromPtr(nil)
+	if inSlice(reducer.Type, paramReducerTypes) && len(reducer.Params) == 0 {
+		return nil, alerting.ValidationError{Reason: fmt.Sprintf("Reducer %s requires a parameter", reducer.Type)}
 	}
 
-	return null.FloatFrom(value)
+	if reducer.Type == "percentile" && (reducer.Params[0] < 0 || reducer.Params[0] > 100) {
+		return nil, alerting.ValidationError{Reason: "Reducer percentile must be between 0 and 100"}
+	}
+
+	return reducer, nil
 }
 
-func NewSimpleReducer(typ string) *SimpleReducer {
-	return &SimpleReducer{Type: typ}
+// reducerParam reads a numeric parameter, which the alert tab stores as
+// a string after it has been edited.
+func reducerParam(param *simplejson.Json) (float64, error) {
+	if value, err := param.Float64(); err == nil {
+		return value, nil
+	}
+
+	str, err := param.String()
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(str, 64)
 }
@@ -6,6 +6,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/tsdb"
 )
 
@@ -157,6 +158,101 @@ func TestSimpleReducer(t *testing.T) {
 			result := testReducer("percent_diff", 30, 40, 40)
 			So(result, ShouldEqual, float64(33.33333333333333))
 		})
+
+		Convey("stddev", func() {
+			result := testReducer("stddev", 2, 4, 4, 4, 5, 5, 7, 9)
+			So(result, ShouldEqual, float64(2))
+		})
+
+		Convey("stddev should ignore null values", func() {
+			series := &tsdb.TimeSeries{Name: "test time serie"}
+			series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(1), 1))
+			series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFromPtr(nil), 2))
+			series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(3), 3))
+
+			So(NewSimpleReducer("stddev").Reduce(series).Float64, ShouldEqual, float64(1))
+		})
+
+		Convey("percentile", func() {
+			So(testReducer("p50", 1, 2, 3, 4, 5), ShouldEqual, float64(3))
+			So(testReducer("p95", 5, 1, 4, 2, 3), ShouldEqual, float64(4.8))
+			So(testReducer("p100", 1, 2, 3), ShouldEqual, float64(3))
+			So(testReducer("p0", 1, 2, 3), ShouldEqual, float64(1))
+			So(testReducer("p90", 7), ShouldEqual, float64(7))
+		})
+
+		Convey("rate", func() {
+			series := &tsdb.TimeSeries{Name: "test time serie"}
+			series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(100), 0))
+			series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(110), 10000))
+			series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFromPtr(nil), 15000))
+			series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(130), 20000))
+
+			So(NewSimpleReducer("rate").Reduce(series).Float64, ShouldEqual, float64(1.5))
+
+			Convey("should handle counter resets", func() {
+				series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(10), 30000))
+				series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(20), 40000))
+
+				So(NewSimpleReducer("rate").Reduce(series).Float64, ShouldEqual, float64(1.25))
+			})
+
+			Convey("should be null with a single point", func() {
+				So(NewSimpleReducer("rate").Reduce(&tsdb.TimeSeries{Points: series.Points[:1]}).Valid, ShouldBeFalse)
+			})
+		})
+
+		Convey("count_above", func() {
+			reducer := &SimpleReducer{Type: "count_above", Params: []float64{10}}
+			series := &tsdb.TimeSeries{Name: "test time serie"}
+			for _, v := range []float64{5, 10, 11, 50} {
+				series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(v), 1))
+			}
+
+			So(reducer.Reduce(series).Float64, ShouldEqual, float64(2))
+
+			reducer.Params = []float64{100}
+			result := reducer.Reduce(series)
+			So(result.Valid, ShouldBeTrue)
+			So(result.Float64, ShouldEqual, float64(0))
+		})
+	})
+
+	Convey("Test reducer from json model", t, func() {
+		newReducer := func(json string) (*SimpleReducer, error) {
+			model, err := simplejson.NewJson([]byte(json))
+			So(err, ShouldBeNil)
+			return NewReducerFromJson(model)
+		}
+
+		Convey("should read params", func() {
+			reducer, err := newReducer(`{"type": "percentile", "params": [99]}`)
+			So(err, ShouldBeNil)
+			So(reducer.Params, ShouldResemble, []float64{99})
+
+			reducer, err = newReducer(`{"type": "count_above", "params": ["2.5"]}`)
+			So(err, ShouldBeNil)
+			So(reducer.Params, ShouldResemble, []float64{2.5})
+		})
+
+		Convey("should read percentile short form", func() {
+			reducer, err := newReducer(`{"type": "p95", "params": []}`)
+			So(err, ShouldBeNil)
+			So(reducer.Type, ShouldEqual, "percentile")
+			So(reducer.Params, ShouldResemble, []float64{95})
+		})
+
+		Convey("should validate params", func() {
+			for _, json := range []string{
+				`{"type": "percentile", "params": []}`,
+				`{"type": "percentile", "params": [101]}`,
+				`{"type": "count_above"}`,
+				`{"type": "count_above", "params": ["ten"]}`,
+			} {
+				_, err := newReducer(json)
+				So(err, ShouldNotBeNil)
+			}
+		})
 	})
 }
 
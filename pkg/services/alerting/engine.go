@@ -10,7 +10,9 @@ import (
 	tlog "github.com/opentracing/opentracing-go/log"
 
 	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/setting"
@@ -45,20 +47,40 @@ func (e *AlertingService) IsDisabled() bool {
 }
 
 func (e *AlertingService) Init() error {
+	if setting.AlertingEvaluationTimeout > 0 {
+		alertTimeout = setting.AlertingEvaluationTimeout
+	}
+	if setting.AlertingMaxAttempts > 0 {
+		alertMaxAttempts = setting.AlertingMaxAttempts
+	}
+	if setting.AlertingMaxConcurrentEvaluations > 0 {
+		alertMaxConcurrentEvaluations = setting.AlertingMaxConcurrentEvaluations
+	}
+
+	queueSize := 1000
+	if setting.AlertingEvaluationQueueSize > 0 {
+		queueSize = setting.AlertingEvaluationQueueSize
+	}
+
 	e.ticker = NewTicker(time.Now(), time.Second*0, clock.New())
-	e.execQueue = make(chan *Job, 1000)
+	e.execQueue = make(chan *Job, queueSize)
 	e.scheduler = NewScheduler()
 	e.evalHandler = NewEvalHandler()
 	e.ruleReader = NewRuleReader()
 	e.log = log.New("alerting.engine")
 	e.resultHandler = NewResultHandler(e.RenderService)
+
+	receiver := newAlertReceiver(NewNotificationService(e.RenderService))
+	bus.AddHandler("alerting", receiver.handleReceiveAlertsCommand)
 	return nil
 }
 
 func (e *AlertingService) Run(ctx context.Context) error {
 	alertGroup, ctx := errgroup.WithContext(ctx)
+	alertGroup.Go(func() error { return e.ruleReader.Run(ctx) })
 	alertGroup.Go(func() error { return e.alertingTicker(ctx) })
 	alertGroup.Go(func() error { return e.runJobDispatcher(ctx) })
+	alertGroup.Go(func() error { return e.escalationTicker(ctx) })
 
 	err := alertGroup.Wait()
 	return err
@@ -89,24 +111,47 @@ func (e *AlertingService) alertingTicker(grafanaCtx context.Context) error {
 	}
 }
 
+// runJobDispatcher evaluates the queued jobs with a fixed number of
+// workers, so a slow datasource delays other rules instead of piling up
+// goroutines.
 func (e *AlertingService) runJobDispatcher(grafanaCtx context.Context) error {
 	dispatcherGroup, alertCtx := errgroup.WithContext(grafanaCtx)
 
+	for i := 0; i < alertMaxConcurrentEvaluations; i++ {
+		dispatcherGroup.Go(func() error { return e.runJobWorker(alertCtx) })
+	}
+
+	return dispatcherGroup.Wait()
+}
+
+func (e *AlertingService) runJobWorker(alertCtx context.Context) error {
 	for {
 		select {
-		case <-grafanaCtx.Done():
-			return dispatcherGroup.Wait()
+		case <-alertCtx.Done():
+			return nil
 		case job := <-e.execQueue:
-			dispatcherGroup.Go(func() error { return e.processJobWithRetry(alertCtx, job) })
+			metrics.M_Alerting_Queue_Depth.Set(float64(len(e.execQueue)))
+			if err := e.processJobWithRetry(alertCtx, job); err != nil {
+				return err
+			}
 		}
 	}
 }
 
 var (
 	unfinishedWorkTimeout = time.Second * 5
-	// TODO: Make alertTimeout and alertMaxAttempts configurable in the config file.
-	alertTimeout     = time.Second * 30
-	alertMaxAttempts = 3
+
+	// timeouts of the work done outside of scheduled rule evaluations, they
+	// do not follow the evaluation_timeout setting
+	notificationTimeout = time.Second * 30
+	renderTimeout       = time.Second * 15
+	receiveTimeout      = time.Second * 30
+	backtestTimeout     = time.Second * 30
+
+	// defaults for the [alerting] settings, applied in Init
+	alertTimeout                  = time.Second * 30
+	alertMaxAttempts              = 3
+	alertMaxConcurrentEvaluations = 20
 )
 
 func (e *AlertingService) processJobWithRetry(grafanaCtx context.Context, job *Job) error {
@@ -160,7 +205,12 @@ func (e *AlertingService) processJob(attemptID int, attemptChan chan int, cancel
 		}
 	}()
 
-	alertCtx, cancelFn := context.WithTimeout(context.Background(), alertTimeout)
+	timeout := alertTimeout
+	if job.Rule.Timeout > 0 {
+		timeout = job.Rule.Timeout
+	}
+
+	alertCtx, cancelFn := context.WithTimeout(context.Background(), timeout)
 	cancelChan <- cancelFn
 	span := opentracing.StartSpan("alert execution")
 	alertCtx = opentracing.ContextWithSpan(alertCtx, span)
@@ -205,6 +255,7 @@ func (e *AlertingService) processJob(attemptID int, attemptChan chan int, cancel
 		}
 
 		evalContext.Rule.State = evalContext.GetNewState()
+		evalContext.UpdateInstanceStates()
 		e.resultHandler.Handle(evalContext)
 		span.Finish()
 		e.log.Debug("Job Execution completed", "timeMs", evalContext.GetDurationMs(), "alertId", evalContext.Rule.Id, "name", evalContext.Rule.Name, "firing", evalContext.Firing, "attemptID", attemptID)
@@ -116,3 +116,28 @@ func TestEngineProcessJob(t *testing.T) {
 		})
 	})
 }
+
+func TestSchedulerBackpressure(t *testing.T) {
+	Convey("Scheduler backpressure", t, func() {
+		scheduler := NewScheduler().(*SchedulerImpl)
+		execQueue := make(chan *Job, 1)
+		first := &Job{Rule: &Rule{Id: 1}}
+		second := &Job{Rule: &Rule{Id: 2}}
+
+		Convey("Queued jobs are marked as running", func() {
+			scheduler.enqueue(first, execQueue)
+			So(first.Running, ShouldBeTrue)
+			So(len(execQueue), ShouldEqual, 1)
+		})
+
+		Convey("Jobs are skipped while the queue is full", func() {
+			scheduler.skipWhenFull = true
+			scheduler.enqueue(first, execQueue)
+			scheduler.enqueue(second, execQueue)
+
+			So(first.Running, ShouldBeTrue)
+			So(second.Running, ShouldBeFalse)
+			So(<-execQueue, ShouldEqual, first)
+		})
+	})
+}
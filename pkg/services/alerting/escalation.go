@@ -0,0 +1,421 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+const (
+	EscalationNotifierType = "escalation"
+
+	escalationTickInterval = 10 * time.Second
+)
+
+// EscalationStep notifies a notification channel once the previous step
+// has not been acknowledged within Delay.
+type EscalationStep struct {
+	NotifierUid string
+	Delay       time.Duration
+}
+
+// ParseEscalationSteps reads the steps setting of an escalation channel,
+// e.g. [{"notifierUid": "team-slack", "delay": "0s"}, {"notifierUid": "on-call", "delay": "15m"}].
+func ParseEscalationSteps(settings *simplejson.Json) ([]*EscalationStep, error) {
+	steps := make([]*EscalationStep, 0)
+
+	for i, value := range settings.Get("steps").MustArray() {
+		stepJson := simplejson.NewFromAny(value)
+
+		uid := stepJson.Get("notifierUid").MustString()
+		if uid == "" {
+			return nil, ValidationError{Reason: fmt.Sprintf("Escalation step %d is missing notifierUid", i+1)}
+		}
+
+		delay, err := time.ParseDuration(stepJson.Get("delay").MustString("0s"))
+		if err != nil || delay < 0 {
+			return nil, ValidationError{Reason: fmt.Sprintf("Escalation step %d has an invalid delay", i+1)}
+		}
+
+		steps = append(steps, &EscalationStep{NotifierUid: uid, Delay: delay})
+	}
+
+	if len(steps) == 0 {
+		return nil, ValidationError{Reason: "Escalation requires at least one step"}
+	}
+
+	return steps, nil
+}
+
+func escalationInstanceId(evalContext *EvalContext) int64 {
+	if evalContext.Instance != nil {
+		return evalContext.Instance.Id
+	}
+	return 0
+}
+
+// StartEscalation restarts the escalation chain of an alert that started
+// firing. A first step without delay is notified right away. Test runs
+// notify every step without storing any state.
+func StartEscalation(evalContext *EvalContext, notifierId int64, steps []*EscalationStep) error {
+	if evalContext.IsTestRun {
+		for _, step := range steps {
+			if err := sendEscalationStep(evalContext, step); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// reminders must not restart an escalation that is already running
+	if evalContext.PrevAlertState == evalContext.Rule.State {
+		query := &m.GetAlertEscalationQuery{
+			OrgId:      evalContext.Rule.OrgId,
+			AlertId:    evalContext.Rule.Id,
+			InstanceId: escalationInstanceId(evalContext),
+			NotifierId: notifierId,
+		}
+
+		err := bus.DispatchCtx(evalContext.Ctx, query)
+		if err == nil {
+			return nil
+		}
+		if err != m.ErrAlertEscalationNotFound {
+			return err
+		}
+	}
+
+	now := time.Now()
+
+	cmd := &m.StartAlertEscalationCommand{
+		OrgId:      evalContext.Rule.OrgId,
+		AlertId:    evalContext.Rule.Id,
+		InstanceId: escalationInstanceId(evalContext),
+		NotifierId: notifierId,
+		NextStepAt: now.Add(steps[0].Delay).Unix(),
+	}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		return err
+	}
+
+	if steps[0].Delay > 0 {
+		return nil
+	}
+
+	return advanceEscalation(evalContext, cmd.Result, steps, now)
+}
+
+// ResolveEscalation sends the resolved notification to every step that was
+// notified and removes the escalation.
+func ResolveEscalation(evalContext *EvalContext, notifierId int64, steps []*EscalationStep) error {
+	query := &m.GetAlertEscalationQuery{
+		OrgId:      evalContext.Rule.OrgId,
+		AlertId:    evalContext.Rule.Id,
+		InstanceId: escalationInstanceId(evalContext),
+		NotifierId: notifierId,
+	}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, query); err != nil {
+		if err == m.ErrAlertEscalationNotFound {
+			return nil
+		}
+		return err
+	}
+
+	for i := 0; i <= query.Result.Step && i < len(steps); i++ {
+		if err := sendEscalationStep(evalContext, steps[i]); err != nil {
+			evalContext.log.Error("Failed to send resolved escalation step", "alertId", evalContext.Rule.Id, "step", i+1, "error", err)
+		}
+	}
+
+	return bus.DispatchCtx(evalContext.Ctx, &m.DeleteAlertEscalationCommand{
+		OrgId:      query.OrgId,
+		AlertId:    query.AlertId,
+		InstanceId: query.InstanceId,
+		NotifierId: query.NotifierId,
+	})
+}
+
+// advanceEscalation claims the next step of the escalation and notifies its
+// channel. A step claimed by another server is skipped. While the alert is
+// silenced the escalation is paused until the silence ends.
+func advanceEscalation(evalContext *EvalContext, state *m.AlertEscalationState, steps []*EscalationStep, now time.Time) error {
+	next := state.Step + 1
+	if next >= len(steps) {
+		return nil
+	}
+
+	silence, err := evalContext.getActiveSilence()
+	if err != nil {
+		evalContext.log.Error("Failed to check alert silences", "error", err)
+	} else if silence != nil {
+		evalContext.log.Info("Alert is silenced, pausing escalation", "alertId", evalContext.Rule.Id, "silenceId", silence.Id)
+		return pauseEscalation(evalContext, state, silence.EndsAt)
+	}
+
+	var nextStepAt int64
+	if next+1 < len(steps) {
+		nextStepAt = now.Add(steps[next+1].Delay).Unix()
+	}
+
+	cmd := &m.AdvanceAlertEscalationCommand{
+		Id:         state.Id,
+		Step:       state.Step,
+		NextStep:   next,
+		NextStepAt: nextStepAt,
+	}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		if err == m.ErrAlertEscalationConflict {
+			return nil
+		}
+		return err
+	}
+
+	return sendEscalationStep(evalContext, steps[next])
+}
+
+// pauseEscalation postpones the next step of the escalation until resumeAt.
+func pauseEscalation(evalContext *EvalContext, state *m.AlertEscalationState, resumeAt time.Time) error {
+	cmd := &m.AdvanceAlertEscalationCommand{
+		Id:         state.Id,
+		Step:       state.Step,
+		NextStep:   state.Step,
+		NextStepAt: resumeAt.Unix(),
+	}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil && err != m.ErrAlertEscalationConflict {
+		return err
+	}
+
+	return nil
+}
+
+func sendEscalationStep(evalContext *EvalContext, step *EscalationStep) error {
+	query := &m.GetAlertNotificationsWithUidQuery{OrgId: evalContext.Rule.OrgId, Uid: step.NotifierUid}
+	if err := bus.DispatchCtx(evalContext.Ctx, query); err != nil {
+		return err
+	}
+
+	if query.Result == nil {
+		return fmt.Errorf("Escalation notification channel %s not found", step.NotifierUid)
+	}
+
+	if query.Result.Type == EscalationNotifierType {
+		return fmt.Errorf("Escalation notification channel %s cannot be another escalation", step.NotifierUid)
+	}
+
+	notifier, err := createNotifier(query.Result)
+	if err != nil {
+		return err
+	}
+
+	if evalContext.Rule.State == m.AlertStateOK && notifier.GetDisableResolveMessage() {
+		return nil
+	}
+
+	return notifyWithDelivery(evalContext, notifier)
+}
+
+func (e *AlertingService) escalationTicker(grafanaCtx context.Context) error {
+	ticker := time.NewTicker(escalationTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-grafanaCtx.Done():
+			return grafanaCtx.Err()
+		case <-ticker.C:
+			e.processEscalations()
+		}
+	}
+}
+
+// processEscalations notifies the next step of every escalation that was
+// not acknowledged in time.
+func (e *AlertingService) processEscalations() {
+	query := &m.GetDueAlertEscalationsQuery{Now: time.Now().Unix()}
+	if err := bus.Dispatch(query); err != nil {
+		e.log.Error("Failed to get due alert escalations", "error", err)
+		return
+	}
+
+	for _, state := range query.Result {
+		if err := e.processEscalation(state); err != nil {
+			e.log.Error("Failed to escalate alert", "alertId", state.AlertId, "instanceId", state.InstanceId, "error", err)
+		}
+	}
+}
+
+func (e *AlertingService) processEscalation(state *m.AlertEscalationState) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+	defer cancel()
+
+	deleteCmd := &m.DeleteAlertEscalationCommand{
+		OrgId:      state.OrgId,
+		AlertId:    state.AlertId,
+		InstanceId: state.InstanceId,
+		NotifierId: state.NotifierId,
+	}
+
+	channelQuery := &m.GetAlertNotificationsQuery{OrgId: state.OrgId, Id: state.NotifierId}
+	if err := bus.DispatchCtx(ctx, channelQuery); err != nil {
+		return err
+	}
+
+	if channelQuery.Result == nil || channelQuery.Result.Type != EscalationNotifierType {
+		return bus.DispatchCtx(ctx, deleteCmd)
+	}
+
+	steps, err := ParseEscalationSteps(channelQuery.Result.Settings)
+	if err != nil {
+		return err
+	}
+
+	evalContext, err := newEscalationEvalContext(ctx, state)
+	if err != nil {
+		return err
+	}
+
+	// the alert was resolved, paused or deleted without a notification
+	if evalContext == nil {
+		return bus.DispatchCtx(ctx, deleteCmd)
+	}
+
+	return advanceEscalation(evalContext, state, steps, time.Now())
+}
+
+// newEscalationEvalContext recreates the context of the last evaluation
+// from the stored alert state. It returns nil when the alert is no longer
+// firing or does not exist anymore.
+func newEscalationEvalContext(ctx context.Context, state *m.AlertEscalationState) (*EvalContext, error) {
+	if state.AlertId == 0 {
+		return newReceivedEscalationEvalContext(ctx, state)
+	}
+
+	alertQuery := &m.GetAlertByIdQuery{Id: state.AlertId}
+	if err := bus.DispatchCtx(ctx, alertQuery); err != nil {
+		if err == m.ErrAlertNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	alert := alertQuery.Result
+	if alert.OrgId != state.OrgId {
+		return nil, nil
+	}
+
+	rule := &Rule{
+		Id:              alert.Id,
+		OrgId:           alert.OrgId,
+		DashboardId:     alert.DashboardId,
+		PanelId:         alert.PanelId,
+		Name:            alert.Name,
+		Message:         alert.Message,
+		State:           alert.State,
+		StateChanges:    alert.StateChanges,
+		LastStateChange: alert.NewStateDate,
+	}
+
+	evalContext := NewEvalContext(ctx, rule)
+	evalContext.Firing = alert.State == m.AlertStateAlerting
+	evalContext.NoDataFound = alert.State == m.AlertStateNoData
+	evalContext.EvalMatches = evalMatchesFromData(alert.EvalData)
+
+	if state.InstanceId != 0 {
+		stored, err := getEscalationInstance(ctx, state)
+		if err != nil || stored == nil {
+			return nil, err
+		}
+
+		evalContext = evalContext.ForInstance(escalationInstance(stored))
+	}
+
+	if evalContext.Rule.State != m.AlertStateAlerting && evalContext.Rule.State != m.AlertStateNoData {
+		return nil, nil
+	}
+
+	return evalContext, nil
+}
+
+// newReceivedEscalationEvalContext recreates the context of a received
+// alert. Received alerts have no alert rule, the rule is rebuilt from the
+// stored instance the same way the receiver builds it.
+func newReceivedEscalationEvalContext(ctx context.Context, state *m.AlertEscalationState) (*EvalContext, error) {
+	stored, err := getEscalationInstance(ctx, state)
+	if err != nil || stored == nil {
+		return nil, err
+	}
+
+	if stored.State != m.AlertStateAlerting {
+		return nil, nil
+	}
+
+	rule := &Rule{
+		OrgId:       stored.OrgId,
+		DashboardId: stored.DashboardId,
+		State:       stored.State,
+	}
+	if stored.EvalData != nil {
+		rule.PanelId = stored.EvalData.Get("panelId").MustInt64()
+		rule.Name = stored.EvalData.Get("title").MustString()
+		rule.Message = stored.EvalData.Get("message").MustString()
+	}
+	if rule.Name == "" {
+		rule.Name = stored.Labels["alertname"]
+	}
+
+	return NewEvalContext(ctx, rule).ForInstance(escalationInstance(stored)), nil
+}
+
+func getEscalationInstance(ctx context.Context, state *m.AlertEscalationState) (*m.AlertInstance, error) {
+	query := &m.GetAlertInstancesQuery{OrgId: state.OrgId, AlertId: state.AlertId, Id: state.InstanceId}
+	if err := bus.DispatchCtx(ctx, query); err != nil {
+		return nil, err
+	}
+
+	if len(query.Result) == 0 {
+		return nil, nil
+	}
+
+	return query.Result[0], nil
+}
+
+func escalationInstance(stored *m.AlertInstance) *Instance {
+	return &Instance{
+		Id:              stored.Id,
+		Labels:          stored.Labels,
+		Firing:          stored.State == m.AlertStateAlerting,
+		NoDataFound:     stored.State == m.AlertStateNoData,
+		State:           stored.State,
+		PrevState:       stored.State,
+		EvalMatches:     evalMatchesFromData(stored.EvalData),
+		StateChanges:    stored.StateChanges,
+		LastStateChange: stored.NewStateDate,
+	}
+}
+
+func evalMatchesFromData(evalData *simplejson.Json) []*EvalMatch {
+	matches := make([]*EvalMatch, 0)
+	if evalData == nil {
+		return matches
+	}
+
+	data, err := evalData.Get("evalMatches").MarshalJSON()
+	if err != nil {
+		return matches
+	}
+
+	if err := json.Unmarshal(data, &matches); err != nil || matches == nil {
+		return make([]*EvalMatch, 0)
+	}
+
+	return matches
+}
@@ -0,0 +1,193 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeEscalationTarget struct {
+	fakeGroupedNotifier
+	uid  string
+	sent *[]string
+}
+
+func (n *fakeEscalationTarget) Notify(evalContext *EvalContext) error {
+	*n.sent = append(*n.sent, n.uid)
+	return nil
+}
+
+func TestEscalation(t *testing.T) {
+	Convey("Escalation chains", t, func() {
+		Convey("Should parse steps from settings", func() {
+			settings := simplejson.NewFromAny(map[string]interface{}{
+				"steps": []interface{}{
+					map[string]interface{}{"notifierUid": "slack"},
+					map[string]interface{}{"notifierUid": "pagerduty", "delay": "10m"},
+				},
+			})
+
+			steps, err := ParseEscalationSteps(settings)
+			So(err, ShouldBeNil)
+			So(len(steps), ShouldEqual, 2)
+			So(steps[0].Delay, ShouldEqual, 0)
+			So(steps[1].Delay, ShouldEqual, 10*time.Minute)
+
+			_, err = ParseEscalationSteps(simplejson.New())
+			So(err, ShouldHaveSameTypeAs, ValidationError{})
+
+			settings.Set("steps", []interface{}{map[string]interface{}{"delay": "1m"}})
+			_, err = ParseEscalationSteps(settings)
+			So(err, ShouldHaveSameTypeAs, ValidationError{})
+		})
+
+		Convey("Should notify steps as the escalation advances", func() {
+			sent := make([]string, 0)
+			RegisterNotifier(&NotifierPlugin{
+				Type: "escalation-test-target",
+				Factory: func(model *m.AlertNotification) (Notifier, error) {
+					return &fakeEscalationTarget{uid: model.Uid, sent: &sent}, nil
+				},
+			})
+
+			state := &m.AlertEscalationState{Id: 1, Step: -1}
+			var advanced *m.AdvanceAlertEscalationCommand
+			deleted := false
+
+			bus.ClearBusHandlers()
+			bus.AddHandlerCtx("test", func(ctx context.Context, query *m.GetAlertNotificationsWithUidQuery) error {
+				query.Result = &m.AlertNotification{Uid: query.Uid, Type: "escalation-test-target", Settings: simplejson.New()}
+				return nil
+			})
+			bus.AddHandlerCtx("test", func(ctx context.Context, cmd *m.StartAlertEscalationCommand) error {
+				cmd.Result = state
+				return nil
+			})
+			bus.AddHandlerCtx("test", func(ctx context.Context, query *m.GetAlertEscalationQuery) error {
+				query.Result = state
+				return nil
+			})
+			bus.AddHandlerCtx("test", func(ctx context.Context, cmd *m.AdvanceAlertEscalationCommand) error {
+				if cmd.Step != state.Step {
+					return m.ErrAlertEscalationConflict
+				}
+				advanced = cmd
+				state.Step = cmd.NextStep
+				state.NextStepAt = cmd.NextStepAt
+				return nil
+			})
+			bus.AddHandlerCtx("test", func(ctx context.Context, cmd *m.DeleteAlertEscalationCommand) error {
+				deleted = true
+				return nil
+			})
+
+			steps := []*EscalationStep{
+				{NotifierUid: "slack"},
+				{NotifierUid: "pagerduty", Delay: 10 * time.Minute},
+			}
+
+			evalContext := NewEvalContext(context.Background(), &Rule{Id: 1, OrgId: 1, State: m.AlertStateAlerting})
+			evalContext.PrevAlertState = m.AlertStateOK
+
+			So(StartEscalation(evalContext, 5, steps), ShouldBeNil)
+			So(sent, ShouldResemble, []string{"slack"})
+			So(advanced.NextStepAt, ShouldBeGreaterThan, time.Now().Unix())
+
+			Convey("Next step is notified once", func() {
+				So(advanceEscalation(evalContext, &m.AlertEscalationState{Id: 1, Step: 0}, steps, time.Now()), ShouldBeNil)
+				So(state.Step, ShouldEqual, 1)
+				So(state.NextStepAt, ShouldEqual, 0)
+
+				So(advanceEscalation(evalContext, &m.AlertEscalationState{Id: 1, Step: 0}, steps, time.Now()), ShouldBeNil)
+				So(sent, ShouldResemble, []string{"slack", "pagerduty"})
+
+				Convey("Resolving notifies every reached step", func() {
+					evalContext.Rule.State = m.AlertStateOK
+					So(ResolveEscalation(evalContext, 5, steps), ShouldBeNil)
+					So(sent, ShouldResemble, []string{"slack", "pagerduty", "slack", "pagerduty"})
+					So(deleted, ShouldBeTrue)
+				})
+			})
+
+			Convey("Silenced alerts pause the escalation", func() {
+				endsAt := time.Now().Add(time.Hour)
+				bus.AddHandler("test", func(query *m.GetAlertSilencesQuery) error {
+					query.Result = []*m.AlertSilence{{
+						Id:       1,
+						EndsAt:   endsAt,
+						Matchers: []*m.AlertSilenceMatcher{{Type: m.AlertSilenceMatchRule, Value: "cpu"}},
+					}}
+					return nil
+				})
+
+				evalContext.Rule.Name = "cpu"
+				So(advanceEscalation(evalContext, &m.AlertEscalationState{Id: 1, Step: 0}, steps, time.Now()), ShouldBeNil)
+				So(sent, ShouldResemble, []string{"slack"})
+				So(state.Step, ShouldEqual, 0)
+				So(state.NextStepAt, ShouldEqual, endsAt.Unix())
+			})
+
+			Convey("Escalations of deleted alerts are removed", func() {
+				bus.AddHandlerCtx("test", func(ctx context.Context, query *m.GetAlertNotificationsQuery) error {
+					query.Result = &m.AlertNotification{Id: 5, Type: EscalationNotifierType, Settings: simplejson.NewFromAny(map[string]interface{}{
+						"steps": []interface{}{map[string]interface{}{"notifierUid": "slack"}},
+					})}
+					return nil
+				})
+				bus.AddHandlerCtx("test", func(ctx context.Context, query *m.GetAlertByIdQuery) error {
+					return m.ErrAlertNotFound
+				})
+
+				service := &AlertingService{log: log.New("test")}
+				So(service.processEscalation(&m.AlertEscalationState{Id: 1, OrgId: 1, AlertId: 1, NotifierId: 5}), ShouldBeNil)
+				So(deleted, ShouldBeTrue)
+			})
+
+			Convey("Escalations of received alerts advance through the steps", func() {
+				bus.AddHandlerCtx("test", func(ctx context.Context, query *m.GetAlertNotificationsQuery) error {
+					query.Result = &m.AlertNotification{Id: 5, Type: EscalationNotifierType, Settings: simplejson.NewFromAny(map[string]interface{}{
+						"steps": []interface{}{
+							map[string]interface{}{"notifierUid": "slack"},
+							map[string]interface{}{"notifierUid": "pagerduty", "delay": "10m"},
+							map[string]interface{}{"notifierUid": "phone", "delay": "20m"},
+						},
+					})}
+					return nil
+				})
+				bus.AddHandlerCtx("test", func(ctx context.Context, query *m.GetAlertInstancesQuery) error {
+					if query.AlertId == 0 && query.Id == 7 {
+						query.Result = []*m.AlertInstance{{
+							Id:          7,
+							OrgId:       1,
+							DashboardId: 2,
+							Labels:      map[string]string{"alertname": "cpu"},
+							State:       m.AlertStateAlerting,
+							EvalData:    simplejson.NewFromAny(map[string]interface{}{"title": "High CPU", "panelId": 3}),
+						}}
+					}
+					return nil
+				})
+
+				service := &AlertingService{log: log.New("test")}
+				So(service.processEscalation(&m.AlertEscalationState{Id: 1, OrgId: 1, InstanceId: 7, NotifierId: 5, Step: 0}), ShouldBeNil)
+				So(service.processEscalation(&m.AlertEscalationState{Id: 1, OrgId: 1, InstanceId: 7, NotifierId: 5, Step: 1}), ShouldBeNil)
+
+				So(sent, ShouldResemble, []string{"slack", "pagerduty", "phone"})
+				So(state.Step, ShouldEqual, 2)
+				So(deleted, ShouldBeFalse)
+			})
+
+			Convey("Reminders do not restart the escalation", func() {
+				evalContext.PrevAlertState = m.AlertStateAlerting
+				So(StartEscalation(evalContext, 5, steps), ShouldBeNil)
+				So(sent, ShouldResemble, []string{"slack"})
+			})
+		})
+	})
+}
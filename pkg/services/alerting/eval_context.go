@@ -2,6 +2,7 @@ package alerting
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -23,13 +24,23 @@ type EvalContext struct {
 	Rule           *Rule
 	log            log.Logger
 
-	dashboardRef *m.DashboardRef
+	dashboardRef  *m.DashboardRef
+	dashboardTags []string
 
 	ImagePublicUrl  string
 	ImageOnDiskPath string
 	NoDataFound     bool
 	PrevAlertState  m.AlertStateType
 
+	// Instances holds the per series results of multi-dimensional rules
+	// and Instance is set when the context is scoped to one of them.
+	Instances []*Instance
+	Instance  *Instance
+
+	// EvalTime is the point in time the rule is evaluated at when it is
+	// not now, e.g. when backtesting a rule over a past time range.
+	EvalTime time.Time
+
 	Ctx context.Context
 }
 
@@ -45,6 +56,14 @@ func NewEvalContext(alertCtx context.Context, rule *Rule) *EvalContext {
 	}
 }
 
+// Now returns the time the rule is evaluated at.
+func (c *EvalContext) Now() time.Time {
+	if !c.EvalTime.IsZero() {
+		return c.EvalTime
+	}
+	return time.Now()
+}
+
 type StateDescription struct {
 	Color string
 	Text  string
@@ -87,14 +106,26 @@ func (a *EvalContext) GetDurationMs() float64 {
 }
 
 func (c *EvalContext) GetNotificationTitle() string {
-	return "[" + c.GetStateModel().Text + "] " + c.Rule.Name
+	title := "[" + c.GetStateModel().Text + "] " + c.Rule.Name
+	if c.Instance != nil {
+		title += " " + c.Instance.LabelsString()
+	}
+	return title
 }
 
+// errAlertRuleWithoutDashboard is returned for the dashboard of standalone
+// alert rules.
+var errAlertRuleWithoutDashboard = errors.New("Alert rule is not part of a dashboard")
+
 func (c *EvalContext) GetDashboardUID() (*m.DashboardRef, error) {
 	if c.dashboardRef != nil {
 		return c.dashboardRef, nil
 	}
 
+	if c.Rule.DashboardId == 0 {
+		return nil, errAlertRuleWithoutDashboard
+	}
+
 	uidQuery := &m.GetDashboardRefByIdQuery{Id: c.Rule.DashboardId}
 	if err := bus.Dispatch(uidQuery); err != nil {
 		return nil, err
@@ -111,6 +142,10 @@ func (c *EvalContext) GetRuleUrl() (string, error) {
 		return setting.AppUrl, nil
 	}
 
+	if c.Rule.DashboardId == 0 {
+		return m.GetFullAlertRuleUrl(c.Rule.Id), nil
+	}
+
 	ref, err := c.GetDashboardUID()
 	if err != nil {
 		return "", err
@@ -120,24 +155,28 @@ func (c *EvalContext) GetRuleUrl() (string, error) {
 
 // GetNewState returns the new state from the alert rule evaluation
 func (c *EvalContext) GetNewState() m.AlertStateType {
-	ns := getNewStateInternal(c)
+	return c.getNewState(c.Firing, c.NoDataFound, c.PrevAlertState, c.Rule.LastStateChange)
+}
+
+func (c *EvalContext) getNewState(firing bool, noDataFound bool, prevState m.AlertStateType, lastStateChange time.Time) m.AlertStateType {
+	ns := getNewStateInternal(c, firing, noDataFound, prevState)
 	if ns != m.AlertStateAlerting || c.Rule.For == 0 {
 		return ns
 	}
 
-	since := time.Since(c.Rule.LastStateChange)
-	if c.PrevAlertState == m.AlertStatePending && since > c.Rule.For {
+	since := c.Now().Sub(lastStateChange)
+	if prevState == m.AlertStatePending && since > c.Rule.For {
 		return m.AlertStateAlerting
 	}
 
-	if c.PrevAlertState == m.AlertStateAlerting {
+	if prevState == m.AlertStateAlerting {
 		return m.AlertStateAlerting
 	}
 
 	return m.AlertStatePending
 }
 
-func getNewStateInternal(c *EvalContext) m.AlertStateType {
+func getNewStateInternal(c *EvalContext, firing bool, noDataFound bool, prevState m.AlertStateType) m.AlertStateType {
 	if c.Error != nil {
 		c.log.Error("Alert Rule Result Error",
 			"ruleId", c.Rule.Id,
@@ -146,23 +185,23 @@ func getNewStateInternal(c *EvalContext) m.AlertStateType {
 			"changing state to", c.Rule.ExecutionErrorState.ToAlertState())
 
 		if c.Rule.ExecutionErrorState == m.ExecutionErrorKeepState {
-			return c.PrevAlertState
+			return prevState
 		}
 		return c.Rule.ExecutionErrorState.ToAlertState()
 	}
 
-	if c.Firing {
+	if firing {
 		return m.AlertStateAlerting
 	}
 
-	if c.NoDataFound {
+	if noDataFound {
 		c.log.Info("Alert Rule returned no data",
 			"ruleId", c.Rule.Id,
 			"name", c.Rule.Name,
 			"changing state to", c.Rule.NoDataState.ToAlertState())
 
 		if c.Rule.NoDataState == m.NoDataKeepState {
-			return c.PrevAlertState
+			return prevState
 		}
 		return c.Rule.NoDataState.ToAlertState()
 	}
@@ -25,6 +25,7 @@ func (e *DefaultEvalHandler) Eval(context *EvalContext) {
 	firing := true
 	noDataFound := true
 	conditionEvals := ""
+	results := make([]*ConditionResult, 0, len(context.Rule.Conditions))
 
 	for i := 0; i < len(context.Rule.Conditions); i++ {
 		condition := context.Rule.Conditions[i]
@@ -59,6 +60,11 @@ func (e *DefaultEvalHandler) Eval(context *EvalContext) {
 		}
 
 		context.EvalMatches = append(context.EvalMatches, cr.EvalMatches...)
+		results = append(results, cr)
+	}
+
+	if context.Rule.MultiDimensional && context.Error == nil {
+		context.Instances = evalInstances(results)
 	}
 
 	context.ConditionEvals = conditionEvals + " = " + strconv.FormatBool(firing)
@@ -12,10 +12,11 @@ type conditionStub struct {
 	operator string
 	matches  []*EvalMatch
 	noData   bool
+	series   []*SeriesResult
 }
 
 func (c *conditionStub) Eval(context *EvalContext) (*ConditionResult, error) {
-	return &ConditionResult{Firing: c.firing, EvalMatches: c.matches, Operator: c.operator, NoDataFound: c.noData}, nil
+	return &ConditionResult{Firing: c.firing, EvalMatches: c.matches, Operator: c.operator, NoDataFound: c.noData, SeriesResults: c.series}, nil
 }
 
 func TestAlertingEvaluationHandler(t *testing.T) {
@@ -201,5 +202,53 @@ func TestAlertingEvaluationHandler(t *testing.T) {
 			handler.Eval(context)
 			So(context.NoDataFound, ShouldBeTrue)
 		})
+
+		Convey("Should evaluate an instance per label set for multi-dimensional rules", func() {
+			hostA := map[string]string{"host": "a"}
+			hostB := map[string]string{"host": "b"}
+
+			context := NewEvalContext(context.TODO(), &Rule{
+				MultiDimensional: true,
+				Conditions: []Condition{
+					&conditionStub{operator: "and", firing: true, series: []*SeriesResult{
+						{Labels: hostA, Firing: true, EvalMatch: &EvalMatch{Metric: "a"}},
+						{Labels: hostB, Firing: false},
+					}},
+					&conditionStub{operator: "and", firing: true, series: []*SeriesResult{
+						{Labels: hostA, Firing: true, EvalMatch: &EvalMatch{Metric: "a"}},
+						{Labels: hostB, Firing: true, EvalMatch: &EvalMatch{Metric: "b"}},
+					}},
+				},
+			})
+
+			handler.Eval(context)
+			So(context.Firing, ShouldBeTrue)
+			So(len(context.Instances), ShouldEqual, 2)
+			So(context.Instances[0].Labels, ShouldResemble, hostA)
+			So(context.Instances[0].Firing, ShouldBeTrue)
+			So(len(context.Instances[0].EvalMatches), ShouldEqual, 2)
+			So(context.Instances[1].Labels, ShouldResemble, hostB)
+			So(context.Instances[1].Firing, ShouldBeFalse)
+		})
+
+		Convey("Should treat label sets missing in a condition as no data", func() {
+			context := NewEvalContext(context.TODO(), &Rule{
+				MultiDimensional: true,
+				Conditions: []Condition{
+					&conditionStub{operator: "or", series: []*SeriesResult{
+						{Labels: map[string]string{"host": "a"}, Firing: false},
+					}},
+					&conditionStub{operator: "or", series: []*SeriesResult{
+						{Labels: map[string]string{"host": "b"}, Firing: true},
+					}},
+				},
+			})
+
+			handler.Eval(context)
+			So(len(context.Instances), ShouldEqual, 2)
+			So(context.Instances[0].Firing, ShouldBeFalse)
+			So(context.Instances[0].NoDataFound, ShouldBeTrue)
+			So(context.Instances[1].Firing, ShouldBeTrue)
+		})
 	})
 }
@@ -30,8 +30,14 @@ func NewDashAlertExtractor(dash *m.Dashboard, orgID int64, user *m.SignedInUser)
 }
 
 func (e *DashAlertExtractor) lookupDatasourceID(dsName string) (*m.DataSource, error) {
+	return lookupDatasource(e.OrgID, dsName)
+}
+
+// lookupDatasource returns the datasource with the name, or the default
+// datasource of the org when the name is empty.
+func lookupDatasource(orgID int64, dsName string) (*m.DataSource, error) {
 	if dsName == "" {
-		query := &m.GetDataSourcesQuery{OrgId: e.OrgID}
+		query := &m.GetDataSourcesQuery{OrgId: orgID}
 		if err := bus.Dispatch(query); err != nil {
 			return nil, err
 		}
@@ -42,7 +48,7 @@ func (e *DashAlertExtractor) lookupDatasourceID(dsName string) (*m.DataSource, e
 			}
 		}
 	} else {
-		query := &m.GetDataSourceByNameQuery{Name: dsName, OrgId: e.OrgID}
+		query := &m.GetDataSourceByNameQuery{Name: dsName, OrgId: orgID}
 		if err := bus.Dispatch(query); err != nil {
 			return nil, err
 		}
@@ -73,6 +79,70 @@ func copyJSON(in *simplejson.Json) (*simplejson.Json, error) {
 	return simplejson.NewJson(rawJSON)
 }
 
+// resolveAlertQuery looks up the panel query and datasource referenced by
+// an alert condition query and stores them on the condition.
+func (e *DashAlertExtractor) resolveAlertQuery(panel *simplejson.Json, alert *m.Alert, jsonQuery *simplejson.Json) error {
+	queryRefID, err := jsonQuery.Get("params").GetIndex(0).String()
+	if err != nil {
+		reason := fmt.Sprintf("Alert on PanelId: %v has a query without a query reference", alert.PanelId)
+		return ValidationError{Reason: reason}
+	}
+
+	panelQuery := findPanelQueryByRefID(panel, queryRefID)
+
+	if panelQuery == nil {
+		reason := fmt.Sprintf("Alert on PanelId: %v refers to query(%s) that cannot be found", alert.PanelId, queryRefID)
+		return ValidationError{Reason: reason}
+	}
+
+	dsName := ""
+	if panelQuery.Get("datasource").MustString() != "" {
+		dsName = panelQuery.Get("datasource").MustString()
+	} else if panel.Get("datasource").MustString() != "" {
+		dsName = panel.Get("datasource").MustString()
+	}
+
+	datasource, err := e.lookupDatasourceID(dsName)
+	if err != nil {
+		e.log.Debug("Error looking up datasource", "error", err)
+		return ValidationError{Reason: fmt.Sprintf("Data source used by alert rule not found, alertName=%v, datasource=%s", alert.Name, dsName)}
+	}
+
+	if err := checkDatasourcePermission(e.User, datasource); err != nil {
+		return err
+	}
+
+	jsonQuery.SetPath([]string{"datasourceId"}, datasource.Id)
+
+	if interval, err := panel.Get("interval").String(); err == nil {
+		panelQuery.Set("interval", interval)
+	}
+
+	jsonQuery.Set("model", panelQuery.Interface())
+	return nil
+}
+
+// checkDatasourcePermission returns ErrDataSourceAccessDenied when the user
+// may not query the datasource.
+func checkDatasourcePermission(user *m.SignedInUser, datasource *m.DataSource) error {
+	dsFilterQuery := m.DatasourcesPermissionFilterQuery{
+		User:        user,
+		Datasources: []*m.DataSource{datasource},
+	}
+
+	if err := bus.Dispatch(&dsFilterQuery); err != nil {
+		if err != bus.ErrHandlerNotFound {
+			return err
+		}
+	} else {
+		if len(dsFilterQuery.Result) == 0 {
+			return m.ErrDataSourceAccessDenied
+		}
+	}
+
+	return nil
+}
+
 func (e *DashAlertExtractor) getAlertFromPanels(jsonWithPanels *simplejson.Json, validateAlertFunc func(*m.Alert) bool) ([]*m.Alert, error) {
 	alerts := make([]*m.Alert, 0)
 
@@ -139,50 +209,19 @@ func (e *DashAlertExtractor) getAlertFromPanels(jsonWithPanels *simplejson.Json,
 		for _, condition := range jsonAlert.Get("conditions").MustArray() {
 			jsonCondition := simplejson.NewFromAny(condition)
 
-			jsonQuery := jsonCondition.Get("query")
-			queryRefID := jsonQuery.Get("params").MustArray()[0].(string)
-			panelQuery := findPanelQueryByRefID(panel, queryRefID)
-
-			if panelQuery == nil {
-				reason := fmt.Sprintf("Alert on PanelId: %v refers to query(%s) that cannot be found", alert.PanelId, queryRefID)
-				return nil, ValidationError{Reason: reason}
-			}
-
-			dsName := ""
-			if panelQuery.Get("datasource").MustString() != "" {
-				dsName = panelQuery.Get("datasource").MustString()
-			} else if panel.Get("datasource").MustString() != "" {
-				dsName = panel.Get("datasource").MustString()
-			}
-
-			datasource, err := e.lookupDatasourceID(dsName)
-			if err != nil {
-				e.log.Debug("Error looking up datasource", "error", err)
-				return nil, ValidationError{Reason: fmt.Sprintf("Data source used by alert rule not found, alertName=%v, datasource=%s", alert.Name, dsName)}
-			}
-
-			dsFilterQuery := m.DatasourcesPermissionFilterQuery{
-				User:        e.User,
-				Datasources: []*m.DataSource{datasource},
+			jsonQueries := []*simplejson.Json{jsonCondition.Get("query")}
+			if queries, hasQueries := jsonCondition.CheckGet("queries"); hasQueries {
+				jsonQueries = make([]*simplejson.Json, 0)
+				for _, query := range queries.MustArray() {
+					jsonQueries = append(jsonQueries, simplejson.NewFromAny(query))
+				}
 			}
 
-			if err := bus.Dispatch(&dsFilterQuery); err != nil {
-				if err != bus.ErrHandlerNotFound {
+			for _, jsonQuery := range jsonQueries {
+				if err := e.resolveAlertQuery(panel, alert, jsonQuery); err != nil {
 					return nil, err
 				}
-			} else {
-				if len(dsFilterQuery.Result) == 0 {
-					return nil, m.ErrDataSourceAccessDenied
-				}
 			}
-
-			jsonQuery.SetPath([]string{"datasourceId"}, datasource.Id)
-
-			if interval, err := panel.Get("interval").String(); err == nil {
-				panelQuery.Set("interval", interval)
-			}
-
-			jsonQuery.Set("model", panelQuery.Interface())
 		}
 
 		alert.Settings = jsonAlert
@@ -19,6 +19,10 @@ func TestAlertRuleExtraction(t *testing.T) {
 			return &FakeCondition{}, nil
 		})
 
+		RegisterCondition("math", func(model *simplejson.Json, index int) (Condition, error) {
+			return &FakeCondition{}, nil
+		})
+
 		// mock data
 		defaultDs := &m.DataSource{Id: 12, OrgId: 1, Name: "I am default", IsDefault: true}
 		graphite2Ds := &m.DataSource{Id: 15, OrgId: 1, Name: "graphite2"}
@@ -247,6 +251,45 @@ func TestAlertRuleExtraction(t *testing.T) {
 			})
 		})
 
+		Convey("Parse and validate dashboard containing math alert", func() {
+			json, err := ioutil.ReadFile("./testdata/math-alert.json")
+			So(err, ShouldBeNil)
+
+			dashJSON, err := simplejson.NewJson(json)
+			So(err, ShouldBeNil)
+			dash := m.NewDashboardFromJson(dashJSON)
+			extractor := NewDashAlertExtractor(dash, 1, nil)
+
+			alerts, err := extractor.GetAlerts()
+
+			Convey("Get rules without error", func() {
+				So(err, ShouldBeNil)
+				So(len(alerts), ShouldEqual, 1)
+			})
+
+			Convey("should set datasourceId and model for every query", func() {
+				queries := alerts[0].Settings.Get("conditions").GetIndex(0).Get("queries")
+
+				So(queries.GetIndex(0).Get("datasourceId").MustInt64(), ShouldEqual, 15)
+				So(queries.GetIndex(0).Get("model").Get("target").MustString(), ShouldEqual, "sumSeries(app.*.errors.count)")
+				So(queries.GetIndex(1).Get("datasourceId").MustInt64(), ShouldEqual, 16)
+				So(queries.GetIndex(1).Get("model").Get("target").MustString(), ShouldEqual, "sumSeries(app.*.requests.count)")
+			})
+
+			Convey("should fail on a query without a query reference", func() {
+				for _, params := range []interface{}{[]interface{}{}, []interface{}{1}, nil} {
+					dashJSON, err := simplejson.NewJson(json)
+					So(err, ShouldBeNil)
+
+					panel := dashJSON.Get("panels").GetIndex(0)
+					panel.Get("alert").Get("conditions").GetIndex(0).Get("queries").GetIndex(1).Set("params", params)
+
+					_, err = NewDashAlertExtractor(m.NewDashboardFromJson(dashJSON), 1, nil).GetAlerts()
+					So(err, ShouldHaveSameTypeAs, ValidationError{})
+				}
+			})
+		})
+
 		Convey("Parse and validate dashboard without id and containing an alert", func() {
 			json, err := ioutil.ReadFile("./testdata/dash-without-id.json")
 			So(err, ShouldBeNil)
@@ -0,0 +1,63 @@
+package alerting
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"sort"
+	"strconv"
+)
+
+// hashRingReplicas is the number of points each server gets on the ring,
+// which keeps the share of rules per server close to even.
+const hashRingReplicas = 100
+
+// hashRing assigns rules to servers by consistent hashing so that only the
+// rules of a server that joins or leaves the cluster move to another one.
+type hashRing struct {
+	points []uint32
+	owners map[uint32]string
+}
+
+func newHashRing(servers []string) *hashRing {
+	ring := &hashRing{
+		points: make([]uint32, 0, len(servers)*hashRingReplicas),
+		owners: make(map[uint32]string),
+	}
+
+	for _, server := range servers {
+		for i := 0; i < hashRingReplicas; i++ {
+			point := hashKey(server + "#" + strconv.Itoa(i))
+			if _, exists := ring.owners[point]; exists {
+				continue
+			}
+			ring.owners[point] = server
+			ring.points = append(ring.points, point)
+		}
+	}
+
+	sort.Slice(ring.points, func(i, j int) bool { return ring.points[i] < ring.points[j] })
+	return ring
+}
+
+// owner returns the server responsible for the rule, or an empty string
+// when the ring has no servers.
+func (r *hashRing) owner(ruleId int64) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+
+	hash := hashKey(strconv.FormatInt(ruleId, 10))
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= hash })
+	if i == len(r.points) {
+		i = 0
+	}
+
+	return r.owners[r.points[i]]
+}
+
+// hashKey uses md5 rather than a faster hash since the keys are short and
+// similar, which fnv does not spread evenly enough.
+func hashKey(key string) uint32 {
+	sum := md5.Sum([]byte(key))
+	return binary.BigEndian.Uint32(sum[:4])
+}
@@ -0,0 +1,41 @@
+package alerting
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHashRing(t *testing.T) {
+	Convey("Hash ring", t, func() {
+		Convey("Empty ring has no owner", func() {
+			So(newHashRing(nil).owner(1), ShouldEqual, "")
+		})
+
+		Convey("Should spread rules across servers", func() {
+			ring := newHashRing([]string{"a", "b", "c"})
+			counts := map[string]int{}
+			for id := int64(1); id <= 3000; id++ {
+				counts[ring.owner(id)]++
+			}
+
+			So(len(counts), ShouldEqual, 3)
+			for _, count := range counts {
+				So(count, ShouldBeBetween, 600, 1400)
+			}
+		})
+
+		Convey("Should only move rules of a removed server", func() {
+			before := newHashRing([]string{"a", "b", "c"})
+			after := newHashRing([]string{"a", "c"})
+
+			for id := int64(1); id <= 1000; id++ {
+				if owner := before.owner(id); owner != "b" {
+					So(after.owner(id), ShouldEqual, owner)
+				} else {
+					So(after.owner(id), ShouldBeIn, "a", "c")
+				}
+			}
+		})
+	})
+}
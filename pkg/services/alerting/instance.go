@@ -0,0 +1,195 @@
+package alerting
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// Instance is the evaluation of a single label set of a multi-dimensional
+// rule. Every instance has its own state and notification lifecycle.
+type Instance struct {
+	Id              int64
+	Labels          map[string]string
+	Firing          bool
+	NoDataFound     bool
+	EvalMatches     []*EvalMatch
+	State           m.AlertStateType
+	PrevState       m.AlertStateType
+	LastStateChange time.Time
+	StateChanges    int64
+
+	// Missing is true when the series was not returned by the last
+	// evaluation but a state for it is still persisted.
+	Missing bool
+}
+
+func (i *Instance) ShouldUpdateState() bool {
+	return i.State != i.PrevState
+}
+
+// LabelsString formats the labels of the instance as {key=value, ...}.
+func (i *Instance) LabelsString() string {
+	return formatLabels(i.Labels)
+}
+
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// evalInstances combines the series results of all conditions into one
+// instance per label set, using the same and/or logic as for the rule.
+func evalInstances(results []*ConditionResult) []*Instance {
+	instances := make([]*Instance, 0)
+	keys := make([]string, 0)
+	seen := make(map[string]bool)
+	seriesByCondition := make([]map[string]*SeriesResult, len(results))
+
+	for i, cr := range results {
+		seriesByCondition[i] = make(map[string]*SeriesResult)
+		for _, sr := range cr.SeriesResults {
+			key := m.AlertInstanceLabelsHash(sr.Labels)
+			seriesByCondition[i][key] = sr
+
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+				instances = append(instances, &Instance{Labels: sr.Labels, EvalMatches: make([]*EvalMatch, 0)})
+			}
+		}
+	}
+
+	for index, instance := range instances {
+		firing := true
+		noDataFound := true
+
+		for i, cr := range results {
+			crFiring, crNoData := false, true
+			if sr, exist := seriesByCondition[i][keys[index]]; exist {
+				crFiring, crNoData = sr.Firing, sr.NoDataFound
+				if sr.Firing && sr.EvalMatch != nil {
+					instance.EvalMatches = append(instance.EvalMatches, sr.EvalMatch)
+				}
+			}
+
+			if i == 0 {
+				firing = crFiring
+				noDataFound = crNoData
+			}
+
+			if cr.Operator == "or" {
+				firing = firing || crFiring
+				noDataFound = noDataFound || crNoData
+			} else {
+				firing = firing && crFiring
+				noDataFound = noDataFound && crNoData
+			}
+		}
+
+		instance.Firing = firing
+		instance.NoDataFound = noDataFound
+	}
+
+	return instances
+}
+
+// loadInstanceStates reads the persisted states of the rule instances.
+// Persisted instances that are no longer returned by the query are added
+// as missing so that they get resolved.
+func (c *EvalContext) loadInstanceStates() error {
+	query := &m.GetAlertInstancesQuery{OrgId: c.Rule.OrgId, AlertId: c.Rule.Id}
+	if err := bus.Dispatch(query); err != nil {
+		return err
+	}
+
+	byKey := make(map[string]*Instance)
+	for _, instance := range c.Instances {
+		byKey[m.AlertInstanceLabelsHash(instance.Labels)] = instance
+	}
+
+	for _, stored := range query.Result {
+		instance, exist := byKey[stored.LabelsHash]
+		if !exist {
+			instance = &Instance{
+				Labels:      stored.Labels,
+				EvalMatches: make([]*EvalMatch, 0),
+				Missing:     true,
+			}
+			c.Instances = append(c.Instances, instance)
+		}
+
+		instance.Id = stored.Id
+		instance.PrevState = stored.State
+		instance.LastStateChange = stored.NewStateDate
+		instance.StateChanges = stored.StateChanges
+	}
+
+	return nil
+}
+
+// UpdateInstanceStates computes the new state of every instance of a
+// multi-dimensional rule.
+func (c *EvalContext) UpdateInstanceStates() {
+	if !c.Rule.MultiDimensional {
+		return
+	}
+
+	if !c.IsTestRun {
+		if err := c.loadInstanceStates(); err != nil {
+			c.log.Error("Failed to load alert instance states", "ruleId", c.Rule.Id, "error", err)
+		}
+	}
+
+	for _, instance := range c.Instances {
+		if instance.PrevState == "" {
+			instance.PrevState = m.AlertStateUnknown
+		}
+
+		instance.State = c.getNewState(instance.Firing, instance.NoDataFound, instance.PrevState, instance.LastStateChange)
+	}
+}
+
+// ForInstance returns a copy of the eval context scoped to a single
+// instance, used to drive notifications per label set.
+func (c *EvalContext) ForInstance(instance *Instance) *EvalContext {
+	rule := *c.Rule
+	rule.State = instance.State
+	rule.StateChanges = instance.StateChanges
+	rule.LastStateChange = instance.LastStateChange
+
+	return &EvalContext{
+		Firing:          instance.Firing,
+		IsTestRun:       c.IsTestRun,
+		EvalMatches:     instance.EvalMatches,
+		Logs:            c.Logs,
+		Error:           c.Error,
+		ConditionEvals:  c.ConditionEvals,
+		StartTime:       c.StartTime,
+		EndTime:         c.EndTime,
+		Rule:            &rule,
+		Instance:        instance,
+		log:             c.log,
+		dashboardRef:    c.dashboardRef,
+		dashboardTags:   c.dashboardTags,
+		ImagePublicUrl:  c.ImagePublicUrl,
+		ImageOnDiskPath: c.ImageOnDiskPath,
+		NoDataFound:     instance.NoDataFound,
+		PrevAlertState:  instance.PrevState,
+		EvalTime:        c.EvalTime,
+		Ctx:             c.Ctx,
+	}
+}
@@ -0,0 +1,71 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertInstances(t *testing.T) {
+	Convey("Test multi-dimensional alert instances", t, func() {
+		hostA := map[string]string{"host": "a"}
+		hostB := map[string]string{"host": "b"}
+		hostC := map[string]string{"host": "c"}
+
+		bus.AddHandler("test", func(query *m.GetAlertInstancesQuery) error {
+			query.Result = []*m.AlertInstance{
+				{Id: 1, Labels: hostA, LabelsHash: m.AlertInstanceLabelsHash(hostA), State: m.AlertStateOK, StateChanges: 2},
+				{Id: 2, Labels: hostC, LabelsHash: m.AlertInstanceLabelsHash(hostC), State: m.AlertStateAlerting, StateChanges: 5},
+			}
+			return nil
+		})
+
+		evalContext := NewEvalContext(context.TODO(), &Rule{Id: 1, Name: "cpu", MultiDimensional: true})
+		evalContext.Instances = []*Instance{
+			{Labels: hostA, Firing: true},
+			{Labels: hostB, Firing: false},
+		}
+
+		evalContext.UpdateInstanceStates()
+
+		Convey("Should compute state per instance", func() {
+			So(len(evalContext.Instances), ShouldEqual, 3)
+
+			So(evalContext.Instances[0].Id, ShouldEqual, 1)
+			So(evalContext.Instances[0].PrevState, ShouldEqual, m.AlertStateOK)
+			So(evalContext.Instances[0].State, ShouldEqual, m.AlertStateAlerting)
+
+			So(evalContext.Instances[1].PrevState, ShouldEqual, m.AlertStateUnknown)
+			So(evalContext.Instances[1].State, ShouldEqual, m.AlertStateOK)
+		})
+
+		Convey("Should resolve instances no longer returned by the query", func() {
+			missing := evalContext.Instances[2]
+			So(missing.Missing, ShouldBeTrue)
+			So(missing.Labels, ShouldResemble, hostC)
+			So(missing.PrevState, ShouldEqual, m.AlertStateAlerting)
+			So(missing.State, ShouldEqual, m.AlertStateOK)
+		})
+
+		Convey("Should honor for per instance", func() {
+			evalContext.Rule.For = time.Minute
+			evalContext.UpdateInstanceStates()
+
+			So(evalContext.Instances[0].State, ShouldEqual, m.AlertStatePending)
+		})
+
+		Convey("Should scope eval context to instance", func() {
+			instanceContext := evalContext.ForInstance(evalContext.Instances[0])
+
+			So(instanceContext.Rule.State, ShouldEqual, m.AlertStateAlerting)
+			So(instanceContext.PrevAlertState, ShouldEqual, m.AlertStateOK)
+			So(instanceContext.Rule.StateChanges, ShouldEqual, 2)
+			So(instanceContext.GetNotificationTitle(), ShouldEqual, "[Alerting] cpu {host=a}")
+			So(evalContext.Rule.State, ShouldEqual, "")
+		})
+	})
+}
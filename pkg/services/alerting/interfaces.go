@@ -29,6 +29,18 @@ type Notifier interface {
 	GetSendReminder() bool
 	GetDisableResolveMessage() bool
 	GetFrequency() time.Duration
+
+	// GetGroupBy returns how notifications are grouped into digests
+	// (dashboard, folder or tag), or an empty string to send them one by one.
+	GetGroupBy() string
+	GetGroupWait() time.Duration
+	GetGroupInterval() time.Duration
+}
+
+// DigestNotifier is implemented by notifiers that render a group of alerts
+// themselves. Other notifiers receive the combined context of the digest.
+type DigestNotifier interface {
+	NotifyDigest(digest *NotificationDigest) error
 }
 
 type notifierState struct {
@@ -49,10 +61,20 @@ func (notifiers notifierStateSlice) ShouldUploadImage() bool {
 }
 
 type ConditionResult struct {
+	Firing        bool
+	NoDataFound   bool
+	Operator      string
+	EvalMatches   []*EvalMatch
+	SeriesResults []*SeriesResult
+}
+
+// SeriesResult is the outcome of a condition for a single series. It is
+// used to evaluate multi-dimensional rules per label set.
+type SeriesResult struct {
+	Labels      map[string]string
 	Firing      bool
 	NoDataFound bool
-	Operator    string
-	EvalMatches []*EvalMatch
+	EvalMatch   *EvalMatch
 }
 
 type Condition interface {
@@ -0,0 +1,249 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+const (
+	GroupByDashboard = "dashboard"
+	GroupByFolder    = "folder"
+	GroupByTag       = "tag"
+)
+
+// NotificationDigest is a group of notifications for the same channel that
+// are sent as a single message.
+type NotificationDigest struct {
+	GroupBy string
+	Title   string
+	Alerts  []*EvalContext
+	Ctx     context.Context
+}
+
+// EvalContext combines the alerts of the digest into a single context so
+// that notifiers without digest support render one message for the group.
+func (d *NotificationDigest) EvalContext() *EvalContext {
+	first := d.Alerts[0]
+	state := m.AlertStateOK
+	lines := make([]string, 0, len(d.Alerts))
+	matches := make([]*EvalMatch, 0)
+
+	for _, alert := range d.Alerts {
+		state = worstDigestState(state, alert.Rule.State)
+
+		line := alert.GetNotificationTitle()
+		if alert.Rule.Message != "" && alert.Rule.State != m.AlertStateOK {
+			line += ": " + alert.Rule.Message
+		}
+		lines = append(lines, line)
+		matches = append(matches, alert.EvalMatches...)
+	}
+
+	rule := &Rule{
+		OrgId:       first.Rule.OrgId,
+		DashboardId: first.Rule.DashboardId,
+		PanelId:     first.Rule.PanelId,
+		FolderId:    first.Rule.FolderId,
+		Name:        fmt.Sprintf("%d alerts in %s", len(d.Alerts), d.Title),
+		Message:     strings.Join(lines, "\n"),
+		State:       state,
+	}
+
+	return &EvalContext{
+		Firing:         state == m.AlertStateAlerting,
+		EvalMatches:    matches,
+		Logs:           make([]*ResultLogEntry, 0),
+		StartTime:      first.StartTime,
+		EndTime:        first.EndTime,
+		Rule:           rule,
+		log:            first.log,
+		dashboardRef:   first.dashboardRef,
+		PrevAlertState: first.PrevAlertState,
+		Ctx:            d.Ctx,
+	}
+}
+
+// worstDigestState orders the states that can be notified on, alerting
+// wins over no data which wins over ok.
+func worstDigestState(a, b m.AlertStateType) m.AlertStateType {
+	rank := func(state m.AlertStateType) int {
+		switch state {
+		case m.AlertStateAlerting:
+			return 2
+		case m.AlertStateNoData:
+			return 1
+		}
+		return 0
+	}
+
+	if rank(b) > rank(a) {
+		return b
+	}
+	return a
+}
+
+// notificationGroupKey returns the key and title of the group an alert
+// belongs to. Tag groups contain the dashboards with the same set of tags.
+func notificationGroupKey(evalContext *EvalContext, groupBy string) (string, string, error) {
+	switch groupBy {
+	case GroupByDashboard:
+		if evalContext.Rule.DashboardId == 0 {
+			return "dashboard/0", "alert rules", nil
+		}
+
+		query := &m.GetDashboardQuery{Id: evalContext.Rule.DashboardId, OrgId: evalContext.Rule.OrgId}
+		if err := bus.Dispatch(query); err != nil {
+			return "", "", err
+		}
+		return fmt.Sprintf("dashboard/%d", query.Result.Id), query.Result.Title, nil
+
+	case GroupByFolder:
+		// standalone alert rules are placed in a folder themselves
+		folderId := evalContext.Rule.FolderId
+		if evalContext.Rule.DashboardId != 0 {
+			query := &m.GetDashboardQuery{Id: evalContext.Rule.DashboardId, OrgId: evalContext.Rule.OrgId}
+			if err := bus.Dispatch(query); err != nil {
+				return "", "", err
+			}
+			folderId = query.Result.FolderId
+		}
+
+		if folderId == 0 {
+			return "folder/0", "General", nil
+		}
+
+		folderQuery := &m.GetDashboardQuery{Id: folderId, OrgId: evalContext.Rule.OrgId}
+		if err := bus.Dispatch(folderQuery); err != nil {
+			return "", "", err
+		}
+		return fmt.Sprintf("folder/%d", folderQuery.Result.Id), folderQuery.Result.Title, nil
+
+	case GroupByTag:
+		tags, err := evalContext.getDashboardTags()
+		if err != nil {
+			return "", "", err
+		}
+
+		sorted := append([]string{}, tags...)
+		sort.Strings(sorted)
+		if len(sorted) == 0 {
+			return "tag/", "untagged dashboards", nil
+		}
+
+		title := strings.Join(sorted, ", ")
+		return "tag/" + title, title, nil
+	}
+
+	return "", "", fmt.Errorf("Unsupported notification group by %q", groupBy)
+}
+
+type groupedNotification struct {
+	evalContext *EvalContext
+	state       *m.AlertNotificationState
+}
+
+type notificationGroup struct {
+	title     string
+	notifier  Notifier
+	items     []*groupedNotification
+	lastFlush time.Time
+	timer     *time.Timer
+}
+
+// notificationGroups collects the notifications of grouped channels. The
+// first notification of a group is delayed by the group wait, following
+// ones are sent at most once per group interval.
+type notificationGroups struct {
+	mutex  sync.Mutex
+	groups map[string]*notificationGroup
+	send   func(notifier Notifier, title string, items []*groupedNotification)
+	now    func() time.Time
+}
+
+func newNotificationGroups(send func(notifier Notifier, title string, items []*groupedNotification)) *notificationGroups {
+	return &notificationGroups{
+		groups: make(map[string]*notificationGroup),
+		send:   send,
+		now:    time.Now,
+	}
+}
+
+func (g *notificationGroups) add(key string, title string, notifier Notifier, item *groupedNotification) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	now := g.now()
+	g.prune(now)
+
+	group, exist := g.groups[key]
+	if !exist {
+		group = &notificationGroup{}
+		g.groups[key] = group
+	}
+
+	group.title = title
+	group.notifier = notifier
+
+	// a newer notification for the same alert replaces the queued one
+	replaced := false
+	for i, queued := range group.items {
+		if queued.state.Id == item.state.Id {
+			group.items[i] = item
+			replaced = true
+		}
+	}
+	if !replaced {
+		group.items = append(group.items, item)
+	}
+
+	if group.timer == nil {
+		group.timer = time.AfterFunc(group.delay(now), func() { g.flush(key) })
+	}
+}
+
+func (group *notificationGroup) delay(now time.Time) time.Duration {
+	interval := group.notifier.GetGroupInterval()
+	if group.lastFlush.IsZero() || now.Sub(group.lastFlush) >= interval {
+		return group.notifier.GetGroupWait()
+	}
+
+	return group.lastFlush.Add(interval).Sub(now)
+}
+
+// prune removes idle groups whose interval has passed.
+func (g *notificationGroups) prune(now time.Time) {
+	for key, group := range g.groups {
+		if group.timer == nil && len(group.items) == 0 && now.Sub(group.lastFlush) >= group.notifier.GetGroupInterval() {
+			delete(g.groups, key)
+		}
+	}
+}
+
+func (g *notificationGroups) flush(key string) {
+	g.mutex.Lock()
+	group, exist := g.groups[key]
+	if !exist {
+		g.mutex.Unlock()
+		return
+	}
+
+	items := group.items
+	notifier := group.notifier
+	title := group.title
+
+	group.items = nil
+	group.timer = nil
+	group.lastFlush = g.now()
+	g.mutex.Unlock()
+
+	if len(items) > 0 {
+		g.send(notifier, title, items)
+	}
+}
@@ -0,0 +1,161 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeGroupedNotifier struct {
+	groupBy  string
+	wait     time.Duration
+	interval time.Duration
+}
+
+func (n *fakeGroupedNotifier) Notify(evalContext *EvalContext) error { return nil }
+func (n *fakeGroupedNotifier) GetType() string                       { return "fake" }
+func (n *fakeGroupedNotifier) NeedsImage() bool                      { return false }
+func (n *fakeGroupedNotifier) ShouldNotify(ctx context.Context, evalContext *EvalContext, state *m.AlertNotificationState) bool {
+	return true
+}
+func (n *fakeGroupedNotifier) GetNotifierId() int64            { return 1 }
+func (n *fakeGroupedNotifier) GetIsDefault() bool              { return false }
+func (n *fakeGroupedNotifier) GetSendReminder() bool           { return false }
+func (n *fakeGroupedNotifier) GetDisableResolveMessage() bool  { return false }
+func (n *fakeGroupedNotifier) GetFrequency() time.Duration     { return 0 }
+func (n *fakeGroupedNotifier) GetGroupBy() string              { return n.groupBy }
+func (n *fakeGroupedNotifier) GetGroupWait() time.Duration     { return n.wait }
+func (n *fakeGroupedNotifier) GetGroupInterval() time.Duration { return n.interval }
+
+func TestNotificationGroups(t *testing.T) {
+	Convey("Notification groups", t, func() {
+		notifier := &fakeGroupedNotifier{groupBy: GroupByDashboard, wait: time.Hour, interval: 2 * time.Hour}
+		now := time.Now()
+
+		var sent [][]*groupedNotification
+		groups := newNotificationGroups(func(notifier Notifier, title string, items []*groupedNotification) {
+			sent = append(sent, items)
+		})
+		groups.now = func() time.Time { return now }
+
+		newItem := func(stateId int64, ruleName string) *groupedNotification {
+			return &groupedNotification{
+				evalContext: NewEvalContext(context.Background(), &Rule{Name: ruleName, State: m.AlertStateAlerting}),
+				state:       &m.AlertNotificationState{Id: stateId},
+			}
+		}
+
+		Convey("Should collect notifications until the group is flushed", func() {
+			groups.add("1/dashboard/1", "Servers", notifier, newItem(1, "cpu"))
+			groups.add("1/dashboard/1", "Servers", notifier, newItem(2, "memory"))
+			groups.add("1/dashboard/2", "Databases", notifier, newItem(3, "disk"))
+			So(sent, ShouldBeEmpty)
+
+			groups.flush("1/dashboard/1")
+			So(len(sent), ShouldEqual, 1)
+			So(len(sent[0]), ShouldEqual, 2)
+			So(len(groups.groups["1/dashboard/2"].items), ShouldEqual, 1)
+		})
+
+		Convey("Should replace queued notification of the same alert", func() {
+			groups.add("1/dashboard/1", "Servers", notifier, newItem(1, "cpu"))
+			groups.add("1/dashboard/1", "Servers", notifier, newItem(1, "cpu again"))
+
+			groups.flush("1/dashboard/1")
+			So(len(sent[0]), ShouldEqual, 1)
+			So(sent[0][0].evalContext.Rule.Name, ShouldEqual, "cpu again")
+		})
+
+		Convey("Should wait for group wait and then group interval", func() {
+			group := &notificationGroup{notifier: notifier}
+			So(group.delay(now), ShouldEqual, time.Hour)
+
+			group.lastFlush = now.Add(-30 * time.Minute)
+			So(group.delay(now), ShouldEqual, 90*time.Minute)
+
+			group.lastFlush = now.Add(-3 * time.Hour)
+			So(group.delay(now), ShouldEqual, time.Hour)
+		})
+
+		Convey("Should prune idle groups", func() {
+			groups.add("1/dashboard/1", "Servers", notifier, newItem(1, "cpu"))
+			groups.flush("1/dashboard/1")
+
+			now = now.Add(3 * time.Hour)
+			groups.add("1/dashboard/2", "Databases", notifier, newItem(2, "disk"))
+			_, exist := groups.groups["1/dashboard/1"]
+			So(exist, ShouldBeFalse)
+		})
+	})
+
+	Convey("Notification digest", t, func() {
+		newContext := func(name string, state m.AlertStateType, message string) *EvalContext {
+			evalContext := NewEvalContext(context.Background(), &Rule{Name: name, State: state, Message: message})
+			evalContext.EvalMatches = append(evalContext.EvalMatches, &EvalMatch{Metric: name})
+			return evalContext
+		}
+
+		digest := &NotificationDigest{
+			GroupBy: GroupByDashboard,
+			Title:   "Servers",
+			Alerts: []*EvalContext{
+				newContext("cpu", m.AlertStateOK, "cpu is high"),
+				newContext("memory", m.AlertStateAlerting, "memory is low"),
+				newContext("disk", m.AlertStateNoData, ""),
+			},
+		}
+
+		Convey("Should combine the alerts into one context", func() {
+			evalContext := digest.EvalContext()
+			So(evalContext.Rule.State, ShouldEqual, m.AlertStateAlerting)
+			So(evalContext.GetNotificationTitle(), ShouldEqual, "[Alerting] 3 alerts in Servers")
+			So(evalContext.Rule.Message, ShouldEqual, "[OK] cpu\n[Alerting] memory: memory is low\n[No Data] disk")
+			So(len(evalContext.EvalMatches), ShouldEqual, 3)
+		})
+	})
+
+	Convey("Notification group key", t, func() {
+		dashboards := map[int64]*m.Dashboard{
+			1:  {Id: 1, Title: "Servers", FolderId: 10, Data: simplejson.NewFromAny(map[string]interface{}{"tags": []interface{}{"prod", "linux"}})},
+			10: {Id: 10, Title: "Infrastructure", IsFolder: true, Data: simplejson.New()},
+		}
+
+		bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+			query.Result = dashboards[query.Id]
+			return nil
+		})
+
+		evalContext := NewEvalContext(context.Background(), &Rule{OrgId: 1, DashboardId: 1})
+
+		Convey("Should group by dashboard", func() {
+			key, title, err := notificationGroupKey(evalContext, GroupByDashboard)
+			So(err, ShouldBeNil)
+			So(key, ShouldEqual, "dashboard/1")
+			So(title, ShouldEqual, "Servers")
+		})
+
+		Convey("Should group by folder", func() {
+			key, title, err := notificationGroupKey(evalContext, GroupByFolder)
+			So(err, ShouldBeNil)
+			So(key, ShouldEqual, "folder/10")
+			So(title, ShouldEqual, "Infrastructure")
+		})
+
+		Convey("Should group by sorted tags", func() {
+			key, title, err := notificationGroupKey(evalContext, GroupByTag)
+			So(err, ShouldBeNil)
+			So(key, ShouldEqual, "tag/linux, prod")
+			So(title, ShouldEqual, "linux, prod")
+		})
+
+		Convey("Should fail on unknown grouping", func() {
+			_, _, err := notificationGroupKey(evalContext, "panel")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
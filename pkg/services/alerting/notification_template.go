@@ -0,0 +1,128 @@
+package alerting
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// NotificationTemplateData is the data available in the title and body
+// templates of a notification channel, e.g. `{{.RuleName}} is {{.State}}`.
+type NotificationTemplateData struct {
+	RuleId       int64
+	RuleName     string
+	RuleUrl      string
+	State        m.AlertStateType
+	PrevState    m.AlertStateType
+	Title        string
+	Message      string
+	Error        string
+	EvalMatches  []*EvalMatch
+	Tags         []string
+	Labels       map[string]string
+	DashboardUrl string
+	ImageUrl     string
+}
+
+var notificationTemplateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// NewNotificationTemplateData collects the template data of an evaluation.
+// Urls and tags that cannot be resolved are left empty.
+func NewNotificationTemplateData(evalContext *EvalContext) *NotificationTemplateData {
+	data := &NotificationTemplateData{
+		RuleId:      evalContext.Rule.Id,
+		RuleName:    evalContext.Rule.Name,
+		State:       evalContext.Rule.State,
+		PrevState:   evalContext.PrevAlertState,
+		Title:       evalContext.GetNotificationTitle(),
+		Message:     evalContext.Rule.Message,
+		EvalMatches: evalContext.EvalMatches,
+		Tags:        make([]string, 0),
+		ImageUrl:    evalContext.ImagePublicUrl,
+	}
+
+	if evalContext.Error != nil {
+		data.Error = evalContext.Error.Error()
+	}
+
+	if evalContext.Instance != nil {
+		data.Labels = evalContext.Instance.Labels
+	}
+
+	if evalContext.IsTestRun {
+		data.RuleUrl = setting.AppUrl
+		data.DashboardUrl = setting.AppUrl
+		return data
+	}
+
+	if ruleUrl, err := evalContext.GetRuleUrl(); err == nil {
+		data.RuleUrl = ruleUrl
+	}
+
+	if ref, err := evalContext.GetDashboardUID(); err == nil {
+		data.DashboardUrl = m.GetFullDashboardUrl(ref.Uid, ref.Slug)
+	}
+
+	if tags, err := evalContext.getDashboardTags(); err == nil {
+		data.Tags = tags
+	}
+
+	return data
+}
+
+// ParseNotificationTemplate parses a title or body template. An empty text
+// returns a nil template which means the default text is used.
+func ParseNotificationTemplate(name string, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	return template.New(name).Funcs(notificationTemplateFuncs).Parse(text)
+}
+
+// RenderNotificationTemplate executes tmpl for the evaluation and returns
+// defaultText when no template is configured.
+func RenderNotificationTemplate(tmpl *template.Template, evalContext *EvalContext, defaultText string) (string, error) {
+	if tmpl == nil {
+		return defaultText, nil
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, NewNotificationTemplateData(evalContext)); err != nil {
+		return defaultText, err
+	}
+
+	return buf.String(), nil
+}
+
+// ValidateNotificationTemplates checks the titleTemplate and bodyTemplate
+// settings of a notification channel by rendering them for a test alert.
+func ValidateNotificationTemplates(settings *simplejson.Json) error {
+	if settings == nil {
+		return nil
+	}
+
+	evalContext := createTestEvalContext(&NotificationTestCommand{Settings: settings})
+
+	for _, key := range []string{"titleTemplate", "bodyTemplate"} {
+		tmpl, err := ParseNotificationTemplate(key, settings.Get(key).MustString())
+		if err != nil {
+			return ValidationError{Reason: fmt.Sprintf("Invalid %s: %v", key, err)}
+		}
+
+		if _, err := RenderNotificationTemplate(tmpl, evalContext, ""); err != nil {
+			return ValidationError{Reason: fmt.Sprintf("Invalid %s: %v", key, err)}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNotificationTemplates(t *testing.T) {
+	Convey("Notification templates", t, func() {
+		evalContext := NewEvalContext(context.Background(), &Rule{Id: 3, Name: "cpu", Message: "cpu is high", State: m.AlertStateAlerting})
+		evalContext.IsTestRun = true
+		evalContext.EvalMatches = []*EvalMatch{{Metric: "server-1", Value: null.FloatFrom(95)}}
+		evalContext.Instance = &Instance{Labels: map[string]string{"host": "server-1"}}
+
+		render := func(text string) (string, error) {
+			tmpl, err := ParseNotificationTemplate("test", text)
+			So(err, ShouldBeNil)
+			return RenderNotificationTemplate(tmpl, evalContext, "default")
+		}
+
+		Convey("Should use default text without template", func() {
+			result, err := render("")
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, "default")
+		})
+
+		Convey("Should render rule fields, labels and eval matches", func() {
+			result, err := render(`{{upper .RuleName}} {{.State}} on {{.Labels.host}}:{{range .EvalMatches}} {{.Metric}}={{.Value}}{{end}}`)
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, "CPU alerting on server-1: server-1=95.000")
+		})
+
+		Convey("Should fall back to default text on execution errors", func() {
+			result, err := render(`{{.Unknown}}`)
+			So(err, ShouldNotBeNil)
+			So(result, ShouldEqual, "default")
+		})
+
+		Convey("Should validate templates in settings", func() {
+			settings := simplejson.NewFromAny(map[string]interface{}{
+				"titleTemplate": "{{.RuleName}} is {{.State}}",
+				"bodyTemplate":  "{{.Message}} {{join .Tags \", \"}}",
+			})
+			So(ValidateNotificationTemplates(settings), ShouldBeNil)
+
+			settings.Set("bodyTemplate", "{{.Message")
+			So(ValidateNotificationTemplates(settings), ShouldHaveSameTypeAs, ValidationError{})
+
+			settings.Set("bodyTemplate", "")
+			settings.Set("titleTemplate", "{{.RuleTitle}}")
+			So(ValidateNotificationTemplates(settings), ShouldHaveSameTypeAs, ValidationError{})
+		})
+
+		Convey("Should render preview of test notification", func() {
+			cmd := &NotificationTestCommand{
+				Settings: simplejson.NewFromAny(map[string]interface{}{"titleTemplate": "Test: {{.RuleName}}"}),
+			}
+
+			So(renderTestNotification(cmd, createTestEvalContext(cmd)), ShouldBeNil)
+			So(cmd.Title, ShouldEqual, "Test: Test notification")
+			So(cmd.Body, ShouldEqual, "Someone is testing the alert notification within grafana.")
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package alerting
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -27,15 +28,18 @@ type NotificationService interface {
 }
 
 func NewNotificationService(renderService rendering.Service) NotificationService {
-	return &notificationService{
+	service := &notificationService{
 		log:           log.New("alerting.notifier"),
 		renderService: renderService,
 	}
+	service.groups = newNotificationGroups(service.sendDigest)
+	return service
 }
 
 type notificationService struct {
 	log           log.Logger
 	renderService rendering.Service
+	groups        *notificationGroups
 }
 
 func (n *notificationService) SendIfNeeded(context *EvalContext) error {
@@ -48,7 +52,19 @@ func (n *notificationService) SendIfNeeded(context *EvalContext) error {
 		return nil
 	}
 
-	if notifierStates.ShouldUploadImage() {
+	if !context.IsTestRun {
+		silence, err := context.getActiveSilence()
+		if err != nil {
+			n.log.Error("Failed to check alert silences", "error", err)
+		} else if silence != nil {
+			n.log.Info("Alert is silenced, holding back notifications", "alertId", context.Rule.Id, "silenceId", silence.Id)
+			n.holdNotifications(context, notifierStates)
+			return nil
+		}
+	}
+
+	// standalone alert rules have no panel to render
+	if notifierStates.ShouldUploadImage() && context.ImageOnDiskPath == "" && context.Rule.DashboardId != 0 {
 		if err = n.uploadImage(context); err != nil {
 			n.log.Error("Failed to upload alert panel image.", "error", err)
 		}
@@ -63,7 +79,7 @@ func (n *notificationService) sendAndMarkAsComplete(evalContext *EvalContext, no
 	n.log.Debug("Sending notification", "type", notifier.GetType(), "id", notifier.GetNotifierId(), "isDefault", notifier.GetIsDefault())
 	metrics.M_Alerting_Notification_Sent.WithLabelValues(notifier.GetType()).Inc()
 
-	err := notifier.Notify(evalContext)
+	err := notifyWithDelivery(evalContext, notifier)
 
 	if err != nil {
 		n.log.Error("failed to send notification", "id", notifier.GetNotifierId(), "error", err)
@@ -81,34 +97,95 @@ func (n *notificationService) sendAndMarkAsComplete(evalContext *EvalContext, no
 	return bus.DispatchCtx(evalContext.Ctx, cmd)
 }
 
-func (n *notificationService) sendNotification(evalContext *EvalContext, notifierState *notifierState) error {
-	if !evalContext.IsTestRun {
-		setPendingCmd := &m.SetAlertNotificationStateToPendingCommand{
-			Id:                           notifierState.state.Id,
-			Version:                      notifierState.state.Version,
-			AlertRuleStateUpdatedVersion: evalContext.Rule.StateChanges,
+// setPending marks the notification state as pending, or as silenced when
+// it is held back, and returns false when another server already claimed
+// the notification.
+func (n *notificationService) setPending(evalContext *EvalContext, notifierState *notifierState, silenced bool) (bool, error) {
+	setPendingCmd := &m.SetAlertNotificationStateToPendingCommand{
+		Id:                           notifierState.state.Id,
+		Version:                      notifierState.state.Version,
+		AlertRuleStateUpdatedVersion: evalContext.Rule.StateChanges,
+		Silenced:                     silenced,
+	}
+
+	err := bus.DispatchCtx(evalContext.Ctx, setPendingCmd)
+	if err == m.ErrAlertNotificationStateVersionConflict {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	// We need to update state version to be able to log
+	// unexpected version conflicts when marking notifications as ok
+	notifierState.state.Version = setPendingCmd.ResultVersion
+	return true, nil
+}
+
+// holdNotifications marks the notifications of a silenced alert as
+// silenced without sending them, so they are sent once the silence has
+// ended.
+func (n *notificationService) holdNotifications(evalContext *EvalContext, notifierStates notifierStateSlice) {
+	for _, notifierState := range notifierStates {
+		if notifierState.state.State == m.AlertNotificationStatePending {
+			continue
 		}
 
-		err := bus.DispatchCtx(evalContext.Ctx, setPendingCmd)
-		if err == m.ErrAlertNotificationStateVersionConflict {
-			return nil
+		if notifierState.state.State == m.AlertNotificationStateSilenced &&
+			notifierState.state.AlertRuleStateUpdatedVersion == evalContext.Rule.StateChanges {
+			continue
 		}
 
-		if err != nil {
-			return err
+		if _, err := n.setPending(evalContext, notifierState, true); err != nil {
+			n.log.Error("failed to hold back notification", "id", notifierState.notifier.GetNotifierId(), "error", err)
 		}
+	}
+}
 
-		// We need to update state version to be able to log
-		// unexpected version conflicts when marking notifications as ok
-		notifierState.state.Version = setPendingCmd.ResultVersion
+func (n *notificationService) sendNotification(evalContext *EvalContext, notifierState *notifierState) error {
+	if !evalContext.IsTestRun {
+		if ok, err := n.setPending(evalContext, notifierState, false); !ok {
+			return err
+		}
 	}
 
 	return n.sendAndMarkAsComplete(evalContext, notifierState)
 }
 
+// queueNotification claims the notification and adds it to the group of
+// its channel, the group is sent as a digest once its wait time is over.
+func (n *notificationService) queueNotification(evalContext *EvalContext, notifierState *notifierState) error {
+	notifier := notifierState.notifier
+
+	key, title, err := notificationGroupKey(evalContext, notifier.GetGroupBy())
+	if err != nil {
+		n.log.Warn("Could not group notification, sending it directly", "id", notifier.GetNotifierId(), "error", err)
+		return n.sendNotification(evalContext, notifierState)
+	}
+
+	if ok, err := n.setPending(evalContext, notifierState, false); !ok {
+		return err
+	}
+
+	n.log.Debug("Queueing notification", "id", notifier.GetNotifierId(), "group", key)
+	n.groups.add(fmt.Sprintf("%d/%s", notifier.GetNotifierId(), key), title, notifier, &groupedNotification{
+		evalContext: evalContext,
+		state:       notifierState.state,
+	})
+
+	return nil
+}
+
 func (n *notificationService) sendNotifications(evalContext *EvalContext, notifierStates notifierStateSlice) error {
 	for _, notifierState := range notifierStates {
-		err := n.sendNotification(evalContext, notifierState)
+		var err error
+		if !evalContext.IsTestRun && notifierState.notifier.GetGroupBy() != "" {
+			err = n.queueNotification(evalContext, notifierState)
+		} else {
+			err = n.sendNotification(evalContext, notifierState)
+		}
+
 		if err != nil {
 			n.log.Error("failed to send notification", "id", notifierState.notifier.GetNotifierId(), "error", err)
 		}
@@ -117,6 +194,90 @@ func (n *notificationService) sendNotifications(evalContext *EvalContext, notifi
 	return nil
 }
 
+// sendDigest sends the queued notifications of a group. The evaluation
+// contexts have ended by now so they get a new context for sending.
+func (n *notificationService) sendDigest(notifier Notifier, title string, items []*groupedNotification) {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+	defer cancel()
+
+	target := newDeliveryTarget(items[0].evalContext, notifier)
+	if len(items) > 1 {
+		target.AlertId = 0
+	}
+	ctx = m.WithNotificationDeliveryTarget(ctx, target)
+
+	for _, item := range items {
+		item.evalContext.Ctx = ctx
+	}
+
+	if len(items) == 1 {
+		item := items[0]
+		if err := n.sendAndMarkAsComplete(item.evalContext, &notifierState{notifier: notifier, state: item.state}); err != nil {
+			n.log.Error("failed to send notification", "id", notifier.GetNotifierId(), "error", err)
+		}
+		return
+	}
+
+	digest := &NotificationDigest{
+		GroupBy: notifier.GetGroupBy(),
+		Title:   title,
+		Alerts:  make([]*EvalContext, 0, len(items)),
+		Ctx:     ctx,
+	}
+	for _, item := range items {
+		digest.Alerts = append(digest.Alerts, item.evalContext)
+	}
+
+	n.log.Debug("Sending notification digest", "type", notifier.GetType(), "id", notifier.GetNotifierId(), "alerts", len(items))
+	metrics.M_Alerting_Notification_Sent.WithLabelValues(notifier.GetType()).Inc()
+
+	var err error
+	if digestNotifier, ok := notifier.(DigestNotifier); ok {
+		err = digestNotifier.NotifyDigest(digest)
+	} else {
+		err = notifier.Notify(digest.EvalContext())
+	}
+
+	if err != nil {
+		n.log.Error("failed to send notification digest", "id", notifier.GetNotifierId(), "error", err)
+	}
+
+	for _, item := range items {
+		cmd := &m.SetAlertNotificationStateToCompleteCommand{
+			Id:      item.state.Id,
+			Version: item.state.Version,
+		}
+
+		if err := bus.DispatchCtx(ctx, cmd); err != nil {
+			n.log.Error("failed to mark notification as complete", "id", notifier.GetNotifierId(), "error", err)
+		}
+	}
+}
+
+func newDeliveryTarget(evalContext *EvalContext, notifier Notifier) *m.NotificationDeliveryTarget {
+	return &m.NotificationDeliveryTarget{
+		OrgId:        evalContext.Rule.OrgId,
+		AlertId:      evalContext.Rule.Id,
+		NotifierId:   notifier.GetNotifierId(),
+		NotifierType: notifier.GetType(),
+	}
+}
+
+// notifyWithDelivery sends the notification with the channel as delivery
+// target, so webhooks that fail are retried from the delivery queue. Test
+// runs report failures right away instead.
+func notifyWithDelivery(evalContext *EvalContext, notifier Notifier) error {
+	if evalContext.IsTestRun {
+		return notifier.Notify(evalContext)
+	}
+
+	ctx := evalContext.Ctx
+	evalContext.Ctx = m.WithNotificationDeliveryTarget(ctx, newDeliveryTarget(evalContext, notifier))
+	defer func() { evalContext.Ctx = ctx }()
+
+	return notifier.Notify(evalContext)
+}
+
 func (n *notificationService) uploadImage(context *EvalContext) (err error) {
 	uploader, err := imguploader.NewImageUploader()
 	if err != nil {
@@ -126,7 +287,7 @@ func (n *notificationService) uploadImage(context *EvalContext) (err error) {
 	renderOpts := rendering.Opts{
 		Width:           1000,
 		Height:          500,
-		Timeout:         alertTimeout / 2,
+		Timeout:         renderTimeout,
 		OrgId:           context.Rule.OrgId,
 		OrgRole:         m.ROLE_ADMIN,
 		ConcurrentLimit: setting.AlertingRenderLimit,
@@ -178,6 +339,10 @@ func (n *notificationService) getNeededNotifiers(orgId int64, notificationIds []
 			OrgId:      evalContext.Rule.OrgId,
 		}
 
+		if evalContext.Instance != nil {
+			query.InstanceId = evalContext.Instance.Id
+		}
+
 		err = bus.DispatchCtx(evalContext.Ctx, query)
 		if err != nil {
 			n.log.Error("Could not get notification state.", "notifier", notification.Id, "error", err)
@@ -196,6 +361,10 @@ func (n *notificationService) getNeededNotifiers(orgId int64, notificationIds []
 }
 
 func (n *notificationService) createNotifierFor(model *m.AlertNotification) (Notifier, error) {
+	return createNotifier(model)
+}
+
+func createNotifier(model *m.AlertNotification) (Notifier, error) {
 	notifierPlugin, found := notifierFactories[model.Type]
 	if !found {
 		return nil, errors.New("Unsupported notification type")
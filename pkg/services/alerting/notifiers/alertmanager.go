@@ -70,10 +70,7 @@ func (this *AlertmanagerNotifier) createAlert(evalContext *alerting.EvalContext,
 
 	// Annotations (summary and description are very commonly used).
 	alertJSON.SetPath([]string{"annotations", "summary"}, evalContext.Rule.Name)
-	description := ""
-	if evalContext.Rule.Message != "" {
-		description += evalContext.Rule.Message
-	}
+	description := this.GetBody(evalContext)
 	if evalContext.Error != nil {
 		if description != "" {
 			description += "\n"
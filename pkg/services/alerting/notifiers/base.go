@@ -2,8 +2,10 @@ package notifiers
 
 import (
 	"context"
+	"text/template"
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/alerting"
@@ -11,6 +13,9 @@ import (
 
 const (
 	triggMetrString = "Triggered metrics:\n\n"
+
+	defaultGroupWait     = 30 * time.Second
+	defaultGroupInterval = 5 * time.Minute
 )
 
 type NotifierBase struct {
@@ -22,6 +27,11 @@ type NotifierBase struct {
 	SendReminder          bool
 	DisableResolveMessage bool
 	Frequency             time.Duration
+	GroupBy               string
+	GroupWait             time.Duration
+	GroupInterval         time.Duration
+	TitleTemplate         *template.Template
+	BodyTemplate          *template.Template
 
 	log log.Logger
 }
@@ -33,6 +43,19 @@ func NewNotifierBase(model *models.AlertNotification) NotifierBase {
 		uploadImage = value.MustBool()
 	}
 
+	logger := log.New("alerting.notifier." + model.Name)
+
+	// templates are validated when the notification is saved
+	titleTemplate, err := alerting.ParseNotificationTemplate("title", model.Settings.Get("titleTemplate").MustString())
+	if err != nil {
+		logger.Error("Invalid title template", "error", err)
+	}
+
+	bodyTemplate, err := alerting.ParseNotificationTemplate("body", model.Settings.Get("bodyTemplate").MustString())
+	if err != nil {
+		logger.Error("Invalid body template", "error", err)
+	}
+
 	return NotifierBase{
 		Id:                    model.Id,
 		Name:                  model.Name,
@@ -42,12 +65,36 @@ func NewNotifierBase(model *models.AlertNotification) NotifierBase {
 		SendReminder:          model.SendReminder,
 		DisableResolveMessage: model.DisableResolveMessage,
 		Frequency:             model.Frequency,
-		log:                   log.New("alerting.notifier." + model.Name),
+		GroupBy:               model.Settings.Get("groupBy").MustString(),
+		GroupWait:             settingsDuration(model.Settings, "groupWait", defaultGroupWait),
+		GroupInterval:         settingsDuration(model.Settings, "groupInterval", defaultGroupInterval),
+		TitleTemplate:         titleTemplate,
+		BodyTemplate:          bodyTemplate,
+		log:                   logger,
 	}
 }
 
+// settingsDuration reads a duration like "30s" from the notifier settings
+// and falls back to defaultValue when it is missing or invalid.
+func settingsDuration(settings *simplejson.Json, key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(settings.Get(key).MustString())
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+
+	return value
+}
+
 // ShouldNotify checks this evaluation should send an alert notification
 func (n *NotifierBase) ShouldNotify(ctx context.Context, context *alerting.EvalContext, notiferState *models.AlertNotificationState) bool {
+	// Send notifications that were held back by a silence when the current
+	// state was reached.
+	if context.PrevAlertState == context.Rule.State &&
+		notiferState.State == models.AlertNotificationStateSilenced &&
+		notiferState.AlertRuleStateUpdatedVersion == context.Rule.StateChanges {
+		return context.Rule.State != models.AlertStateOK || !n.DisableResolveMessage
+	}
+
 	// Only notify on state change.
 	if context.PrevAlertState == context.Rule.State && !n.SendReminder {
 		return false
@@ -56,7 +103,7 @@ func (n *NotifierBase) ShouldNotify(ctx context.Context, context *alerting.EvalC
 	if context.PrevAlertState == context.Rule.State && n.SendReminder {
 		// Do not notify if interval has not elapsed
 		lastNotify := time.Unix(notiferState.UpdatedAt, 0)
-		if notiferState.UpdatedAt != 0 && lastNotify.Add(n.Frequency).After(time.Now()) {
+		if notiferState.UpdatedAt != 0 && lastNotify.Add(n.Frequency).After(context.Now()) {
 			return false
 		}
 
@@ -89,7 +136,7 @@ func (n *NotifierBase) ShouldNotify(ctx context.Context, context *alerting.EvalC
 	// Do not notifu if state pending and it have been updated last minute
 	if notiferState.State == models.AlertNotificationStatePending {
 		lastUpdated := time.Unix(notiferState.UpdatedAt, 0)
-		if lastUpdated.Add(1 * time.Minute).After(time.Now()) {
+		if lastUpdated.Add(1 * time.Minute).After(context.Now()) {
 			return false
 		}
 	}
@@ -129,3 +176,37 @@ func (n *NotifierBase) GetDisableResolveMessage() bool {
 func (n *NotifierBase) GetFrequency() time.Duration {
 	return n.Frequency
 }
+
+func (n *NotifierBase) GetGroupBy() string {
+	return n.GroupBy
+}
+
+func (n *NotifierBase) GetGroupWait() time.Duration {
+	return n.GroupWait
+}
+
+func (n *NotifierBase) GetGroupInterval() time.Duration {
+	return n.GroupInterval
+}
+
+// GetTitle returns the notification title rendered from the title template
+// of the channel, or the default title of the alert.
+func (n *NotifierBase) GetTitle(evalContext *alerting.EvalContext) string {
+	title, err := alerting.RenderNotificationTemplate(n.TitleTemplate, evalContext, evalContext.GetNotificationTitle())
+	if err != nil {
+		n.log.Error("Failed to render title template", "error", err)
+	}
+
+	return title
+}
+
+// GetBody returns the notification message rendered from the body template
+// of the channel, or the message of the alert rule.
+func (n *NotifierBase) GetBody(evalContext *alerting.EvalContext) string {
+	body, err := alerting.RenderNotificationTemplate(n.BodyTemplate, evalContext, evalContext.Rule.Message)
+	if err != nil {
+		n.log.Error("Failed to render body template", "error", err)
+	}
+
+	return body
+}
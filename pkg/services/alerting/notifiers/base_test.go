@@ -127,6 +127,30 @@ func TestShouldSendAlertNotification(t *testing.T) {
 
 			expect: true,
 		},
+		{
+			name:      "alerting -> alerting with notification held back by a silence should trigger",
+			newState:  m.AlertStateAlerting,
+			prevState: m.AlertStateAlerting,
+			state:     &m.AlertNotificationState{State: m.AlertNotificationStateSilenced, UpdatedAt: tnow.Add(-30 * time.Second).Unix()},
+
+			expect: true,
+		},
+		{
+			name:      "alerting -> alerting with notification held back for a previous state change should not trigger",
+			newState:  m.AlertStateAlerting,
+			prevState: m.AlertStateAlerting,
+			state:     &m.AlertNotificationState{State: m.AlertNotificationStateSilenced, UpdatedAt: tnow.Add(-2 * time.Minute).Unix(), AlertRuleStateUpdatedVersion: 3},
+
+			expect: false,
+		},
+		{
+			name:      "alerting -> alerting with notification state pending and updated 2 minutes ago should not trigger",
+			newState:  m.AlertStateAlerting,
+			prevState: m.AlertStateAlerting,
+			state:     &m.AlertNotificationState{State: m.AlertNotificationStatePending, UpdatedAt: tnow.Add(-2 * time.Minute).Unix()},
+
+			expect: false,
+		},
 		{
 			name:      "unknown -> ok",
 			prevState: m.AlertStateUnknown,
@@ -202,5 +226,34 @@ func TestBaseNotifier(t *testing.T) {
 			base := NewNotifierBase(model)
 			So(base.DisableResolveMessage, ShouldBeFalse)
 		})
+
+		Convey("can parse notification grouping", func() {
+			bJson.Set("groupBy", "dashboard")
+			bJson.Set("groupWait", "1m")
+
+			base := NewNotifierBase(model)
+			So(base.GroupBy, ShouldEqual, "dashboard")
+			So(base.GroupWait, ShouldEqual, time.Minute)
+			So(base.GroupInterval, ShouldEqual, defaultGroupInterval)
+		})
+
+		Convey("can render title and body templates", func() {
+			bJson.Set("titleTemplate", "{{.RuleName}} is {{.State}}")
+
+			base := NewNotifierBase(model)
+			evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{Name: "cpu", Message: "cpu is high", State: m.AlertStateAlerting})
+			evalContext.IsTestRun = true
+
+			So(base.GetTitle(evalContext), ShouldEqual, "cpu is alerting")
+			So(base.GetBody(evalContext), ShouldEqual, "cpu is high")
+		})
+
+		Convey("invalid group wait should use default", func() {
+			bJson.Set("groupWait", "soon")
+
+			base := NewNotifierBase(model)
+			So(base.GroupBy, ShouldEqual, "")
+			So(base.GroupWait, ShouldEqual, defaultGroupWait)
+		})
 	})
 }
@@ -54,9 +54,9 @@ func (this *DingDingNotifier) Notify(evalContext *alerting.EvalContext) error {
 	}
 	this.log.Info("messageUrl:" + messageUrl)
 
-	message := evalContext.Rule.Message
+	message := this.GetBody(evalContext)
 	picUrl := evalContext.ImagePublicUrl
-	title := evalContext.GetNotificationTitle()
+	title := this.GetTitle(evalContext)
 	if message == "" {
 		message = title
 	}
@@ -82,11 +82,11 @@ func (this *DiscordNotifier) Notify(evalContext *alerting.EvalContext) error {
 	color, _ := strconv.ParseInt(strings.TrimLeft(evalContext.GetStateModel().Color, "#"), 16, 0)
 
 	embed := simplejson.New()
-	embed.Set("title", evalContext.GetNotificationTitle())
+	embed.Set("title", this.GetTitle(evalContext))
 	//Discord takes integer for color
 	embed.Set("color", color)
 	embed.Set("url", ruleUrl)
-	embed.Set("description", evalContext.Rule.Message)
+	embed.Set("description", this.GetBody(evalContext))
 	embed.Set("type", "rich")
 	embed.Set("fields", fields)
 	embed.Set("footer", footer)
@@ -74,13 +74,13 @@ func (this *EmailNotifier) Notify(evalContext *alerting.EvalContext) error {
 
 	cmd := &m.SendEmailCommandSync{
 		SendEmailCommand: m.SendEmailCommand{
-			Subject: evalContext.GetNotificationTitle(),
+			Subject: this.GetTitle(evalContext),
 			Data: map[string]interface{}{
-				"Title":        evalContext.GetNotificationTitle(),
+				"Title":        this.GetTitle(evalContext),
 				"State":        evalContext.Rule.State,
 				"Name":         evalContext.Rule.Name,
 				"StateModel":   evalContext.GetStateModel(),
-				"Message":      evalContext.Rule.Message,
+				"Message":      this.GetBody(evalContext),
 				"Error":        error,
 				"RuleUrl":      ruleUrl,
 				"ImageLink":    "",
@@ -0,0 +1,73 @@
+package notifiers
+
+import (
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        alerting.EscalationNotifierType,
+		Name:        "Escalation chain",
+		Description: "Notifies other channels one after another until the alert is acknowledged or resolved",
+		Factory:     NewEscalationNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">Escalation steps</h3>
+      <div ng-init="ctrl.model.settings.steps = ctrl.model.settings.steps || []">
+        <div class="gf-form-inline" ng-repeat="step in ctrl.model.settings.steps">
+          <div class="gf-form">
+            <span class="gf-form-label width-6">Step {{$index + 1}}</span>
+            <span class="gf-form-label width-6">Channel uid</span>
+            <input type="text" required class="gf-form-input max-width-14" ng-model="step.notifierUid" placeholder="notifier uid"></input>
+          </div>
+          <div class="gf-form">
+            <span class="gf-form-label width-5">After</span>
+            <input type="text" class="gf-form-input max-width-6" ng-model="step.delay" placeholder="15m"></input>
+            <info-popover mode="right-absolute">
+              Time without acknowledgement before this step is notified, e.g. 0s, 15m or 1h.
+            </info-popover>
+          </div>
+          <div class="gf-form">
+            <a class="gf-form-label" ng-click="ctrl.model.settings.steps.splice($index, 1)"><i class="fa fa-trash"></i></a>
+          </div>
+        </div>
+        <div class="gf-form">
+          <a class="btn btn-inverse" ng-click="ctrl.model.settings.steps.push({notifierUid: '', delay: '15m'})"><i class="fa fa-plus"></i>&nbsp;Add step</a>
+        </div>
+      </div>
+    `,
+	})
+}
+
+func NewEscalationNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	steps, err := alerting.ParseEscalationSteps(model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EscalationNotifier{
+		NotifierBase: NewNotifierBase(model),
+		Steps:        steps,
+		log:          log.New("alerting.notifier.escalation"),
+	}, nil
+}
+
+// EscalationNotifier notifies its steps in order until the alert is
+// acknowledged. The steps after the first one are sent by the alerting
+// engine once their delay has passed.
+type EscalationNotifier struct {
+	NotifierBase
+	Steps []*alerting.EscalationStep
+	log   log.Logger
+}
+
+func (this *EscalationNotifier) Notify(evalContext *alerting.EvalContext) error {
+	if evalContext.Rule.State == m.AlertStateOK {
+		this.log.Info("Resolving escalation", "alertId", evalContext.Rule.Id)
+		return alerting.ResolveEscalation(evalContext, this.Id, this.Steps)
+	}
+
+	this.log.Info("Starting escalation", "alertId", evalContext.Rule.Id, "steps", len(this.Steps))
+	return alerting.StartEscalation(evalContext, this.Id, this.Steps)
+}
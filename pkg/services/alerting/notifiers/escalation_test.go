@@ -0,0 +1,71 @@
+package notifiers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEscalationNotifier(t *testing.T) {
+	Convey("Escalation notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				settingsJSON, _ := simplejson.NewJson([]byte(`{ }`))
+				model := &m.AlertNotification{
+					Name:     "escalation_testing",
+					Type:     "escalation",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewEscalationNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("invalid delay should return error", func() {
+				json := `{ "steps": [{ "notifierUid": "slack", "delay": "soon" }] }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "escalation_testing",
+					Type:     "escalation",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewEscalationNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+					"steps": [
+						{ "notifierUid": "slack" },
+						{ "notifierUid": "pagerduty", "delay": "15m" }
+					]
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "escalation_testing",
+					Type:     "escalation",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewEscalationNotifier(model)
+				escalationNotifier := not.(*EscalationNotifier)
+
+				So(err, ShouldBeNil)
+				So(escalationNotifier.Name, ShouldEqual, "escalation_testing")
+				So(len(escalationNotifier.Steps), ShouldEqual, 2)
+				So(escalationNotifier.Steps[0].NotifierUid, ShouldEqual, "slack")
+				So(escalationNotifier.Steps[0].Delay, ShouldEqual, 0)
+				So(escalationNotifier.Steps[1].NotifierUid, ShouldEqual, "pagerduty")
+				So(escalationNotifier.Steps[1].Delay, ShouldEqual, 15*time.Minute)
+			})
+		})
+	})
+}
@@ -126,7 +126,7 @@ func (this *GoogleChatNotifier) Notify(evalContext *alerting.EvalContext) error
 	widgets := []widget{
 		textParagraphWidget{
 			Text: text{
-				Text: evalContext.Rule.Message,
+				Text: this.GetBody(evalContext),
 			},
 		},
 	}
@@ -187,7 +187,7 @@ func (this *GoogleChatNotifier) Notify(evalContext *alerting.EvalContext) error
 		Cards: []card{
 			{
 				Header: header{
-					Title: evalContext.GetNotificationTitle(),
+					Title: this.GetTitle(evalContext),
 				},
 				Sections: []section{
 					{
@@ -112,11 +112,11 @@ func (this *HipChatNotifier) Notify(evalContext *alerting.EvalContext) error {
 
 	message := ""
 	if evalContext.Rule.State != models.AlertStateOK { //don't add message when going back to alert state ok.
-		message += " " + evalContext.Rule.Message
+		message += " " + this.GetBody(evalContext)
 	}
 
 	if message == "" {
-		message = evalContext.GetNotificationTitle() + " in state " + evalContext.GetStateModel().Text
+		message = this.GetTitle(evalContext) + " in state " + evalContext.GetStateModel().Text
 	}
 
 	//HipChat has a set list of colors
@@ -135,7 +135,7 @@ func (this *HipChatNotifier) Notify(evalContext *alerting.EvalContext) error {
 		"style":       "application",
 		"url":         ruleUrl,
 		"id":          "1",
-		"title":       evalContext.GetNotificationTitle(),
+		"title":       this.GetTitle(evalContext),
 		"description": message,
 		"icon": map[string]interface{}{
 			"url": "https://grafana.com/assets/img/fav32.png",
@@ -0,0 +1,247 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "jira",
+		Name:        "Jira",
+		Description: "Creates a Jira issue for every alert and resolves it once the alert is ok",
+		Factory:     NewJiraNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">Jira settings</h3>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Url</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.url" placeholder="https://example.atlassian.net"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Username</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.username"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Password</span>
+        <input type="password" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.password"></input>
+        <info-popover mode="right-absolute">
+          Password or API token of the user
+        </info-popover>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Project key</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.project" placeholder="OPS"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Issue type</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.issueType" placeholder="Bug"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Labels</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.labels" placeholder="grafana, alert"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Resolve transition</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.resolveTransition" placeholder="Done"></input>
+        <info-popover mode="right-absolute">
+          Name or id of the workflow transition used once the alert is ok
+        </info-popover>
+      </div>
+    `,
+	})
+}
+
+func NewJiraNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	jiraUrl := strings.TrimRight(model.Settings.Get("url").MustString(), "/")
+	if jiraUrl == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find url property in settings"}
+	}
+
+	username := model.Settings.Get("username").MustString()
+	password := model.Settings.Get("password").MustString()
+	if username == "" || password == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find username and password properties in settings"}
+	}
+
+	project := model.Settings.Get("project").MustString()
+	if project == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find project property in settings"}
+	}
+
+	labels := make([]string, 0)
+	for _, label := range strings.Split(model.Settings.Get("labels").MustString(), ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	return &JiraNotifier{
+		NotifierBase:      NewNotifierBase(model),
+		Url:               jiraUrl,
+		Username:          username,
+		Password:          password,
+		Project:           project,
+		IssueType:         model.Settings.Get("issueType").MustString("Bug"),
+		Labels:            labels,
+		ResolveTransition: model.Settings.Get("resolveTransition").MustString("Done"),
+		log:               log.New("alerting.notifier.jira"),
+	}, nil
+}
+
+type JiraNotifier struct {
+	NotifierBase
+	Url               string
+	Username          string
+	Password          string
+	Project           string
+	IssueType         string
+	Labels            []string
+	ResolveTransition string
+	log               log.Logger
+}
+
+func (this *JiraNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Executing jira notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+
+	if err := notifyTicket(evalContext, this.Id, this); err != nil {
+		this.log.Error("Failed to send jira notification", "error", err, "notification", this.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (this *JiraNotifier) createTicket(evalContext *alerting.EvalContext) (string, error) {
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+		return "", err
+	}
+
+	issue := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": this.Project},
+			"issuetype":   map[string]string{"name": this.IssueType},
+			"summary":     this.GetTitle(evalContext),
+			"description": ticketDescription(evalContext, this.GetBody(evalContext), ruleUrl),
+			"labels":      this.Labels,
+		},
+	}
+
+	cmd := this.request("POST", "/rest/api/2/issue", issue)
+
+	// the issue key is needed right away, so creating it is never queued for retry
+	if err := bus.DispatchCtx(m.WithNotificationDeliveryTarget(evalContext.Ctx, nil), cmd); err != nil {
+		return "", err
+	}
+
+	result := struct {
+		Key string `json:"key"`
+	}{}
+	if err := json.Unmarshal(cmd.ResponseBody, &result); err != nil {
+		return "", err
+	}
+
+	if result.Key == "" {
+		return "", errors.New("Jira did not return the key of the created issue")
+	}
+
+	this.log.Info("Created jira issue", "issue", result.Key, "ruleId", evalContext.Rule.Id)
+	return result.Key, nil
+}
+
+func (this *JiraNotifier) commentTicket(evalContext *alerting.EvalContext, issueKey string) error {
+	comment := map[string]string{
+		"body": this.comment(evalContext),
+	}
+
+	return bus.DispatchCtx(evalContext.Ctx, this.request("POST", "/rest/api/2/issue/"+url.PathEscape(issueKey)+"/comment", comment))
+}
+
+func (this *JiraNotifier) resolveTicket(evalContext *alerting.EvalContext, issueKey string) error {
+	transitionId, err := this.resolveTransitionId(evalContext, issueKey)
+	if err != nil {
+		return err
+	}
+
+	transition := map[string]interface{}{
+		"transition": map[string]string{"id": transitionId},
+		"update": map[string]interface{}{
+			"comment": []interface{}{
+				map[string]interface{}{"add": map[string]string{"body": this.comment(evalContext)}},
+			},
+		},
+	}
+
+	return bus.DispatchCtx(evalContext.Ctx, this.request("POST", "/rest/api/2/issue/"+url.PathEscape(issueKey)+"/transitions", transition))
+}
+
+// resolveTransitionId looks up the id of the resolve transition by name,
+// transition ids differ between workflows.
+func (this *JiraNotifier) resolveTransitionId(evalContext *alerting.EvalContext, issueKey string) (string, error) {
+	if _, err := strconv.Atoi(this.ResolveTransition); err == nil {
+		return this.ResolveTransition, nil
+	}
+
+	cmd := this.request("GET", "/rest/api/2/issue/"+url.PathEscape(issueKey)+"/transitions", nil)
+	if err := bus.DispatchCtx(m.WithNotificationDeliveryTarget(evalContext.Ctx, nil), cmd); err != nil {
+		return "", err
+	}
+
+	result := struct {
+		Transitions []struct {
+			Id   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}{}
+	if err := json.Unmarshal(cmd.ResponseBody, &result); err != nil {
+		return "", err
+	}
+
+	for _, transition := range result.Transitions {
+		if strings.EqualFold(transition.Name, this.ResolveTransition) {
+			return transition.Id, nil
+		}
+	}
+
+	return "", fmt.Errorf("Jira issue %s has no transition named %s", issueKey, this.ResolveTransition)
+}
+
+func (this *JiraNotifier) comment(evalContext *alerting.EvalContext) string {
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+	}
+
+	message := ""
+	if evalContext.Rule.State != m.AlertStateOK {
+		message = this.GetBody(evalContext)
+	}
+
+	return this.GetTitle(evalContext) + "\n\n" + ticketDescription(evalContext, message, ruleUrl)
+}
+
+func (this *JiraNotifier) request(method string, path string, body interface{}) *m.SendWebhookSync {
+	cmd := &m.SendWebhookSync{
+		Url:        this.Url + path,
+		User:       this.Username,
+		Password:   this.Password,
+		HttpMethod: method,
+	}
+
+	if body != nil {
+		data, _ := json.Marshal(body)
+		cmd.Body = string(data)
+	}
+
+	return cmd
+}
@@ -0,0 +1,121 @@
+package notifiers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJiraNotifier(t *testing.T) {
+	Convey("Jira notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "jira_testing",
+					Type:     "jira",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewJiraNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+					"url": "https://example.atlassian.net/",
+					"username": "grafana",
+					"password": "secret",
+					"project": "OPS",
+					"labels": "grafana, alert"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "jira_testing",
+					Type:     "jira",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewJiraNotifier(model)
+				jiraNotifier := not.(*JiraNotifier)
+
+				So(err, ShouldBeNil)
+				So(jiraNotifier.Name, ShouldEqual, "jira_testing")
+				So(jiraNotifier.Type, ShouldEqual, "jira")
+				So(jiraNotifier.Url, ShouldEqual, "https://example.atlassian.net")
+				So(jiraNotifier.Project, ShouldEqual, "OPS")
+				So(jiraNotifier.IssueType, ShouldEqual, "Bug")
+				So(jiraNotifier.Labels, ShouldResemble, []string{"grafana", "alert"})
+				So(jiraNotifier.ResolveTransition, ShouldEqual, "Done")
+			})
+		})
+
+		Convey("Given a jira stand-in", func() {
+			standIn := newTicketStandIn(func(r *ticketRequest) string {
+				switch {
+				case r.Method == "POST" && r.Path == "/rest/api/2/issue":
+					return `{"id": "10001", "key": "OPS-1"}`
+				case r.Method == "GET" && strings.HasSuffix(r.Path, "/transitions"):
+					return `{"transitions": [{"id": "11", "name": "In Progress"}, {"id": "31", "name": "Done"}]}`
+				}
+				return `{}`
+			})
+			defer standIn.close()
+
+			settingsJSON, _ := simplejson.NewJson([]byte(`{"url": "` + standIn.server.URL + `", "username": "grafana", "password": "secret", "project": "OPS"}`))
+			not, _ := NewJiraNotifier(&m.AlertNotification{Id: 3, Name: "jira", Type: "jira", Settings: settingsJSON})
+
+			Convey("Alerting should create an issue", func() {
+				So(not.Notify(newTicketEvalContext(m.AlertStateAlerting)), ShouldBeNil)
+
+				So(len(standIn.requests), ShouldEqual, 1)
+				So(standIn.requests[0].User, ShouldEqual, "grafana")
+				So(standIn.requests[0].Body, ShouldContainSubstring, `"summary":"[Alerting] CPU high"`)
+				So(standIn.requests[0].Body, ShouldContainSubstring, `"key":"OPS"`)
+				So(standIn.state.ExternalId, ShouldEqual, "OPS-1")
+
+				Convey("Reminder should comment on the same issue", func() {
+					So(not.Notify(newTicketEvalContext(m.AlertStateAlerting)), ShouldBeNil)
+
+					So(len(standIn.requests), ShouldEqual, 2)
+					So(standIn.requests[1].Path, ShouldEqual, "/rest/api/2/issue/OPS-1/comment")
+					So(standIn.state.ExternalId, ShouldEqual, "OPS-1")
+				})
+
+				Convey("Ok should transition the issue to done", func() {
+					So(not.Notify(newTicketEvalContext(m.AlertStateOK)), ShouldBeNil)
+
+					So(len(standIn.requests), ShouldEqual, 3)
+					So(standIn.requests[2].Method, ShouldEqual, "POST")
+					So(standIn.requests[2].Path, ShouldEqual, "/rest/api/2/issue/OPS-1/transitions")
+					So(standIn.requests[2].Body, ShouldContainSubstring, `"transition":{"id":"31"}`)
+					So(standIn.state.ExternalId, ShouldEqual, "")
+				})
+			})
+
+			Convey("Ok without an issue should do nothing", func() {
+				So(not.Notify(newTicketEvalContext(m.AlertStateOK)), ShouldBeNil)
+				So(len(standIn.requests), ShouldEqual, 0)
+			})
+
+			Convey("Test run should always create an issue", func() {
+				standIn.state.ExternalId = "OPS-7"
+
+				evalContext := newTicketEvalContext(m.AlertStateAlerting)
+				evalContext.IsTestRun = true
+
+				So(not.Notify(evalContext), ShouldBeNil)
+				So(standIn.requests[0].Path, ShouldEqual, "/rest/api/2/issue")
+				So(standIn.state.ExternalId, ShouldEqual, "OPS-7")
+			})
+		})
+	})
+}
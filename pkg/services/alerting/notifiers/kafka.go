@@ -72,7 +72,7 @@ func (this *KafkaNotifier) Notify(evalContext *alerting.EvalContext) error {
 	records := make([]interface{}, 1)
 
 	bodyJSON := simplejson.New()
-	bodyJSON.Set("description", evalContext.Rule.Name+" - "+evalContext.Rule.Message)
+	bodyJSON.Set("description", evalContext.Rule.Name+" - "+this.GetBody(evalContext))
 	bodyJSON.Set("client", "Grafana")
 	bodyJSON.Set("details", customData)
 	bodyJSON.Set("incident_key", "alertId-"+strconv.FormatInt(evalContext.Rule.Id, 10))
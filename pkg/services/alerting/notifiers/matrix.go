@@ -0,0 +1,155 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "matrix",
+		Name:        "Matrix",
+		Description: "Sends notifications to a Matrix room using the client-server API",
+		Factory:     NewMatrixNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">Matrix settings</h3>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-9">Homeserver URL</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.homeserverUrl" placeholder="https://matrix.example.com"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-9">Room ID</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.roomId" placeholder="!abcdefghijklmnop:example.com"></input>
+        <info-popover mode="right-absolute">
+          The internal room id, shown in the advanced room settings. The user of the token must have joined the room
+        </info-popover>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-9">Access token</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.accessToken"></input>
+        <info-popover mode="right-absolute">
+          Access token of the user that sends the notifications, preferably a bot user
+        </info-popover>
+      </div>
+    `,
+	})
+}
+
+func NewMatrixNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	homeserverUrl := strings.TrimRight(model.Settings.Get("homeserverUrl").MustString(), "/")
+	if homeserverUrl == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find homeserverUrl property in settings"}
+	}
+
+	roomId := model.Settings.Get("roomId").MustString()
+	if roomId == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find roomId property in settings"}
+	}
+
+	accessToken := model.Settings.Get("accessToken").MustString()
+	if accessToken == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find accessToken property in settings"}
+	}
+
+	return &MatrixNotifier{
+		NotifierBase:  NewNotifierBase(model),
+		HomeserverUrl: homeserverUrl,
+		RoomId:        roomId,
+		AccessToken:   accessToken,
+		log:           log.New("alerting.notifier.matrix"),
+	}, nil
+}
+
+type MatrixNotifier struct {
+	NotifierBase
+	HomeserverUrl string
+	RoomId        string
+	AccessToken   string
+	log           log.Logger
+}
+
+func (this *MatrixNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Sending matrix notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+		return err
+	}
+
+	body, _ := json.Marshal(this.buildMessage(evalContext, ruleUrl))
+
+	// the transaction id makes retries of the same message idempotent
+	txnId := fmt.Sprintf("grafana-%d-%d", evalContext.Rule.Id, time.Now().UnixNano())
+
+	cmd := &m.SendWebhookSync{
+		Url:        this.messageUrl(txnId),
+		Body:       string(body),
+		HttpMethod: "PUT",
+		HttpHeader: map[string]string{
+			"Authorization": "Bearer " + this.AccessToken,
+		},
+	}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		this.log.Error("Failed to send matrix notification", "error", err, "notification", this.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (this *MatrixNotifier) messageUrl(txnId string) string {
+	return fmt.Sprintf("%s/_matrix/client/r0/rooms/%s/send/m.room.message/%s", this.HomeserverUrl, url.PathEscape(this.RoomId), txnId)
+}
+
+// buildMessage creates an m.text message with a plain text body for
+// clients that do not render html.
+func (this *MatrixNotifier) buildMessage(evalContext *alerting.EvalContext, ruleUrl string) map[string]interface{} {
+	title := this.GetTitle(evalContext)
+	message := this.GetBody(evalContext)
+
+	plain := []string{title}
+	formatted := []string{fmt.Sprintf(`<strong><font color="%s">%s</font></strong>`, evalContext.GetStateModel().Color, html.EscapeString(title))}
+
+	if message != "" && evalContext.Rule.State != m.AlertStateOK {
+		plain = append(plain, message)
+		formatted = append(formatted, html.EscapeString(message))
+	}
+
+	for _, match := range evalContext.EvalMatches {
+		plain = append(plain, fmt.Sprintf("%s: %s", match.Metric, match.Value))
+		formatted = append(formatted, fmt.Sprintf("<em>%s</em>: %s", html.EscapeString(match.Metric), match.Value))
+	}
+
+	if evalContext.Error != nil {
+		plain = append(plain, "Error message: "+evalContext.Error.Error())
+		formatted = append(formatted, "Error message: "+html.EscapeString(evalContext.Error.Error()))
+	}
+
+	if ruleUrl != "" {
+		plain = append(plain, ruleUrl)
+		formatted = append(formatted, fmt.Sprintf(`<a href="%s">Open in Grafana</a>`, html.EscapeString(ruleUrl)))
+	}
+
+	if this.NeedsImage() && evalContext.ImagePublicUrl != "" {
+		plain = append(plain, evalContext.ImagePublicUrl)
+		formatted = append(formatted, fmt.Sprintf(`<a href="%s">Graph</a>`, html.EscapeString(evalContext.ImagePublicUrl)))
+	}
+
+	return map[string]interface{}{
+		"msgtype":        "m.text",
+		"body":           strings.Join(plain, "\n"),
+		"format":         "org.matrix.custom.html",
+		"formatted_body": strings.Join(formatted, "<br>"),
+	}
+}
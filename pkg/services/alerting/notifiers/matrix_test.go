@@ -0,0 +1,94 @@
+package notifiers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMatrixNotifier(t *testing.T) {
+	Convey("Matrix notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "matrix_testing",
+					Type:     "matrix",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewMatrixNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("missing access token should return error", func() {
+				json := `
+				{
+					"homeserverUrl": "https://matrix.example.com",
+					"roomId": "!room:example.com"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "matrix_testing",
+					Type:     "matrix",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewMatrixNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("settings should trigger incident", func() {
+				json := `
+				{
+					"homeserverUrl": "https://matrix.example.com/",
+					"roomId": "!room:example.com",
+					"accessToken": "secret"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "matrix_testing",
+					Type:     "matrix",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewMatrixNotifier(model)
+				matrixNotifier := not.(*MatrixNotifier)
+
+				So(err, ShouldBeNil)
+				So(matrixNotifier.Name, ShouldEqual, "matrix_testing")
+				So(matrixNotifier.Type, ShouldEqual, "matrix")
+				So(matrixNotifier.HomeserverUrl, ShouldEqual, "https://matrix.example.com")
+				So(matrixNotifier.RoomId, ShouldEqual, "!room:example.com")
+				So(matrixNotifier.AccessToken, ShouldEqual, "secret")
+				So(matrixNotifier.messageUrl("txn1"), ShouldEqual, "https://matrix.example.com/_matrix/client/r0/rooms/%21room:example.com/send/m.room.message/txn1")
+			})
+		})
+
+		Convey("Building a message should escape html", func() {
+			evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{
+				Name:    "CPU <high>",
+				Message: "Check the servers",
+				State:   m.AlertStateAlerting,
+			})
+			evalContext.EvalMatches = []*alerting.EvalMatch{{Metric: "cpu", Value: null.FloatFrom(99)}}
+
+			message := (&MatrixNotifier{}).buildMessage(evalContext, "http://grafana/d/abc")
+
+			So(message["msgtype"], ShouldEqual, "m.text")
+			So(message["body"], ShouldEqual, "[Alerting] CPU <high>\nCheck the servers\ncpu: 99.000\nhttp://grafana/d/abc")
+			So(message["formatted_body"], ShouldContainSubstring, "CPU &lt;high&gt;")
+			So(message["formatted_body"], ShouldContainSubstring, `<a href="http://grafana/d/abc">Open in Grafana</a>`)
+		})
+	})
+}
@@ -0,0 +1,296 @@
+package notifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "mattermost",
+		Name:        "Mattermost",
+		Description: "Sends notifications to Mattermost via incoming webhooks, uploading images with the Mattermost API",
+		Factory:     NewMattermostNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">Mattermost settings</h3>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Url</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.url" placeholder="https://mattermost.example.com/hooks/xxx"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Channel</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.channel"></input>
+        <info-popover mode="right-absolute">
+          Override the channel of the webhook, use the channel name, e.g. town-square, or @username
+        </info-popover>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Username</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.username"></input>
+        <info-popover mode="right-absolute">
+          Override the username of the webhook, the webhook must allow overriding it
+        </info-popover>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Icon URL</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.iconUrl"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Mention</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.mention" placeholder="@channel"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Token</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.token"></input>
+        <info-popover mode="right-absolute">
+          Bot or personal access token used to upload images when no external image store is configured. Requires the channel id
+        </info-popover>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Channel ID</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.channelId"></input>
+        <info-popover mode="right-absolute">
+          Id of the channel images are posted to, shown in the channel info
+        </info-popover>
+      </div>
+    `,
+	})
+}
+
+func NewMattermostNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	url := model.Settings.Get("url").MustString()
+	if url == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find url property in settings"}
+	}
+
+	notifier := &MattermostNotifier{
+		NotifierBase: NewNotifierBase(model),
+		Url:          url,
+		Channel:      model.Settings.Get("channel").MustString(),
+		Username:     model.Settings.Get("username").MustString(),
+		IconUrl:      model.Settings.Get("iconUrl").MustString(),
+		Mention:      model.Settings.Get("mention").MustString(),
+		Token:        model.Settings.Get("token").MustString(),
+		ChannelId:    model.Settings.Get("channelId").MustString(),
+		log:          log.New("alerting.notifier.mattermost"),
+	}
+
+	if notifier.Token != "" {
+		if notifier.ChannelId == "" {
+			return nil, alerting.ValidationError{Reason: "Image upload with a token requires the channelId property"}
+		}
+
+		// the API is served by the same server as the webhook
+		i := strings.Index(url, "/hooks/")
+		if i < 0 {
+			return nil, alerting.ValidationError{Reason: "Image upload requires a Mattermost incoming webhook url, e.g. https://mattermost.example.com/hooks/xxx"}
+		}
+		notifier.ApiUrl = url[:i] + "/api/v4"
+	}
+
+	return notifier, nil
+}
+
+type MattermostNotifier struct {
+	NotifierBase
+	Url       string
+	Channel   string
+	Username  string
+	IconUrl   string
+	Mention   string
+	Token     string
+	ChannelId string
+	ApiUrl    string
+	log       log.Logger
+}
+
+func (this *MattermostNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Executing mattermost notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+		return err
+	}
+
+	attachment := this.buildAttachment(evalContext, ruleUrl)
+
+	if this.Token != "" && this.NeedsImage() && evalContext.ImagePublicUrl == "" && evalContext.ImageOnDiskPath != "" {
+		return this.postWithImage(evalContext, attachment)
+	}
+
+	body := map[string]interface{}{
+		"text":        this.Mention,
+		"attachments": []map[string]interface{}{attachment},
+	}
+
+	if this.Channel != "" {
+		body["channel"] = this.Channel
+	}
+	if this.Username != "" {
+		body["username"] = this.Username
+	}
+	if this.IconUrl != "" {
+		body["icon_url"] = this.IconUrl
+	}
+
+	data, _ := json.Marshal(body)
+	cmd := &m.SendWebhookSync{Url: this.Url, Body: string(data)}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		this.log.Error("Failed to send mattermost notification", "error", err, "notification", this.Name)
+		return err
+	}
+
+	return nil
+}
+
+// buildAttachment creates a message attachment, Mattermost uses the same
+// format as Slack.
+func (this *MattermostNotifier) buildAttachment(evalContext *alerting.EvalContext, ruleUrl string) map[string]interface{} {
+	fields := make([]map[string]interface{}, 0)
+	for _, match := range evalContext.EvalMatches {
+		fields = append(fields, map[string]interface{}{
+			"title": match.Metric,
+			"value": match.Value.String(),
+			"short": true,
+		})
+	}
+
+	if evalContext.Error != nil {
+		fields = append(fields, map[string]interface{}{
+			"title": "Error message",
+			"value": evalContext.Error.Error(),
+			"short": false,
+		})
+	}
+
+	text := ""
+	if evalContext.Rule.State != m.AlertStateOK {
+		text = this.GetBody(evalContext)
+	}
+
+	attachment := map[string]interface{}{
+		"fallback":    this.GetTitle(evalContext),
+		"color":       evalContext.GetStateModel().Color,
+		"title":       this.GetTitle(evalContext),
+		"title_link":  ruleUrl,
+		"text":        text,
+		"fields":      fields,
+		"footer":      "Grafana v" + setting.BuildVersion,
+		"footer_icon": "https://grafana.com/assets/img/fav32.png",
+		"ts":          time.Now().Unix(),
+	}
+
+	if this.NeedsImage() && evalContext.ImagePublicUrl != "" {
+		attachment["image_url"] = evalContext.ImagePublicUrl
+	}
+
+	return attachment
+}
+
+// postWithImage uploads the rendered graph and creates the post with the
+// Mattermost API, since webhooks cannot attach files.
+func (this *MattermostNotifier) postWithImage(evalContext *alerting.EvalContext, attachment map[string]interface{}) error {
+	fileId, err := this.uploadImage(evalContext)
+	if err != nil {
+		this.log.Error("Failed to upload image to mattermost", "error", err, "notification", this.Name)
+		return err
+	}
+
+	post := map[string]interface{}{
+		"channel_id": this.ChannelId,
+		"message":    this.Mention,
+		"file_ids":   []string{fileId},
+		"props": map[string]interface{}{
+			"attachments": []map[string]interface{}{attachment},
+		},
+	}
+
+	data, _ := json.Marshal(post)
+	cmd := &m.SendWebhookSync{
+		Url:        this.ApiUrl + "/posts",
+		Body:       string(data),
+		HttpMethod: "POST",
+		HttpHeader: map[string]string{"Authorization": "Bearer " + this.Token},
+	}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		this.log.Error("Failed to send mattermost notification", "error", err, "notification", this.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (this *MattermostNotifier) uploadImage(evalContext *alerting.EvalContext) (string, error) {
+	f, err := os.Open(evalContext.ImageOnDiskPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+
+	if err := w.WriteField("channel_id", this.ChannelId); err != nil {
+		return "", err
+	}
+
+	fw, err := w.CreateFormFile("files", "graph.png")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(fw, f); err != nil {
+		return "", err
+	}
+
+	w.Close()
+
+	cmd := &m.SendWebhookSync{
+		Url:         this.ApiUrl + "/files",
+		Body:        b.String(),
+		HttpMethod:  "POST",
+		ContentType: w.FormDataContentType(),
+		HttpHeader:  map[string]string{"Authorization": "Bearer " + this.Token},
+	}
+
+	// the file id is needed right away, so the upload is never queued for retry
+	ctx := m.WithNotificationDeliveryTarget(evalContext.Ctx, nil)
+	if err := bus.DispatchCtx(ctx, cmd); err != nil {
+		return "", err
+	}
+
+	return parseMattermostFileId(cmd.ResponseBody)
+}
+
+func parseMattermostFileId(body []byte) (string, error) {
+	result := struct {
+		FileInfos []struct {
+			Id string `json:"id"`
+		} `json:"file_infos"`
+	}{}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", err
+	}
+
+	if len(result.FileInfos) == 0 || result.FileInfos[0].Id == "" {
+		return "", errors.New("Mattermost file upload returned no file id")
+	}
+
+	return result.FileInfos[0].Id, nil
+}
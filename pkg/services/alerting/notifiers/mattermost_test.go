@@ -0,0 +1,108 @@
+package notifiers
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMattermostNotifier(t *testing.T) {
+	Convey("Mattermost notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "mattermost_testing",
+					Type:     "mattermost",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewMattermostNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+					"url": "https://mattermost.example.com/hooks/abc",
+					"channel": "alerts",
+					"username": "grafana",
+					"mention": "@channel"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "mattermost_testing",
+					Type:     "mattermost",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewMattermostNotifier(model)
+				mattermostNotifier := not.(*MattermostNotifier)
+
+				So(err, ShouldBeNil)
+				So(mattermostNotifier.Name, ShouldEqual, "mattermost_testing")
+				So(mattermostNotifier.Type, ShouldEqual, "mattermost")
+				So(mattermostNotifier.Url, ShouldEqual, "https://mattermost.example.com/hooks/abc")
+				So(mattermostNotifier.Channel, ShouldEqual, "alerts")
+				So(mattermostNotifier.Username, ShouldEqual, "grafana")
+				So(mattermostNotifier.Mention, ShouldEqual, "@channel")
+				So(mattermostNotifier.ApiUrl, ShouldEqual, "")
+			})
+
+			Convey("token without channel id should return error", func() {
+				json := `
+				{
+					"url": "https://mattermost.example.com/hooks/abc",
+					"token": "secret"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "mattermost_testing",
+					Type:     "mattermost",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewMattermostNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("token should enable the api of the webhook server", func() {
+				json := `
+				{
+					"url": "https://mattermost.example.com/hooks/abc",
+					"token": "secret",
+					"channelId": "xyz"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "mattermost_testing",
+					Type:     "mattermost",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewMattermostNotifier(model)
+				mattermostNotifier := not.(*MattermostNotifier)
+
+				So(err, ShouldBeNil)
+				So(mattermostNotifier.ApiUrl, ShouldEqual, "https://mattermost.example.com/api/v4")
+			})
+		})
+
+		Convey("Parsing the file upload response", func() {
+			id, err := parseMattermostFileId([]byte(`{"file_infos": [{"id": "file1", "name": "graph.png"}]}`))
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "file1")
+
+			_, err = parseMattermostFileId([]byte(`{"file_infos": []}`))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,104 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMessageBusNotifiers(t *testing.T) {
+	Convey("Message bus notifiers", t, func() {
+		Convey("TLS config", func() {
+			Convey("is nil without TLS options for an insecure url", func() {
+				tlsConfig, err := newMessageBusTLSConfig(simplejson.New(), false)
+
+				So(err, ShouldBeNil)
+				So(tlsConfig, ShouldBeNil)
+			})
+
+			Convey("is empty for a secure url", func() {
+				tlsConfig, err := newMessageBusTLSConfig(simplejson.New(), true)
+
+				So(err, ShouldBeNil)
+				So(tlsConfig, ShouldNotBeNil)
+				So(tlsConfig.InsecureSkipVerify, ShouldBeFalse)
+				So(tlsConfig.RootCAs, ShouldBeNil)
+			})
+
+			Convey("can skip verification", func() {
+				settingsJSON, _ := simplejson.NewJson([]byte(`{ "tlsSkipVerify": true }`))
+				tlsConfig, err := newMessageBusTLSConfig(settingsJSON, false)
+
+				So(err, ShouldBeNil)
+				So(tlsConfig.InsecureSkipVerify, ShouldBeTrue)
+			})
+
+			Convey("verifies with the CA cert", func() {
+				settingsJSON := simplejson.New()
+				settingsJSON.Set("tlsAuthWithCACert", true)
+				settingsJSON.Set("tlsCACert", messageBusTestCACert)
+				tlsConfig, err := newMessageBusTLSConfig(settingsJSON, false)
+
+				So(err, ShouldBeNil)
+				So(tlsConfig.RootCAs, ShouldNotBeNil)
+			})
+
+			Convey("fails for an invalid CA cert", func() {
+				settingsJSON, _ := simplejson.NewJson([]byte(`{ "tlsAuthWithCACert": true, "tlsCACert": "invalid" }`))
+				_, err := newMessageBusTLSConfig(settingsJSON, false)
+
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("fails for an invalid client cert", func() {
+				settingsJSON, _ := simplejson.NewJson([]byte(`{ "tlsAuth": true, "tlsClientCert": "invalid", "tlsClientKey": "invalid" }`))
+				_, err := newMessageBusTLSConfig(settingsJSON, true)
+
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("alert event has the webhook body", func() {
+			settingsJSON, _ := simplejson.NewJson([]byte(`{ "url": "nats://localhost:4222", "subject": "alerts" }`))
+			not, _ := NewNatsNotifier(&m.AlertNotification{Name: "bus", Type: "nats", Settings: settingsJSON})
+			natsNotifier := not.(*NatsNotifier)
+
+			evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{
+				Id:      1,
+				Name:    "cpu",
+				Message: "cpu is high",
+				State:   m.AlertStateAlerting,
+			})
+			evalContext.EvalMatches = []*alerting.EvalMatch{{Metric: "cpu", Value: null.FloatFrom(95)}}
+
+			var event map[string]interface{}
+			So(json.Unmarshal(alertEventMessage(&natsNotifier.NotifierBase, evalContext), &event), ShouldBeNil)
+			So(event["title"], ShouldEqual, "[Alerting] cpu")
+			So(event["ruleId"], ShouldEqual, 1)
+			So(event["ruleName"], ShouldEqual, "cpu")
+			So(event["state"], ShouldEqual, "alerting")
+			So(event["message"], ShouldEqual, "cpu is high")
+			So(event["evalMatches"], ShouldHaveLength, 1)
+		})
+	})
+}
+
+const messageBusTestCACert = `-----BEGIN CERTIFICATE-----
+MIICBjCCAW+gAwIBAgIUIoMZURwmSSHLeAkG3MvU/2xjAfEwDQYJKoZIhvcNAQEL
+BQAwFDESMBAGA1UEAwwJbG9jYWxob3N0MCAXDTI2MTAxODA2MjUyMloYDzIxMjYw
+OTI0MDYyNTIyWjAUMRIwEAYDVQQDDAlsb2NhbGhvc3QwgZ8wDQYJKoZIhvcNAQEB
+BQADgY0AMIGJAoGBAJZyNA39xOyEW79Hc9mOVr9zTK9cPsiiRKiHdFHm1uV52rh/
+htdmCbh7AazG4luABwbQ+QvI13wDeA3QGE0lRRoUAfeX0L1gn66nxemlDoEL6f5f
+ig8ZqL5h4KyN4yjpZfHIVYs5MNVTHHJCtO+8GmqV7oWQ0Cs/JqkijJVbxd4jAgMB
+AAGjUzBRMB0GA1UdDgQWBBSM2eOj/0Lm4KOEu0lqWNdBclVOYDAfBgNVHSMEGDAW
+gBSM2eOj/0Lm4KOEu0lqWNdBclVOYDAPBgNVHRMBAf8EBTADAQH/MA0GCSqGSIb3
+DQEBCwUAA4GBAAHkGrt89rqpkC+w6O/3B1n7FQvuRFW0u3RCce+5qrgrCwhJKpa8
+hVmV6OhdrEsZXEEHZvgvHRA/zt8iy9YV3yPlFy082Cx9A8fILPWAu5aAiH44HcUl
+uxMBQE13OiXJz6HEbN/z0KyYt5fEvLXbOY8JLFWV0TrRoN89TfyeEuEm
+-----END CERTIFICATE-----`
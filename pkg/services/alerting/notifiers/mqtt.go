@@ -0,0 +1,161 @@
+package notifiers
+
+import (
+	"crypto/tls"
+	"errors"
+	"strings"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "mqtt",
+		Name:        "MQTT",
+		Description: "Publishes alert events to an MQTT topic",
+		Factory:     NewMqttNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">MQTT settings</h3>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Broker url</span>
+        <input type="text" required class="gf-form-input max-width-26" ng-model="ctrl.model.settings.brokerUrl" placeholder="tcp://localhost:1883"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Topic</span>
+        <input type="text" required class="gf-form-input max-width-26" ng-model="ctrl.model.settings.topic" placeholder="grafana/alerts"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">QoS</span>
+        <div class="gf-form-select-wrapper width-14">
+          <select class="gf-form-input" ng-model="ctrl.model.settings.qos" ng-options="t for t in [0, 1, 2]" ng-init="ctrl.model.settings.qos = ctrl.model.settings.qos || 1">
+          </select>
+        </div>
+      </div>
+      <div class="gf-form">
+        <gf-form-switch
+           class="gf-form"
+           label="Retain"
+           label-class="width-14"
+           checked="ctrl.model.settings.retain"
+           tooltip="The broker keeps the last event of the topic for new subscribers.">
+        </gf-form-switch>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Client ID</span>
+        <input type="text" class="gf-form-input max-width-26" ng-model="ctrl.model.settings.clientId" placeholder="random when empty"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Username</span>
+        <input type="text" class="gf-form-input max-width-14" ng-model="ctrl.model.settings.username"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Password</span>
+        <input type="password" class="gf-form-input max-width-14" ng-model="ctrl.model.settings.password"></input>
+      </div>
+    ` + messageBusTLSOptionsTemplate,
+	})
+}
+
+func NewMqttNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	brokerUrl := model.Settings.Get("brokerUrl").MustString()
+	if brokerUrl == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find broker url property in settings"}
+	}
+	topic := model.Settings.Get("topic").MustString()
+	if topic == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find topic property in settings"}
+	}
+	if strings.ContainsAny(topic, "#+") {
+		return nil, alerting.ValidationError{Reason: "Topic can not contain wildcards"}
+	}
+	qos := model.Settings.Get("qos").MustInt(1)
+	if qos < 0 || qos > 2 {
+		return nil, alerting.ValidationError{Reason: "QoS must be 0, 1 or 2"}
+	}
+
+	secure := strings.HasPrefix(brokerUrl, "ssl://") || strings.HasPrefix(brokerUrl, "tls://") || strings.HasPrefix(brokerUrl, "tcps://") || strings.HasPrefix(brokerUrl, "wss://")
+	tlsConfig, err := newMessageBusTLSConfig(model.Settings, secure)
+	if err != nil {
+		return nil, messageBusTLSValidationError(err)
+	}
+
+	return &MqttNotifier{
+		NotifierBase: NewNotifierBase(model),
+		BrokerUrl:    brokerUrl,
+		Topic:        topic,
+		QoS:          byte(qos),
+		Retain:       model.Settings.Get("retain").MustBool(false),
+		ClientId:     model.Settings.Get("clientId").MustString(),
+		User:         model.Settings.Get("username").MustString(),
+		Password:     model.Settings.Get("password").MustString(),
+		TLSConfig:    tlsConfig,
+		log:          log.New("alerting.notifier.mqtt"),
+	}, nil
+}
+
+type MqttNotifier struct {
+	NotifierBase
+	BrokerUrl string
+	Topic     string
+	QoS       byte
+	Retain    bool
+	ClientId  string
+	User      string
+	Password  string
+	TLSConfig *tls.Config
+	log       log.Logger
+}
+
+func (this *MqttNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Publishing alert event", "topic", this.Topic)
+
+	client := mqtt.NewClient(this.clientOptions())
+
+	token := client.Connect()
+	if !token.WaitTimeout(messageBusTimeout) {
+		return errors.New("Timeout connecting to MQTT broker")
+	}
+	if err := token.Error(); err != nil {
+		this.log.Error("Failed to connect to MQTT broker", "error", err)
+		return err
+	}
+	defer client.Disconnect(250)
+
+	token = client.Publish(this.Topic, this.QoS, this.Retain, alertEventMessage(&this.NotifierBase, evalContext))
+	if !token.WaitTimeout(messageBusTimeout) {
+		return errors.New("Timeout publishing alert event to MQTT broker")
+	}
+	if err := token.Error(); err != nil {
+		this.log.Error("Failed to publish alert event", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (this *MqttNotifier) clientOptions() *mqtt.ClientOptions {
+	clientId := this.ClientId
+	if clientId == "" {
+		clientId = "grafana-" + util.GenerateShortUid()
+	}
+
+	options := mqtt.NewClientOptions().
+		AddBroker(this.BrokerUrl).
+		SetClientID(clientId).
+		SetUsername(this.User).
+		SetPassword(this.Password).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectTimeout(messageBusTimeout).
+		SetWriteTimeout(messageBusTimeout)
+
+	if this.TLSConfig != nil {
+		options.SetTLSConfig(this.TLSConfig)
+	}
+
+	return options
+}
@@ -0,0 +1,129 @@
+package notifiers
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMqttNotifier(t *testing.T) {
+	Convey("MQTT notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "mqtt_testing",
+					Type:     "mqtt",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewMqttNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("topic with wildcard should return error", func() {
+				json := `
+				{
+					"brokerUrl": "tcp://localhost:1883",
+					"topic": "grafana/#"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "mqtt_testing",
+					Type:     "mqtt",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewMqttNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("invalid qos should return error", func() {
+				json := `
+				{
+					"brokerUrl": "tcp://localhost:1883",
+					"topic": "grafana/alerts",
+					"qos": 3
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "mqtt_testing",
+					Type:     "mqtt",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewMqttNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+					"brokerUrl": "ssl://localhost:8883",
+					"topic": "grafana/alerts",
+					"qos": 2,
+					"retain": true,
+					"username": "grafana",
+					"password": "secret"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "mqtt_testing",
+					Type:     "mqtt",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewMqttNotifier(model)
+				mqttNotifier := not.(*MqttNotifier)
+
+				So(err, ShouldBeNil)
+				So(mqttNotifier.Name, ShouldEqual, "mqtt_testing")
+				So(mqttNotifier.Type, ShouldEqual, "mqtt")
+				So(mqttNotifier.BrokerUrl, ShouldEqual, "ssl://localhost:8883")
+				So(mqttNotifier.Topic, ShouldEqual, "grafana/alerts")
+				So(mqttNotifier.QoS, ShouldEqual, 2)
+				So(mqttNotifier.Retain, ShouldBeTrue)
+				So(mqttNotifier.TLSConfig, ShouldNotBeNil)
+
+				Convey("uses a random client id", func() {
+					options := mqttNotifier.clientOptions()
+
+					So(options.ClientID, ShouldStartWith, "grafana-")
+					So(options.Username, ShouldEqual, "grafana")
+				})
+			})
+
+			Convey("defaults to qos 1", func() {
+				json := `
+				{
+					"brokerUrl": "tcp://localhost:1883",
+					"topic": "grafana/alerts",
+					"clientId": "grafana-ops"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "mqtt_testing",
+					Type:     "mqtt",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewMqttNotifier(model)
+				mqttNotifier := not.(*MqttNotifier)
+
+				So(err, ShouldBeNil)
+				So(mqttNotifier.QoS, ShouldEqual, 1)
+				So(mqttNotifier.TLSConfig, ShouldBeNil)
+				So(mqttNotifier.clientOptions().ClientID, ShouldEqual, "grafana-ops")
+			})
+		})
+	})
+}
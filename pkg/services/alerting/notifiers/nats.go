@@ -0,0 +1,123 @@
+package notifiers
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/nats-io/go-nats"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "nats",
+		Name:        "NATS",
+		Description: "Publishes alert events to a NATS subject",
+		Factory:     NewNatsNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">NATS settings</h3>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Servers</span>
+        <input type="text" required class="gf-form-input max-width-26" ng-model="ctrl.model.settings.url" placeholder="nats://localhost:4222" bs-tooltip="'Comma separated list of servers of the cluster'" data-placement="right"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Subject</span>
+        <input type="text" required class="gf-form-input max-width-26" ng-model="ctrl.model.settings.subject" placeholder="grafana.alerts"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Username</span>
+        <input type="text" class="gf-form-input max-width-14" ng-model="ctrl.model.settings.username"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Password</span>
+        <input type="password" class="gf-form-input max-width-14" ng-model="ctrl.model.settings.password"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Token</span>
+        <input type="password" class="gf-form-input max-width-26" ng-model="ctrl.model.settings.token"></input>
+      </div>
+    ` + messageBusTLSOptionsTemplate,
+	})
+}
+
+func NewNatsNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	url := model.Settings.Get("url").MustString()
+	if url == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find url property in settings"}
+	}
+	subject := model.Settings.Get("subject").MustString()
+	if subject == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find subject property in settings"}
+	}
+	if strings.ContainsAny(subject, "*> \t") {
+		return nil, alerting.ValidationError{Reason: "Subject can not contain wildcards or whitespace"}
+	}
+
+	tlsConfig, err := newMessageBusTLSConfig(model.Settings, strings.Contains(url, "tls://"))
+	if err != nil {
+		return nil, messageBusTLSValidationError(err)
+	}
+
+	return &NatsNotifier{
+		NotifierBase: NewNotifierBase(model),
+		Url:          url,
+		Subject:      subject,
+		User:         model.Settings.Get("username").MustString(),
+		Password:     model.Settings.Get("password").MustString(),
+		Token:        model.Settings.Get("token").MustString(),
+		TLSConfig:    tlsConfig,
+		log:          log.New("alerting.notifier.nats"),
+	}, nil
+}
+
+type NatsNotifier struct {
+	NotifierBase
+	Url       string
+	Subject   string
+	User      string
+	Password  string
+	Token     string
+	TLSConfig *tls.Config
+	log       log.Logger
+}
+
+func (this *NatsNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Publishing alert event", "subject", this.Subject)
+
+	conn, err := nats.Connect(this.Url, this.options()...)
+	if err != nil {
+		this.log.Error("Failed to connect to NATS server", "error", err)
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.Publish(this.Subject, alertEventMessage(&this.NotifierBase, evalContext)); err != nil {
+		this.log.Error("Failed to publish alert event", "error", err)
+		return err
+	}
+
+	// publishing is buffered, the round trip makes sure the server got the event
+	return conn.FlushTimeout(messageBusTimeout)
+}
+
+func (this *NatsNotifier) options() []nats.Option {
+	options := []nats.Option{
+		nats.Name("grafana"),
+		nats.Timeout(messageBusTimeout),
+		nats.NoReconnect(),
+	}
+
+	if this.User != "" {
+		options = append(options, nats.UserInfo(this.User, this.Password))
+	}
+	if this.Token != "" {
+		options = append(options, nats.Token(this.Token))
+	}
+	if this.TLSConfig != nil {
+		options = append(options, nats.Secure(this.TLSConfig))
+	}
+
+	return options
+}
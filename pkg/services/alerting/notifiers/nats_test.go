@@ -0,0 +1,135 @@
+package notifiers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNatsNotifier(t *testing.T) {
+	Convey("NATS notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "nats_testing",
+					Type:     "nats",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewNatsNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("subject with wildcard should return error", func() {
+				json := `
+				{
+					"url": "nats://localhost:4222",
+					"subject": "grafana.>"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "nats_testing",
+					Type:     "nats",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewNatsNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+					"url": "tls://nats1:4222,tls://nats2:4222",
+					"subject": "grafana.alerts",
+					"token": "secret"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "nats_testing",
+					Type:     "nats",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewNatsNotifier(model)
+				natsNotifier := not.(*NatsNotifier)
+
+				So(err, ShouldBeNil)
+				So(natsNotifier.Name, ShouldEqual, "nats_testing")
+				So(natsNotifier.Type, ShouldEqual, "nats")
+				So(natsNotifier.Url, ShouldEqual, "tls://nats1:4222,tls://nats2:4222")
+				So(natsNotifier.Subject, ShouldEqual, "grafana.alerts")
+				So(natsNotifier.Token, ShouldEqual, "secret")
+				So(natsNotifier.TLSConfig, ShouldNotBeNil)
+			})
+		})
+
+		Convey("Publishes the alert event", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			So(err, ShouldBeNil)
+			defer listener.Close()
+
+			published := make(chan string, 1)
+			go serveNatsStandIn(listener, published)
+
+			settingsJSON := simplejson.New()
+			settingsJSON.Set("url", "nats://"+listener.Addr().String())
+			settingsJSON.Set("subject", "grafana.alerts")
+			not, _ := NewNatsNotifier(&m.AlertNotification{Name: "nats_testing", Type: "nats", Settings: settingsJSON})
+
+			evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{Id: 1, Name: "cpu", State: m.AlertStateAlerting})
+
+			So(not.Notify(evalContext), ShouldBeNil)
+			So(<-published, ShouldEqual, "grafana.alerts "+string(alertEventMessage(&not.(*NatsNotifier).NotifierBase, evalContext)))
+		})
+	})
+}
+
+// serveNatsStandIn speaks enough of the NATS protocol for a client to
+// connect and publish a message, it sends the subject and payload of the
+// first message to published.
+func serveNatsStandIn(listener net.Listener, published chan<- string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n")
+
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		switch fields := strings.Fields(line); fields[0] {
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "PUB":
+			var size int
+			fmt.Sscan(fields[len(fields)-1], &size)
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			published <- fields[1] + " " + string(payload[:size])
+		}
+	}
+}
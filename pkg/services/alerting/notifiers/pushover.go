@@ -130,7 +130,7 @@ func (this *PushoverNotifier) Notify(evalContext *alerting.EvalContext) error {
 		return err
 	}
 
-	message := evalContext.Rule.Message
+	message := this.GetBody(evalContext)
 	for idx, evt := range evalContext.EvalMatches {
 		message += fmt.Sprintf("\n<b>%s</b>: %v", evt.Metric, evt.Value)
 		if idx > 4 {
@@ -161,7 +161,7 @@ func (this *PushoverNotifier) Notify(evalContext *alerting.EvalContext) error {
 	if this.Sound != "default" {
 		q.Add("sound", this.Sound)
 	}
-	q.Add("title", evalContext.GetNotificationTitle())
+	q.Add("title", this.GetTitle(evalContext))
 	q.Add("url", ruleUrl)
 	q.Add("url_title", "Show dashboard with alert")
 	q.Add("message", message)
@@ -0,0 +1,142 @@
+package notifiers
+
+import (
+	"encoding/json"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "rocketchat",
+		Name:        "Rocket.Chat",
+		Description: "Sends notifications to Rocket.Chat via incoming webhooks",
+		Factory:     NewRocketChatNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">Rocket.Chat settings</h3>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Url</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.url" placeholder="Rocket.Chat incoming webhook url"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Channel</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.channel"></input>
+        <info-popover mode="right-absolute">
+          Override the channel of the webhook, use #channel-name or @username
+        </info-popover>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Alias</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.alias"></input>
+        <info-popover mode="right-absolute">
+          Name shown instead of the webhook user
+        </info-popover>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Avatar URL</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.avatarUrl"></input>
+      </div>
+    `,
+	})
+}
+
+func NewRocketChatNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	url := model.Settings.Get("url").MustString()
+	if url == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find url property in settings"}
+	}
+
+	return &RocketChatNotifier{
+		NotifierBase: NewNotifierBase(model),
+		Url:          url,
+		Channel:      model.Settings.Get("channel").MustString(),
+		Alias:        model.Settings.Get("alias").MustString(),
+		AvatarUrl:    model.Settings.Get("avatarUrl").MustString(),
+		log:          log.New("alerting.notifier.rocketchat"),
+	}, nil
+}
+
+type RocketChatNotifier struct {
+	NotifierBase
+	Url       string
+	Channel   string
+	Alias     string
+	AvatarUrl string
+	log       log.Logger
+}
+
+func (this *RocketChatNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Executing rocket.chat notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+		return err
+	}
+
+	data, _ := json.Marshal(this.buildMessage(evalContext, ruleUrl))
+	cmd := &m.SendWebhookSync{Url: this.Url, Body: string(data)}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		this.log.Error("Failed to send rocket.chat notification", "error", err, "notification", this.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (this *RocketChatNotifier) buildMessage(evalContext *alerting.EvalContext, ruleUrl string) map[string]interface{} {
+	fields := make([]map[string]interface{}, 0)
+	for _, match := range evalContext.EvalMatches {
+		fields = append(fields, map[string]interface{}{
+			"title": match.Metric,
+			"value": match.Value.String(),
+			"short": true,
+		})
+	}
+
+	if evalContext.Error != nil {
+		fields = append(fields, map[string]interface{}{
+			"title": "Error message",
+			"value": evalContext.Error.Error(),
+			"short": false,
+		})
+	}
+
+	text := ""
+	if evalContext.Rule.State != m.AlertStateOK {
+		text = this.GetBody(evalContext)
+	}
+
+	attachment := map[string]interface{}{
+		"title":      this.GetTitle(evalContext),
+		"title_link": ruleUrl,
+		"text":       text,
+		"color":      evalContext.GetStateModel().Color,
+		"fields":     fields,
+	}
+
+	if this.NeedsImage() && evalContext.ImagePublicUrl != "" {
+		attachment["image_url"] = evalContext.ImagePublicUrl
+	}
+
+	message := map[string]interface{}{
+		"text":        this.GetTitle(evalContext),
+		"attachments": []map[string]interface{}{attachment},
+	}
+
+	if this.Channel != "" {
+		message["channel"] = this.Channel
+	}
+	if this.Alias != "" {
+		message["alias"] = this.Alias
+	}
+	if this.AvatarUrl != "" {
+		message["avatar"] = this.AvatarUrl
+	}
+
+	return message
+}
@@ -0,0 +1,68 @@
+package notifiers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRocketChatNotifier(t *testing.T) {
+	Convey("Rocket.Chat notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "rocketchat_testing",
+					Type:     "rocketchat",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewRocketChatNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+					"url": "https://rocket.example.com/hooks/abc/def",
+					"channel": "#alerts",
+					"alias": "Grafana"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "rocketchat_testing",
+					Type:     "rocketchat",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewRocketChatNotifier(model)
+				rocketChatNotifier := not.(*RocketChatNotifier)
+
+				So(err, ShouldBeNil)
+				So(rocketChatNotifier.Name, ShouldEqual, "rocketchat_testing")
+				So(rocketChatNotifier.Type, ShouldEqual, "rocketchat")
+				So(rocketChatNotifier.Url, ShouldEqual, "https://rocket.example.com/hooks/abc/def")
+
+				Convey("message should override channel and alias", func() {
+					evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{
+						Name:  "CPU high",
+						State: m.AlertStateAlerting,
+					})
+
+					message := rocketChatNotifier.buildMessage(evalContext, "http://grafana/d/abc")
+					So(message["channel"], ShouldEqual, "#alerts")
+					So(message["alias"], ShouldEqual, "Grafana")
+					So(message["text"], ShouldEqual, "[Alerting] CPU high")
+				})
+			})
+		})
+	})
+}
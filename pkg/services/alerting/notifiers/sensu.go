@@ -111,8 +111,8 @@ func (this *SensuNotifier) Notify(evalContext *alerting.EvalContext) error {
 		bodyJSON.Set("imageUrl", evalContext.ImagePublicUrl)
 	}
 
-	if evalContext.Rule.Message != "" {
-		bodyJSON.Set("output", evalContext.Rule.Message)
+	if message := this.GetBody(evalContext); message != "" {
+		bodyJSON.Set("output", message)
 	}
 
 	body, _ := bodyJSON.MarshalJSON()
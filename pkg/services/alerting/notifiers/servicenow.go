@@ -0,0 +1,195 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "servicenow",
+		Name:        "ServiceNow",
+		Description: "Creates a ServiceNow incident for every alert and resolves it once the alert is ok",
+		Factory:     NewServiceNowNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">ServiceNow settings</h3>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Url</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.url" placeholder="https://example.service-now.com"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Username</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.username"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Password</span>
+        <input type="password" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.password"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Table</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.table" placeholder="incident"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Assignment group</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.assignmentGroup"></input>
+        <info-popover mode="right-absolute">
+          Name or sys_id of the group new incidents are assigned to
+        </info-popover>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Resolved state</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.resolvedState" placeholder="6"></input>
+        <info-popover mode="right-absolute">
+          Value of the state field once the alert is ok, 6 is Resolved for incidents
+        </info-popover>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-10">Close code</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.closeCode" placeholder="Solved (Permanently)"></input>
+      </div>
+    `,
+	})
+}
+
+func NewServiceNowNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	serviceNowUrl := strings.TrimRight(model.Settings.Get("url").MustString(), "/")
+	if serviceNowUrl == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find url property in settings"}
+	}
+
+	username := model.Settings.Get("username").MustString()
+	password := model.Settings.Get("password").MustString()
+	if username == "" || password == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find username and password properties in settings"}
+	}
+
+	return &ServiceNowNotifier{
+		NotifierBase:    NewNotifierBase(model),
+		Url:             serviceNowUrl,
+		Username:        username,
+		Password:        password,
+		Table:           model.Settings.Get("table").MustString("incident"),
+		AssignmentGroup: model.Settings.Get("assignmentGroup").MustString(),
+		ResolvedState:   model.Settings.Get("resolvedState").MustString("6"),
+		CloseCode:       model.Settings.Get("closeCode").MustString("Solved (Permanently)"),
+		log:             log.New("alerting.notifier.servicenow"),
+	}, nil
+}
+
+type ServiceNowNotifier struct {
+	NotifierBase
+	Url             string
+	Username        string
+	Password        string
+	Table           string
+	AssignmentGroup string
+	ResolvedState   string
+	CloseCode       string
+	log             log.Logger
+}
+
+func (this *ServiceNowNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Executing servicenow notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+
+	if err := notifyTicket(evalContext, this.Id, this); err != nil {
+		this.log.Error("Failed to send servicenow notification", "error", err, "notification", this.Name)
+		return err
+	}
+
+	return nil
+}
+
+// createTicket creates the incident and returns its sys_id, which
+// identifies the record in the table API.
+func (this *ServiceNowNotifier) createTicket(evalContext *alerting.EvalContext) (string, error) {
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+		return "", err
+	}
+
+	incident := map[string]string{
+		"short_description": this.GetTitle(evalContext),
+		"description":       ticketDescription(evalContext, this.GetBody(evalContext), ruleUrl),
+	}
+
+	if this.AssignmentGroup != "" {
+		incident["assignment_group"] = this.AssignmentGroup
+	}
+
+	cmd := this.request("POST", "", incident)
+
+	// the sys_id is needed right away, so creating it is never queued for retry
+	if err := bus.DispatchCtx(m.WithNotificationDeliveryTarget(evalContext.Ctx, nil), cmd); err != nil {
+		return "", err
+	}
+
+	result := struct {
+		Result struct {
+			SysId  string `json:"sys_id"`
+			Number string `json:"number"`
+		} `json:"result"`
+	}{}
+	if err := json.Unmarshal(cmd.ResponseBody, &result); err != nil {
+		return "", err
+	}
+
+	if result.Result.SysId == "" {
+		return "", errors.New("ServiceNow did not return the sys_id of the created record")
+	}
+
+	this.log.Info("Created servicenow record", "number", result.Result.Number, "ruleId", evalContext.Rule.Id)
+	return result.Result.SysId, nil
+}
+
+func (this *ServiceNowNotifier) commentTicket(evalContext *alerting.EvalContext, sysId string) error {
+	update := map[string]string{
+		"work_notes": this.note(evalContext),
+	}
+
+	return bus.DispatchCtx(evalContext.Ctx, this.request("PATCH", "/"+url.PathEscape(sysId), update))
+}
+
+func (this *ServiceNowNotifier) resolveTicket(evalContext *alerting.EvalContext, sysId string) error {
+	update := map[string]string{
+		"state":       this.ResolvedState,
+		"close_code":  this.CloseCode,
+		"close_notes": this.note(evalContext),
+	}
+
+	return bus.DispatchCtx(evalContext.Ctx, this.request("PATCH", "/"+url.PathEscape(sysId), update))
+}
+
+func (this *ServiceNowNotifier) note(evalContext *alerting.EvalContext) string {
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+	}
+
+	message := ""
+	if evalContext.Rule.State != m.AlertStateOK {
+		message = this.GetBody(evalContext)
+	}
+
+	return this.GetTitle(evalContext) + "\n\n" + ticketDescription(evalContext, message, ruleUrl)
+}
+
+func (this *ServiceNowNotifier) request(method string, path string, body map[string]string) *m.SendWebhookSync {
+	data, _ := json.Marshal(body)
+
+	return &m.SendWebhookSync{
+		Url:        this.Url + "/api/now/table/" + url.PathEscape(this.Table) + path,
+		User:       this.Username,
+		Password:   this.Password,
+		Body:       string(data),
+		HttpMethod: method,
+		HttpHeader: map[string]string{"Accept": "application/json"},
+	}
+}
@@ -0,0 +1,95 @@
+package notifiers
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestServiceNowNotifier(t *testing.T) {
+	Convey("ServiceNow notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "servicenow_testing",
+					Type:     "servicenow",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewServiceNowNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+					"url": "https://example.service-now.com",
+					"username": "grafana",
+					"password": "secret"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "servicenow_testing",
+					Type:     "servicenow",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewServiceNowNotifier(model)
+				serviceNowNotifier := not.(*ServiceNowNotifier)
+
+				So(err, ShouldBeNil)
+				So(serviceNowNotifier.Name, ShouldEqual, "servicenow_testing")
+				So(serviceNowNotifier.Type, ShouldEqual, "servicenow")
+				So(serviceNowNotifier.Table, ShouldEqual, "incident")
+				So(serviceNowNotifier.ResolvedState, ShouldEqual, "6")
+				So(serviceNowNotifier.CloseCode, ShouldEqual, "Solved (Permanently)")
+			})
+		})
+
+		Convey("Given a servicenow stand-in", func() {
+			standIn := newTicketStandIn(func(r *ticketRequest) string {
+				if r.Method == "POST" {
+					return `{"result": {"sys_id": "a1b2c3", "number": "INC0010001"}}`
+				}
+				return `{"result": {}}`
+			})
+			defer standIn.close()
+
+			settingsJSON, _ := simplejson.NewJson([]byte(`{"url": "` + standIn.server.URL + `", "username": "grafana", "password": "secret", "assignmentGroup": "ops"}`))
+			not, _ := NewServiceNowNotifier(&m.AlertNotification{Id: 3, Name: "servicenow", Type: "servicenow", Settings: settingsJSON})
+
+			Convey("Alerting should create an incident", func() {
+				So(not.Notify(newTicketEvalContext(m.AlertStateAlerting)), ShouldBeNil)
+
+				So(len(standIn.requests), ShouldEqual, 1)
+				So(standIn.requests[0].Path, ShouldEqual, "/api/now/table/incident")
+				So(standIn.requests[0].Body, ShouldContainSubstring, `"assignment_group":"ops"`)
+				So(standIn.requests[0].Body, ShouldContainSubstring, `"short_description":"[Alerting] CPU high"`)
+				So(standIn.state.ExternalId, ShouldEqual, "a1b2c3")
+
+				Convey("Reminder should add work notes to the same incident", func() {
+					So(not.Notify(newTicketEvalContext(m.AlertStateAlerting)), ShouldBeNil)
+
+					So(standIn.requests[1].Method, ShouldEqual, "PATCH")
+					So(standIn.requests[1].Path, ShouldEqual, "/api/now/table/incident/a1b2c3")
+					So(standIn.requests[1].Body, ShouldContainSubstring, `"work_notes"`)
+				})
+
+				Convey("Ok should resolve the incident", func() {
+					So(not.Notify(newTicketEvalContext(m.AlertStateOK)), ShouldBeNil)
+
+					So(standIn.requests[1].Method, ShouldEqual, "PATCH")
+					So(standIn.requests[1].Body, ShouldContainSubstring, `"state":"6"`)
+					So(standIn.state.ExternalId, ShouldEqual, "")
+				})
+			})
+		})
+	})
+}
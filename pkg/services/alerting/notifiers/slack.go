@@ -2,11 +2,13 @@ package notifiers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
@@ -172,7 +174,7 @@ func (this *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 
 	message := this.Mention
 	if evalContext.Rule.State != m.AlertStateOK { //don't add message when going back to alert state ok.
-		message += " " + evalContext.Rule.Message
+		message += " " + this.GetBody(evalContext)
 	}
 	image_url := ""
 	// default to file.upload API method if a token is provided
@@ -183,9 +185,9 @@ func (this *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 	body := map[string]interface{}{
 		"attachments": []map[string]interface{}{
 			{
-				"fallback":    evalContext.GetNotificationTitle(),
+				"fallback":    this.GetTitle(evalContext),
 				"color":       evalContext.GetStateModel().Color,
-				"title":       evalContext.GetNotificationTitle(),
+				"title":       this.GetTitle(evalContext),
 				"title_link":  ruleUrl,
 				"text":        message,
 				"fields":      fields,
@@ -198,6 +200,55 @@ func (this *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 		"parse": "full", // to linkify urls, users and channels in alert message.
 	}
 
+	if err := this.post(evalContext.Ctx, body); err != nil {
+		return err
+	}
+	if this.Token != "" && this.UploadImage {
+		err = SlackFileUpload(evalContext, this.log, "https://slack.com/api/files.upload", this.Recipient, this.Token)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyDigest sends a group of alerts as a single message with an
+// attachment per alert.
+func (this *SlackNotifier) NotifyDigest(digest *alerting.NotificationDigest) error {
+	this.log.Info("Executing slack notification digest", "alerts", len(digest.Alerts), "notification", this.Name)
+
+	attachments := make([]map[string]interface{}, 0, len(digest.Alerts))
+	for _, evalContext := range digest.Alerts {
+		ruleUrl, err := evalContext.GetRuleUrl()
+		if err != nil {
+			this.log.Error("Failed get rule link", "error", err)
+		}
+
+		text := ""
+		if evalContext.Rule.State != m.AlertStateOK {
+			text = this.GetBody(evalContext)
+		}
+
+		attachments = append(attachments, map[string]interface{}{
+			"fallback":   this.GetTitle(evalContext),
+			"color":      evalContext.GetStateModel().Color,
+			"title":      this.GetTitle(evalContext),
+			"title_link": ruleUrl,
+			"text":       text,
+			"ts":         evalContext.EndTime.Unix(),
+		})
+	}
+
+	body := map[string]interface{}{
+		"text":        strings.TrimSpace(this.Mention + " " + digest.EvalContext().GetNotificationTitle()),
+		"attachments": attachments,
+		"parse":       "full",
+	}
+
+	return this.post(digest.Ctx, body)
+}
+
+func (this *SlackNotifier) post(ctx context.Context, body map[string]interface{}) error {
 	//recipient override
 	if this.Recipient != "" {
 		body["channel"] = this.Recipient
@@ -213,16 +264,10 @@ func (this *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 	}
 	data, _ := json.Marshal(&body)
 	cmd := &m.SendWebhookSync{Url: this.Url, Body: string(data)}
-	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+	if err := bus.DispatchCtx(ctx, cmd); err != nil {
 		this.log.Error("Failed to send slack notification", "error", err, "webhook", this.Name)
 		return err
 	}
-	if this.Token != "" && this.UploadImage {
-		err = SlackFileUpload(evalContext, this.log, "https://slack.com/api/files.upload", this.Recipient, this.Token)
-		if err != nil {
-			return err
-		}
-	}
 	return nil
 }
 
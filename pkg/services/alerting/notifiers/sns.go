@@ -0,0 +1,177 @@
+package notifiers
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+// snsMaxSubjectLength is the length limit of the subject of an SNS message.
+const snsMaxSubjectLength = 100
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "sns",
+		Name:        "AWS SNS",
+		Description: "Publishes alert events to an AWS SNS topic",
+		Factory:     NewSnsNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">AWS SNS settings</h3>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Region</span>
+        <input type="text" required class="gf-form-input max-width-14" ng-model="ctrl.model.settings.region" placeholder="us-east-1"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Topic ARN</span>
+        <input type="text" required class="gf-form-input max-width-26" ng-model="ctrl.model.settings.topicArn" placeholder="arn:aws:sns:us-east-1:123456789012:grafana-alerts"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Access key</span>
+        <input type="text" class="gf-form-input max-width-26" ng-model="ctrl.model.settings.accessKey" placeholder="default credentials when empty"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Secret key</span>
+        <input type="password" class="gf-form-input max-width-26" ng-model="ctrl.model.settings.secretKey"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Assume role ARN</span>
+        <input type="text" class="gf-form-input max-width-26" ng-model="ctrl.model.settings.assumeRoleArn" placeholder="optional"></input>
+      </div>
+      <div class="gf-form">
+        <span class="gf-form-label width-14">Endpoint</span>
+        <input type="text" class="gf-form-input max-width-26" ng-model="ctrl.model.settings.endpoint" placeholder="optional, e.g. a VPC endpoint"></input>
+      </div>
+    `,
+	})
+}
+
+func NewSnsNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	region := model.Settings.Get("region").MustString()
+	if region == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find region property in settings"}
+	}
+	topicArn := model.Settings.Get("topicArn").MustString()
+	if topicArn == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find topic ARN property in settings"}
+	}
+	if !strings.HasPrefix(topicArn, "arn:") {
+		return nil, alerting.ValidationError{Reason: "Topic ARN must start with arn:"}
+	}
+
+	accessKey := model.Settings.Get("accessKey").MustString()
+	secretKey := model.Settings.Get("secretKey").MustString()
+	if (accessKey == "") != (secretKey == "") {
+		return nil, alerting.ValidationError{Reason: "Access key and secret key must be set together"}
+	}
+
+	return &SnsNotifier{
+		NotifierBase:  NewNotifierBase(model),
+		Region:        region,
+		TopicArn:      topicArn,
+		AccessKey:     accessKey,
+		SecretKey:     secretKey,
+		AssumeRoleArn: model.Settings.Get("assumeRoleArn").MustString(),
+		Endpoint:      model.Settings.Get("endpoint").MustString(),
+		log:           log.New("alerting.notifier.sns"),
+	}, nil
+}
+
+type SnsNotifier struct {
+	NotifierBase
+	Region        string
+	TopicArn      string
+	AccessKey     string
+	SecretKey     string
+	AssumeRoleArn string
+	Endpoint      string
+	log           log.Logger
+}
+
+func (this *SnsNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Publishing alert event", "topicArn", this.TopicArn)
+
+	sess, err := this.session()
+	if err != nil {
+		return err
+	}
+
+	if _, err := sns.New(sess).PublishWithContext(evalContext.Ctx, this.publishInput(evalContext)); err != nil {
+		this.log.Error("Failed to publish alert event", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (this *SnsNotifier) session() (*session.Session, error) {
+	config := aws.NewConfig().WithRegion(this.Region)
+	if this.AccessKey != "" {
+		config = config.WithCredentials(credentials.NewStaticCredentials(this.AccessKey, this.SecretKey, ""))
+	}
+	if this.Endpoint != "" {
+		config = config.WithEndpoint(this.Endpoint)
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if this.AssumeRoleArn != "" {
+		sess = sess.Copy(aws.NewConfig().WithCredentials(stscreds.NewCredentials(sess, this.AssumeRoleArn)))
+	}
+
+	return sess, nil
+}
+
+// publishInput returns the message for the alert event. The state and rule
+// id are message attributes so subscriptions can filter on them.
+func (this *SnsNotifier) publishInput(evalContext *alerting.EvalContext) *sns.PublishInput {
+	input := &sns.PublishInput{
+		TopicArn: aws.String(this.TopicArn),
+		Message:  aws.String(string(alertEventMessage(&this.NotifierBase, evalContext))),
+		MessageAttributes: map[string]*sns.MessageAttributeValue{
+			"state": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(string(evalContext.Rule.State)),
+			},
+			"ruleId": {
+				DataType:    aws.String("Number"),
+				StringValue: aws.String(strconv.FormatInt(evalContext.Rule.Id, 10)),
+			},
+		},
+	}
+
+	if subject := snsSubject(this.GetTitle(evalContext)); subject != "" {
+		input.Subject = aws.String(subject)
+	}
+
+	return input
+}
+
+// snsSubject makes the title a valid subject, SNS only accepts printable
+// ASCII characters without line breaks.
+func snsSubject(title string) string {
+	subject := strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return ' '
+		}
+		return r
+	}, title)
+
+	subject = strings.TrimSpace(subject)
+	if len(subject) > snsMaxSubjectLength {
+		subject = subject[:snsMaxSubjectLength-3] + "..."
+	}
+
+	return subject
+}
@@ -0,0 +1,95 @@
+package notifiers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSnsNotifier(t *testing.T) {
+	Convey("SNS notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "sns_testing",
+					Type:     "sns",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewSnsNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("access key without secret key should return error", func() {
+				json := `
+				{
+					"region": "eu-west-1",
+					"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts",
+					"accessKey": "AKID"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "sns_testing",
+					Type:     "sns",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewSnsNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+					"region": "eu-west-1",
+					"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts",
+					"accessKey": "AKID",
+					"secretKey": "secret"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "sns_testing",
+					Type:     "sns",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewSnsNotifier(model)
+				snsNotifier := not.(*SnsNotifier)
+
+				So(err, ShouldBeNil)
+				So(snsNotifier.Name, ShouldEqual, "sns_testing")
+				So(snsNotifier.Type, ShouldEqual, "sns")
+				So(snsNotifier.Region, ShouldEqual, "eu-west-1")
+				So(snsNotifier.TopicArn, ShouldEqual, "arn:aws:sns:eu-west-1:123456789012:alerts")
+				So(snsNotifier.AccessKey, ShouldEqual, "AKID")
+
+				Convey("publishes the event with the state as attribute", func() {
+					evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{Id: 7, Name: "cpu", State: m.AlertStateAlerting})
+					input := snsNotifier.publishInput(evalContext)
+
+					So(*input.TopicArn, ShouldEqual, "arn:aws:sns:eu-west-1:123456789012:alerts")
+					So(*input.Subject, ShouldEqual, "[Alerting] cpu")
+					So(*input.Message, ShouldContainSubstring, `"ruleId":7`)
+					So(*input.MessageAttributes["state"].StringValue, ShouldEqual, "alerting")
+					So(*input.MessageAttributes["ruleId"].StringValue, ShouldEqual, "7")
+				})
+			})
+		})
+
+		Convey("Subject is printable ascii within the length limit", func() {
+			So(snsSubject("[Alerting] Disk\nfull ✓"), ShouldEqual, "[Alerting] Disk full")
+			So(snsSubject(strings.Repeat("a", 120)), ShouldHaveLength, snsMaxSubjectLength)
+		})
+	})
+}
@@ -75,7 +75,7 @@ func (this *TeamsNotifier) Notify(evalContext *alerting.EvalContext) error {
 
 	message := ""
 	if evalContext.Rule.State != m.AlertStateOK { //don't add message when going back to alert state ok.
-		message = evalContext.Rule.Message
+		message = this.GetBody(evalContext)
 	}
 
 	body := map[string]interface{}{
@@ -83,8 +83,8 @@ func (this *TeamsNotifier) Notify(evalContext *alerting.EvalContext) error {
 		"@context": "http://schema.org/extensions",
 		// summary MUST not be empty or the webhook request fails
 		// summary SHOULD contain some meaningful information, since it is used for mobile notifications
-		"summary":    evalContext.GetNotificationTitle(),
-		"title":      evalContext.GetNotificationTitle(),
+		"summary":    this.GetTitle(evalContext),
+		"title":      this.GetTitle(evalContext),
 		"themeColor": evalContext.GetStateModel().Color,
 		"sections": []map[string]interface{}{
 			{
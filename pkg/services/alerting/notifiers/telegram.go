@@ -99,7 +99,7 @@ func (this *TelegramNotifier) buildMessage(evalContext *alerting.EvalContext, se
 }
 
 func (this *TelegramNotifier) buildMessageLinkedImage(evalContext *alerting.EvalContext) *m.SendWebhookSync {
-	message := fmt.Sprintf("<b>%s</b>\nState: %s\nMessage: %s\n", evalContext.GetNotificationTitle(), evalContext.Rule.Name, evalContext.Rule.Message)
+	message := fmt.Sprintf("<b>%s</b>\nState: %s\nMessage: %s\n", this.GetTitle(evalContext), evalContext.Rule.Name, this.GetBody(evalContext))
 
 	ruleUrl, err := evalContext.GetRuleUrl()
 	if err == nil {
@@ -144,7 +144,7 @@ func (this *TelegramNotifier) buildMessageInlineImage(evalContext *alerting.Eval
 	}
 
 	metrics := generateMetricsMessage(evalContext)
-	message := generateImageCaption(evalContext, ruleUrl, metrics)
+	message := this.generateImageCaption(evalContext, ruleUrl, metrics)
 
 	cmd := this.generateTelegramCmd(message, "caption", "sendPhoto", func(w *multipart.Writer) {
 		fw, _ := w.CreateFormFile("photo", evalContext.ImageOnDiskPath)
@@ -193,11 +193,11 @@ func generateMetricsMessage(evalContext *alerting.EvalContext) string {
 	return metrics
 }
 
-func generateImageCaption(evalContext *alerting.EvalContext, ruleUrl string, metrics string) string {
-	message := evalContext.GetNotificationTitle()
+func (this *TelegramNotifier) generateImageCaption(evalContext *alerting.EvalContext, ruleUrl string, metrics string) string {
+	message := this.GetTitle(evalContext)
 
-	if len(evalContext.Rule.Message) > 0 {
-		message = fmt.Sprintf("%s\nMessage: %s", message, evalContext.Rule.Message)
+	if body := this.GetBody(evalContext); len(body) > 0 {
+		message = fmt.Sprintf("%s\nMessage: %s", message, body)
 	}
 
 	if len(message) > captionLengthLimit {
@@ -60,7 +60,7 @@ func TestTelegramNotifier(t *testing.T) {
 						State:   m.AlertStateOK,
 					})
 
-				caption := generateImageCaption(evalContext, "http://grafa.url/abcdef", "")
+				caption := (&TelegramNotifier{}).generateImageCaption(evalContext, "http://grafa.url/abcdef", "")
 				So(len(caption), ShouldBeLessThanOrEqualTo, 1024)
 				So(caption, ShouldContainSubstring, "Some kind of message.")
 				So(caption, ShouldContainSubstring, "[OK] This is an alarm")
@@ -77,7 +77,7 @@ func TestTelegramNotifier(t *testing.T) {
 							State:   m.AlertStateOK,
 						})
 
-					caption := generateImageCaption(evalContext,
+					caption := (&TelegramNotifier{}).generateImageCaption(evalContext,
 						"http://grafa.url/abcdefaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
 						"foo bar")
 					So(len(caption), ShouldBeLessThanOrEqualTo, 1024)
@@ -95,7 +95,7 @@ func TestTelegramNotifier(t *testing.T) {
 							State:   m.AlertStateOK,
 						})
 
-					caption := generateImageCaption(evalContext,
+					caption := (&TelegramNotifier{}).generateImageCaption(evalContext,
 						"http://grafa.url/foo",
 						"")
 					So(len(caption), ShouldBeLessThanOrEqualTo, 1024)
@@ -112,7 +112,7 @@ func TestTelegramNotifier(t *testing.T) {
 							State:   m.AlertStateOK,
 						})
 
-					caption := generateImageCaption(evalContext,
+					caption := (&TelegramNotifier{}).generateImageCaption(evalContext,
 						"http://grafa.url/foo",
 						"foo bar long song")
 					So(len(caption), ShouldBeLessThanOrEqualTo, 1024)
@@ -137,8 +137,8 @@ func (notifier *ThreemaNotifier) Notify(evalContext *alerting.EvalContext) error
 
 	// Build message
 	message := fmt.Sprintf("%s%s\n\n*State:* %s\n*Message:* %s\n",
-		stateEmoji, evalContext.GetNotificationTitle(),
-		evalContext.Rule.Name, evalContext.Rule.Message)
+		stateEmoji, notifier.GetTitle(evalContext),
+		evalContext.Rule.Name, notifier.GetBody(evalContext))
 	ruleURL, err := evalContext.GetRuleUrl()
 	if err == nil {
 		message = message + fmt.Sprintf("*URL:* %s\n", ruleURL)
@@ -0,0 +1,102 @@
+package notifiers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+// ticketClient creates and updates the tickets of a ticketing system.
+type ticketClient interface {
+	createTicket(evalContext *alerting.EvalContext) (string, error)
+	commentTicket(evalContext *alerting.EvalContext, ticketId string) error
+	resolveTicket(evalContext *alerting.EvalContext, ticketId string) error
+}
+
+// notifyTicket opens a ticket when an alert starts firing, comments on it
+// for reminders and resolves it once the alert is ok. The ticket id is kept
+// in the notification state of the alert, or of the alert instance for
+// multi-dimensional alerts. Test runs always open a new ticket.
+func notifyTicket(evalContext *alerting.EvalContext, notifierId int64, client ticketClient) error {
+	if evalContext.IsTestRun {
+		_, err := client.createTicket(evalContext)
+		return err
+	}
+
+	query := &m.GetOrCreateNotificationStateQuery{
+		OrgId:      evalContext.Rule.OrgId,
+		AlertId:    evalContext.Rule.Id,
+		NotifierId: notifierId,
+	}
+	if evalContext.Instance != nil {
+		query.InstanceId = evalContext.Instance.Id
+	}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, query); err != nil {
+		return err
+	}
+
+	state := query.Result
+
+	if evalContext.Rule.State == m.AlertStateOK {
+		if state.ExternalId == "" {
+			return nil
+		}
+
+		if err := client.resolveTicket(evalContext, state.ExternalId); err != nil {
+			return err
+		}
+
+		return setTicketId(evalContext.Ctx, state, "")
+	}
+
+	if state.ExternalId != "" {
+		return client.commentTicket(evalContext, state.ExternalId)
+	}
+
+	ticketId, err := client.createTicket(evalContext)
+	if err != nil {
+		return err
+	}
+
+	return setTicketId(evalContext.Ctx, state, ticketId)
+}
+
+func setTicketId(ctx context.Context, state *m.AlertNotificationState, ticketId string) error {
+	return bus.DispatchCtx(ctx, &m.SetAlertNotificationStateExternalIdCommand{Id: state.Id, ExternalId: ticketId})
+}
+
+// ticketDescription is the plain text description of a ticket.
+func ticketDescription(evalContext *alerting.EvalContext, message string, ruleUrl string) string {
+	lines := make([]string, 0)
+
+	if message != "" {
+		lines = append(lines, message, "")
+	}
+
+	for _, match := range evalContext.EvalMatches {
+		lines = append(lines, fmt.Sprintf("%s: %s", match.Metric, match.Value))
+	}
+
+	if evalContext.Error != nil {
+		lines = append(lines, "Error message: "+evalContext.Error.Error())
+	}
+
+	if evalContext.Instance != nil && len(evalContext.Instance.Labels) > 0 {
+		lines = append(lines, "Labels: "+evalContext.Instance.LabelsString())
+	}
+
+	if ruleUrl != "" {
+		lines = append(lines, "", ruleUrl)
+	}
+
+	if evalContext.ImagePublicUrl != "" {
+		lines = append(lines, evalContext.ImagePublicUrl)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
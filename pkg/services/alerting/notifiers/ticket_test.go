@@ -0,0 +1,93 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+type ticketRequest struct {
+	Method string
+	Path   string
+	Body   string
+	User   string
+}
+
+// ticketStandIn serves a ticketing system over http and keeps the
+// notification state of a single alert in memory.
+type ticketStandIn struct {
+	server   *httptest.Server
+	requests []*ticketRequest
+	state    *m.AlertNotificationState
+}
+
+func newTicketStandIn(respond func(r *ticketRequest) string) *ticketStandIn {
+	standIn := &ticketStandIn{
+		requests: make([]*ticketRequest, 0),
+		state:    &m.AlertNotificationState{Id: 1},
+	}
+
+	standIn.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		user, _, _ := r.BasicAuth()
+		request := &ticketRequest{Method: r.Method, Path: r.URL.Path, Body: string(body), User: user}
+		standIn.requests = append(standIn.requests, request)
+		fmt.Fprint(w, respond(request))
+	}))
+
+	bus.ClearBusHandlers()
+
+	bus.AddHandlerCtx("test", func(ctx context.Context, cmd *m.SendWebhookSync) error {
+		request, _ := http.NewRequest(cmd.HttpMethod, cmd.Url, bytes.NewReader([]byte(cmd.Body)))
+		request.SetBasicAuth(cmd.User, cmd.Password)
+
+		resp, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		cmd.ResponseBody, err = ioutil.ReadAll(resp.Body)
+		return err
+	})
+
+	bus.AddHandler("test", func(query *m.GetDashboardRefByIdQuery) error {
+		query.Result = &m.DashboardRef{Uid: "abc", Slug: "servers"}
+		return nil
+	})
+
+	bus.AddHandlerCtx("test", func(ctx context.Context, query *m.GetOrCreateNotificationStateQuery) error {
+		state := *standIn.state
+		query.Result = &state
+		return nil
+	})
+
+	bus.AddHandlerCtx("test", func(ctx context.Context, cmd *m.SetAlertNotificationStateExternalIdCommand) error {
+		standIn.state.ExternalId = cmd.ExternalId
+		return nil
+	})
+
+	return standIn
+}
+
+func (s *ticketStandIn) close() {
+	s.server.Close()
+	bus.ClearBusHandlers()
+}
+
+func newTicketEvalContext(state m.AlertStateType) *alerting.EvalContext {
+	return alerting.NewEvalContext(context.Background(), &alerting.Rule{
+		Id:      1,
+		OrgId:   1,
+		Name:    "CPU high",
+		Message: "Check the servers",
+		State:   state,
+	})
+}
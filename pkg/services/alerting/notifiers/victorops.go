@@ -97,7 +97,7 @@ func (this *VictoropsNotifier) Notify(evalContext *alerting.EvalContext) error {
 	bodyJSON.Set("entity_id", evalContext.Rule.Name)
 	bodyJSON.Set("timestamp", time.Now().Unix())
 	bodyJSON.Set("state_start_time", evalContext.StartTime.Unix())
-	bodyJSON.Set("state_message", evalContext.Rule.Message)
+	bodyJSON.Set("state_message", this.GetBody(evalContext))
 	bodyJSON.Set("monitoring_tool", "Grafana v"+setting.BuildVersion)
 	bodyJSON.Set("alert_url", ruleUrl)
 
@@ -0,0 +1,126 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+const webexDefaultApiUrl = "https://webexapis.com/v1/messages"
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "webex",
+		Name:        "Webex Teams",
+		Description: "Sends notifications to a Webex Teams space using a bot",
+		Factory:     NewWebexNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">Webex Teams settings</h3>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Bot token</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.botToken"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Room ID</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.roomId"></input>
+        <info-popover mode="right-absolute">
+          Id of the space to post to, the bot must be a member of it
+        </info-popover>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">API URL</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.apiUrl" placeholder="` + webexDefaultApiUrl + `"></input>
+      </div>
+    `,
+	})
+}
+
+func NewWebexNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	botToken := model.Settings.Get("botToken").MustString()
+	if botToken == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find botToken property in settings"}
+	}
+
+	roomId := model.Settings.Get("roomId").MustString()
+	if roomId == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find roomId property in settings"}
+	}
+
+	return &WebexNotifier{
+		NotifierBase: NewNotifierBase(model),
+		BotToken:     botToken,
+		RoomId:       roomId,
+		ApiUrl:       model.Settings.Get("apiUrl").MustString(webexDefaultApiUrl),
+		log:          log.New("alerting.notifier.webex"),
+	}, nil
+}
+
+type WebexNotifier struct {
+	NotifierBase
+	BotToken string
+	RoomId   string
+	ApiUrl   string
+	log      log.Logger
+}
+
+func (this *WebexNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Executing webex notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+		return err
+	}
+
+	data, _ := json.Marshal(this.buildMessage(evalContext, ruleUrl))
+	cmd := &m.SendWebhookSync{
+		Url:        this.ApiUrl,
+		Body:       string(data),
+		HttpMethod: "POST",
+		HttpHeader: map[string]string{"Authorization": "Bearer " + this.BotToken},
+	}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		this.log.Error("Failed to send webex notification", "error", err, "notification", this.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (this *WebexNotifier) buildMessage(evalContext *alerting.EvalContext, ruleUrl string) map[string]interface{} {
+	lines := []string{fmt.Sprintf("**%s**", this.GetTitle(evalContext))}
+
+	if message := this.GetBody(evalContext); message != "" && evalContext.Rule.State != m.AlertStateOK {
+		lines = append(lines, message)
+	}
+
+	for _, match := range evalContext.EvalMatches {
+		lines = append(lines, fmt.Sprintf("- %s: %s", match.Metric, match.Value))
+	}
+
+	if evalContext.Error != nil {
+		lines = append(lines, "Error message: "+evalContext.Error.Error())
+	}
+
+	if ruleUrl != "" {
+		lines = append(lines, fmt.Sprintf("[Open in Grafana](%s)", ruleUrl))
+	}
+
+	message := map[string]interface{}{
+		"roomId":   this.RoomId,
+		"markdown": strings.Join(lines, "\n\n"),
+	}
+
+	// webex downloads the image from the url and attaches it
+	if this.NeedsImage() && evalContext.ImagePublicUrl != "" {
+		message["files"] = []string{evalContext.ImagePublicUrl}
+	}
+
+	return message
+}
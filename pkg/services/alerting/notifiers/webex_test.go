@@ -0,0 +1,55 @@
+package notifiers
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWebexNotifier(t *testing.T) {
+	Convey("Webex notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "webex_testing",
+					Type:     "webex",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewWebexNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+					"botToken": "secret",
+					"roomId": "room1"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "webex_testing",
+					Type:     "webex",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewWebexNotifier(model)
+				webexNotifier := not.(*WebexNotifier)
+
+				So(err, ShouldBeNil)
+				So(webexNotifier.Name, ShouldEqual, "webex_testing")
+				So(webexNotifier.Type, ShouldEqual, "webex")
+				So(webexNotifier.BotToken, ShouldEqual, "secret")
+				So(webexNotifier.RoomId, ShouldEqual, "room1")
+				So(webexNotifier.ApiUrl, ShouldEqual, "https://webexapis.com/v1/messages")
+			})
+		})
+	})
+}
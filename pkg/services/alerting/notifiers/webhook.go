@@ -1,6 +1,8 @@
 package notifiers
 
 import (
+	"context"
+
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/log"
@@ -68,26 +70,28 @@ type WebhookNotifier struct {
 func (this *WebhookNotifier) Notify(evalContext *alerting.EvalContext) error {
 	this.log.Info("Sending webhook")
 
-	bodyJSON := simplejson.New()
-	bodyJSON.Set("title", evalContext.GetNotificationTitle())
-	bodyJSON.Set("ruleId", evalContext.Rule.Id)
-	bodyJSON.Set("ruleName", evalContext.Rule.Name)
-	bodyJSON.Set("state", evalContext.Rule.State)
-	bodyJSON.Set("evalMatches", evalContext.EvalMatches)
+	return this.send(evalContext.Ctx, this.alertJSON(evalContext))
+}
 
-	ruleUrl, err := evalContext.GetRuleUrl()
-	if err == nil {
-		bodyJSON.Set("ruleUrl", ruleUrl)
-	}
+// NotifyDigest sends a single request for a group of alerts, the alerts
+// are listed in the alerts property with the same fields as a single alert.
+func (this *WebhookNotifier) NotifyDigest(digest *alerting.NotificationDigest) error {
+	this.log.Info("Sending webhook digest", "alerts", len(digest.Alerts))
 
-	if evalContext.ImagePublicUrl != "" {
-		bodyJSON.Set("imageUrl", evalContext.ImagePublicUrl)
-	}
+	bodyJSON := this.alertJSON(digest.EvalContext())
+	bodyJSON.Del("ruleId")
 
-	if evalContext.Rule.Message != "" {
-		bodyJSON.Set("message", evalContext.Rule.Message)
+	alerts := make([]*simplejson.Json, 0, len(digest.Alerts))
+	for _, evalContext := range digest.Alerts {
+		alerts = append(alerts, this.alertJSON(evalContext))
 	}
+	bodyJSON.Set("groupBy", digest.GroupBy)
+	bodyJSON.Set("alerts", alerts)
 
+	return this.send(digest.Ctx, bodyJSON)
+}
+
+func (this *WebhookNotifier) send(ctx context.Context, bodyJSON *simplejson.Json) error {
 	body, _ := bodyJSON.MarshalJSON()
 
 	cmd := &m.SendWebhookSync{
@@ -98,10 +102,41 @@ func (this *WebhookNotifier) Notify(evalContext *alerting.EvalContext) error {
 		HttpMethod: this.HttpMethod,
 	}
 
-	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+	if err := bus.DispatchCtx(ctx, cmd); err != nil {
 		this.log.Error("Failed to send webhook", "error", err, "webhook", this.Name)
 		return err
 	}
 
 	return nil
 }
+
+func (this *WebhookNotifier) alertJSON(evalContext *alerting.EvalContext) *simplejson.Json {
+	return alertEventJSON(&this.NotifierBase, evalContext)
+}
+
+// alertEventJSON builds the event describing the state of an alert. It is
+// the body of the webhook notifier and the message published by the message
+// bus notifiers.
+func alertEventJSON(n *NotifierBase, evalContext *alerting.EvalContext) *simplejson.Json {
+	bodyJSON := simplejson.New()
+	bodyJSON.Set("title", n.GetTitle(evalContext))
+	bodyJSON.Set("ruleId", evalContext.Rule.Id)
+	bodyJSON.Set("ruleName", evalContext.Rule.Name)
+	bodyJSON.Set("state", evalContext.Rule.State)
+	bodyJSON.Set("evalMatches", evalContext.EvalMatches)
+
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err == nil {
+		bodyJSON.Set("ruleUrl", ruleUrl)
+	}
+
+	if evalContext.ImagePublicUrl != "" {
+		bodyJSON.Set("imageUrl", evalContext.ImagePublicUrl)
+	}
+
+	if message := n.GetBody(evalContext); message != "" {
+		bodyJSON.Set("message", message)
+	}
+
+	return bodyJSON
+}
@@ -0,0 +1,151 @@
+package notifiers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "zulip",
+		Name:        "Zulip",
+		Description: "Sends notifications to a Zulip stream using an incoming webhook bot",
+		Factory:     NewZulipNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">Zulip settings</h3>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Url</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.url" placeholder="https://example.zulipchat.com"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Bot email</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.email" placeholder="grafana-bot@example.zulipchat.com"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">API key</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.apiKey"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Stream</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.stream"></input>
+      </div>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-8">Topic</span>
+        <input type="text" class="gf-form-input max-width-30" ng-model="ctrl.model.settings.topic"></input>
+        <info-popover mode="right-absolute">
+          Defaults to the name of the alert rule, so every alert gets its own topic
+        </info-popover>
+      </div>
+    `,
+	})
+}
+
+func NewZulipNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	zulipUrl := strings.TrimRight(model.Settings.Get("url").MustString(), "/")
+	if zulipUrl == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find url property in settings"}
+	}
+
+	email := model.Settings.Get("email").MustString()
+	if email == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find email property in settings"}
+	}
+
+	apiKey := model.Settings.Get("apiKey").MustString()
+	if apiKey == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find apiKey property in settings"}
+	}
+
+	stream := model.Settings.Get("stream").MustString()
+	if stream == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find stream property in settings"}
+	}
+
+	return &ZulipNotifier{
+		NotifierBase: NewNotifierBase(model),
+		Url:          zulipUrl,
+		Email:        email,
+		ApiKey:       apiKey,
+		Stream:       stream,
+		Topic:        model.Settings.Get("topic").MustString(),
+		log:          log.New("alerting.notifier.zulip"),
+	}, nil
+}
+
+type ZulipNotifier struct {
+	NotifierBase
+	Url    string
+	Email  string
+	ApiKey string
+	Stream string
+	Topic  string
+	log    log.Logger
+}
+
+func (this *ZulipNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Executing zulip notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+		return err
+	}
+
+	cmd := &m.SendWebhookSync{
+		Url:         this.Url + "/api/v1/messages",
+		User:        this.Email,
+		Password:    this.ApiKey,
+		Body:        this.buildMessage(evalContext, ruleUrl).Encode(),
+		HttpMethod:  "POST",
+		ContentType: "application/x-www-form-urlencoded",
+	}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		this.log.Error("Failed to send zulip notification", "error", err, "notification", this.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (this *ZulipNotifier) buildMessage(evalContext *alerting.EvalContext, ruleUrl string) url.Values {
+	topic := this.Topic
+	if topic == "" {
+		topic = evalContext.Rule.Name
+	}
+
+	lines := []string{fmt.Sprintf("**%s**", this.GetTitle(evalContext))}
+
+	if message := this.GetBody(evalContext); message != "" && evalContext.Rule.State != m.AlertStateOK {
+		lines = append(lines, message)
+	}
+
+	for _, match := range evalContext.EvalMatches {
+		lines = append(lines, fmt.Sprintf("* %s: %s", match.Metric, match.Value))
+	}
+
+	if evalContext.Error != nil {
+		lines = append(lines, "Error message: "+evalContext.Error.Error())
+	}
+
+	if ruleUrl != "" {
+		lines = append(lines, fmt.Sprintf("[Open in Grafana](%s)", ruleUrl))
+	}
+
+	if this.NeedsImage() && evalContext.ImagePublicUrl != "" {
+		lines = append(lines, fmt.Sprintf("[Graph](%s)", evalContext.ImagePublicUrl))
+	}
+
+	values := url.Values{}
+	values.Set("type", "stream")
+	values.Set("to", this.Stream)
+	values.Set("topic", topic)
+	values.Set("content", strings.Join(lines, "\n"))
+	return values
+}
@@ -0,0 +1,91 @@
+package notifiers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestZulipNotifier(t *testing.T) {
+	Convey("Zulip notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "zulip_testing",
+					Type:     "zulip",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewZulipNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("missing stream should return error", func() {
+				json := `
+				{
+					"url": "https://example.zulipchat.com",
+					"email": "bot@example.zulipchat.com",
+					"apiKey": "secret"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "zulip_testing",
+					Type:     "zulip",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewZulipNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+					"url": "https://example.zulipchat.com/",
+					"email": "bot@example.zulipchat.com",
+					"apiKey": "secret",
+					"stream": "alerts"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "zulip_testing",
+					Type:     "zulip",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewZulipNotifier(model)
+				zulipNotifier := not.(*ZulipNotifier)
+
+				So(err, ShouldBeNil)
+				So(zulipNotifier.Name, ShouldEqual, "zulip_testing")
+				So(zulipNotifier.Type, ShouldEqual, "zulip")
+				So(zulipNotifier.Url, ShouldEqual, "https://example.zulipchat.com")
+				So(zulipNotifier.Stream, ShouldEqual, "alerts")
+
+				Convey("topic should default to the rule name", func() {
+					evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{
+						Name:    "CPU high",
+						Message: "Check the servers",
+						State:   m.AlertStateAlerting,
+					})
+
+					values := zulipNotifier.buildMessage(evalContext, "http://grafana/d/abc")
+					So(values.Get("type"), ShouldEqual, "stream")
+					So(values.Get("to"), ShouldEqual, "alerts")
+					So(values.Get("topic"), ShouldEqual, "CPU high")
+					So(values.Get("content"), ShouldEqual, "**[Alerting] CPU high**\nCheck the servers\n[Open in Grafana](http://grafana/d/abc)")
+				})
+			})
+		})
+	})
+}
@@ -1,6 +1,9 @@
 package alerting
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -8,34 +11,57 @@ import (
 	"github.com/grafana/grafana/pkg/log"
 	"github.com/grafana/grafana/pkg/metrics"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	heartbeatInterval = time.Second * 10
+
+	// a server that missed this many heartbeats is considered dead and its
+	// rules are taken over by the remaining servers
+	heartbeatTimeout = heartbeatInterval * 3
 )
 
 type RuleReader interface {
 	Fetch() []*Rule
+	Run(ctx context.Context) error
 }
 
 type DefaultRuleReader struct {
 	sync.RWMutex
-	//serverID       string
-	serverPosition int
-	clusterSize    int
-	log            log.Logger
+	serverID string
+	servers  []string
+	ring     *hashRing
+	log      log.Logger
 }
 
 func NewRuleReader() *DefaultRuleReader {
-	ruleReader := &DefaultRuleReader{
-		log: log.New("alerting.ruleReader"),
+	return &DefaultRuleReader{
+		serverID: fmt.Sprintf("%s-%s", setting.InstanceName, util.GetRandomString(8)),
+		log:      log.New("alerting.ruleReader"),
 	}
-
-	go ruleReader.initReader()
-	return ruleReader
 }
 
-func (arr *DefaultRuleReader) initReader() {
-	heartbeat := time.NewTicker(time.Second * 10)
+// Run sends heartbeats for this server and keeps track of the other live
+// servers until the context is cancelled.
+func (arr *DefaultRuleReader) Run(ctx context.Context) error {
+	arr.heartbeat()
 
-	for range heartbeat.C {
-		arr.heartbeat()
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// let the other servers take over right away
+			if err := bus.Dispatch(&m.DeleteAlertHeartbeatCommand{ServerId: arr.serverID}); err != nil {
+				arr.log.Error("Failed to remove alert heartbeat", "error", err)
+			}
+			return ctx.Err()
+		case <-heartbeat.C:
+			arr.heartbeat()
+		}
 	}
 }
 
@@ -49,6 +75,10 @@ func (arr *DefaultRuleReader) Fetch() []*Rule {
 
 	res := make([]*Rule, 0)
 	for _, ruleDef := range cmd.Result {
+		if !arr.isResponsibleFor(ruleDef.Id) {
+			continue
+		}
+
 		if model, err := NewRuleFromDBAlert(ruleDef); err != nil {
 			arr.log.Error("Could not build alert model for rule", "ruleId", ruleDef.Id, "error", err)
 		} else {
@@ -60,7 +90,73 @@ func (arr *DefaultRuleReader) Fetch() []*Rule {
 	return res
 }
 
+// isResponsibleFor reports whether this server evaluates the rule. Every
+// rule is evaluated while this server does not know its own membership,
+// e.g. when the database is unreachable, so that no rule is left behind.
+func (arr *DefaultRuleReader) isResponsibleFor(ruleId int64) bool {
+	arr.RLock()
+	defer arr.RUnlock()
+
+	if arr.ring == nil {
+		return true
+	}
+
+	return arr.ring.owner(ruleId) == arr.serverID
+}
+
 func (arr *DefaultRuleReader) heartbeat() {
-	arr.clusterSize = 1
-	arr.serverPosition = 1
+	if err := bus.Dispatch(&m.AlertHeartbeatCommand{ServerId: arr.serverID}); err != nil {
+		arr.log.Error("Failed to send alert heartbeat", "error", err)
+		arr.updateServers(nil)
+		return
+	}
+
+	query := &m.GetActiveAlertServersQuery{Since: time.Now().Add(-heartbeatTimeout)}
+	if err := bus.Dispatch(query); err != nil {
+		arr.log.Error("Failed to get alert servers", "error", err)
+		arr.updateServers(nil)
+		return
+	}
+
+	arr.updateServers(query.Result)
+}
+
+// updateServers rebuilds the hash ring when the cluster membership changed.
+// Without a known membership that includes this server the ring is
+// dropped and all rules are evaluated locally.
+func (arr *DefaultRuleReader) updateServers(servers []string) {
+	if !containsServer(servers, arr.serverID) {
+		servers = nil
+	}
+
+	metrics.M_Alerting_Cluster_Servers.Set(float64(len(servers)))
+
+	arr.Lock()
+	defer arr.Unlock()
+
+	if arr.ring != nil && strings.Join(arr.servers, ",") == strings.Join(servers, ",") {
+		return
+	}
+
+	if servers == nil {
+		if arr.ring != nil {
+			arr.log.Warn("Alert cluster membership unknown, evaluating all rules")
+		}
+		arr.servers = nil
+		arr.ring = nil
+		return
+	}
+
+	arr.log.Info("Alert cluster membership changed", "serverId", arr.serverID, "servers", len(servers))
+	arr.servers = servers
+	arr.ring = newHashRing(servers)
+}
+
+func containsServer(servers []string, serverID string) bool {
+	for _, server := range servers {
+		if server == serverID {
+			return true
+		}
+	}
+	return false
 }
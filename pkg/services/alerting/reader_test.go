@@ -0,0 +1,68 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRuleReaderSharding(t *testing.T) {
+	Convey("Rule reader sharding", t, func() {
+		servers := []string{"server-a", "server-b", "server-c"}
+
+		bus.ClearBusHandlers()
+		bus.AddHandler("test", func(cmd *m.AlertHeartbeatCommand) error { return nil })
+		bus.AddHandler("test", func(query *m.GetActiveAlertServersQuery) error {
+			query.Result = servers
+			return nil
+		})
+
+		readers := make([]*DefaultRuleReader, 0)
+		for _, server := range servers {
+			reader := NewRuleReader()
+			reader.serverID = server
+			readers = append(readers, reader)
+		}
+
+		Convey("Should evaluate all rules before the membership is known", func() {
+			So(readers[0].isResponsibleFor(1), ShouldBeTrue)
+			So(readers[1].isResponsibleFor(1), ShouldBeTrue)
+		})
+
+		Convey("Should evaluate every rule on exactly one server", func() {
+			for _, reader := range readers {
+				reader.heartbeat()
+			}
+
+			for id := int64(1); id <= 100; id++ {
+				owners := 0
+				for _, reader := range readers {
+					if reader.isResponsibleFor(id) {
+						owners++
+					}
+				}
+				So(owners, ShouldEqual, 1)
+			}
+
+			Convey("Should take over the rules of a dead server", func() {
+				servers = []string{"server-a", "server-c"}
+				readers[0].heartbeat()
+				readers[2].heartbeat()
+
+				for id := int64(1); id <= 100; id++ {
+					So(readers[0].isResponsibleFor(id) != readers[2].isResponsibleFor(id), ShouldBeTrue)
+				}
+			})
+
+			Convey("Should evaluate all rules when its own heartbeat is missing", func() {
+				servers = []string{"server-b", "server-c"}
+				readers[0].heartbeat()
+
+				So(readers[0].isResponsibleFor(1), ShouldBeTrue)
+				So(readers[0].isResponsibleFor(2), ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -0,0 +1,216 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/annotations"
+)
+
+// ReceivedAlert is an alert reported by an external system, e.g. a
+// Prometheus Alertmanager.
+type ReceivedAlert struct {
+	Title       string
+	Message     string
+	State       m.AlertStateType
+	Labels      map[string]string
+	EvalMatches []*EvalMatch
+	Time        time.Time
+}
+
+// ReceiveAlertsCommand records external alerts as alert instances and
+// annotations of a dashboard and notifies the given channels, or the
+// default channels when none are given, of their state changes.
+type ReceiveAlertsCommand struct {
+	OrgId        int64
+	DashboardId  int64
+	PanelId      int64
+	NotifierUids []string
+	Alerts       []*ReceivedAlert
+
+	Changed int
+}
+
+// alertReceiver handles received alerts. External alerts are stored as
+// instances without an alert rule, i.e. with alert id 0.
+type alertReceiver struct {
+	notifier NotificationService
+	log      log.Logger
+}
+
+func newAlertReceiver(notifier NotificationService) *alertReceiver {
+	return &alertReceiver{
+		notifier: notifier,
+		log:      log.New("alerting.receiver"),
+	}
+}
+
+func (r *alertReceiver) handleReceiveAlertsCommand(cmd *ReceiveAlertsCommand) error {
+	notifierIds := make([]int64, 0, len(cmd.NotifierUids))
+	for _, uid := range cmd.NotifierUids {
+		query := &m.GetAlertNotificationsWithUidQuery{OrgId: cmd.OrgId, Uid: uid}
+		if err := bus.Dispatch(query); err != nil {
+			return err
+		}
+		if query.Result == nil {
+			return ValidationError{Reason: fmt.Sprintf("Notification channel %s not found", uid)}
+		}
+		notifierIds = append(notifierIds, query.Result.Id)
+	}
+
+	for _, alert := range cmd.Alerts {
+		if alert.State != m.AlertStateAlerting && alert.State != m.AlertStateOK {
+			return ValidationError{Reason: fmt.Sprintf("Invalid state %s for received alert, must be alerting or ok", alert.State)}
+		}
+	}
+
+	for _, alert := range cmd.Alerts {
+		changed, err := r.receive(cmd, notifierIds, alert)
+		if err != nil {
+			return err
+		}
+		if changed {
+			cmd.Changed++
+		}
+	}
+
+	return nil
+}
+
+// receive records the state of a received alert and notifies when it
+// changed. Repeated reports of the same state are ignored.
+func (r *alertReceiver) receive(cmd *ReceiveAlertsCommand, notifierIds []int64, alert *ReceivedAlert) (bool, error) {
+	labels := make(map[string]string)
+	for k, v := range alert.Labels {
+		labels[k] = v
+	}
+	if _, exist := labels["alertname"]; !exist {
+		labels["alertname"] = alert.Title
+	}
+
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+
+	// title and panel are kept to rebuild the alert for later escalation steps
+	evalData := simplejson.New()
+	evalData.Set("title", alert.Title)
+	if cmd.PanelId > 0 {
+		evalData.Set("panelId", cmd.PanelId)
+	}
+	if len(alert.EvalMatches) > 0 {
+		evalData.Set("evalMatches", simplejson.NewFromAny(alert.EvalMatches))
+	}
+	if alert.Message != "" {
+		evalData.Set("message", alert.Message)
+	}
+
+	prevState := m.AlertStateUnknown
+	instancesQuery := &m.GetAlertInstancesQuery{
+		OrgId:       cmd.OrgId,
+		AlertId:     0,
+		DashboardId: cmd.DashboardId,
+		LabelsHash:  m.AlertInstanceLabelsHash(labels),
+	}
+	if err := bus.Dispatch(instancesQuery); err != nil {
+		return false, err
+	}
+	if len(instancesQuery.Result) > 0 {
+		prevState = instancesQuery.Result[0].State
+	}
+
+	// a resolved alert that was never seen has nothing to resolve
+	if prevState == m.AlertStateUnknown && alert.State == m.AlertStateOK {
+		return false, nil
+	}
+
+	stateCmd := &m.SetAlertInstanceStateCommand{
+		OrgId:       cmd.OrgId,
+		AlertId:     0,
+		DashboardId: cmd.DashboardId,
+		Labels:      labels,
+		State:       alert.State,
+		EvalData:    evalData,
+	}
+	if err := bus.Dispatch(stateCmd); err != nil {
+		if err == m.ErrRequiresNewState {
+			return false, nil
+		}
+		return false, err
+	}
+
+	item := annotations.Item{
+		OrgId:       cmd.OrgId,
+		DashboardId: cmd.DashboardId,
+		PanelId:     cmd.PanelId,
+		Text:        alert.Title,
+		NewState:    string(alert.State),
+		PrevState:   string(prevState),
+		Epoch:       alert.Time.UnixNano() / int64(time.Millisecond),
+		Tags:        labelTags(labels),
+		Data:        evalData,
+	}
+	if alert.Message != "" {
+		item.Text = alert.Title + " - " + alert.Message
+	}
+	if err := annotations.GetRepository().Save(&item); err != nil {
+		r.log.Error("Failed to save annotation for received alert", "error", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), receiveTimeout)
+	defer cancel()
+
+	rule := &Rule{
+		OrgId:         cmd.OrgId,
+		DashboardId:   cmd.DashboardId,
+		PanelId:       cmd.PanelId,
+		Name:          alert.Title,
+		Message:       alert.Message,
+		State:         alert.State,
+		Notifications: notifierIds,
+	}
+
+	evalContext := NewEvalContext(ctx, rule)
+	instance := &Instance{
+		Id:              stateCmd.Result.Id,
+		Labels:          labels,
+		Firing:          alert.State == m.AlertStateAlerting,
+		EvalMatches:     alert.EvalMatches,
+		State:           alert.State,
+		PrevState:       prevState,
+		LastStateChange: alert.Time,
+		StateChanges:    stateCmd.Result.StateChanges,
+	}
+	if instance.EvalMatches == nil {
+		instance.EvalMatches = make([]*EvalMatch, 0)
+	}
+
+	if err := r.notifier.SendIfNeeded(evalContext.ForInstance(instance)); err != nil {
+		r.log.Error("Failed to send notifications for received alert", "alertname", labels["alertname"], "error", err)
+	}
+
+	// resolved external alerts are forgotten, they come back as new ones
+	if alert.State == m.AlertStateOK {
+		deleteCmd := &m.DeleteAlertInstanceCommand{Id: instance.Id, AlertId: 0, OrgId: cmd.OrgId}
+		if err := bus.Dispatch(deleteCmd); err != nil {
+			r.log.Error("Failed to delete resolved received alert", "error", err)
+		}
+	}
+
+	return true, nil
+}
+
+func labelTags(labels map[string]string) []string {
+	tags := make([]string, 0, len(labels))
+	for k, v := range labels {
+		tags = append(tags, k+":"+v)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,143 @@
+package alerting
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeReceiverNotificationService struct {
+	sent []*EvalContext
+}
+
+func (s *fakeReceiverNotificationService) SendIfNeeded(evalContext *EvalContext) error {
+	s.sent = append(s.sent, evalContext)
+	return nil
+}
+
+type fakeReceiverAnnotationsRepo struct {
+	saved []*annotations.Item
+}
+
+func (repo *fakeReceiverAnnotationsRepo) Save(item *annotations.Item) error {
+	repo.saved = append(repo.saved, item)
+	return nil
+}
+func (repo *fakeReceiverAnnotationsRepo) Update(item *annotations.Item) error { return nil }
+func (repo *fakeReceiverAnnotationsRepo) Delete(params *annotations.DeleteParams) error {
+	return nil
+}
+func (repo *fakeReceiverAnnotationsRepo) Find(query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	return nil, nil
+}
+
+func TestAlertReceiver(t *testing.T) {
+	Convey("Receiving external alerts", t, func() {
+		stored := make(map[string]*m.AlertInstance)
+		deleted := 0
+
+		bus.ClearBusHandlers()
+		bus.AddHandler("test", func(query *m.GetAlertNotificationsWithUidQuery) error {
+			if query.Uid == "ops" {
+				query.Result = &m.AlertNotification{Id: 4, Uid: "ops"}
+			}
+			return nil
+		})
+		bus.AddHandler("test", func(query *m.GetAlertInstancesQuery) error {
+			for _, instance := range stored {
+				if instance.DashboardId == query.DashboardId && instance.LabelsHash == query.LabelsHash {
+					query.Result = append(query.Result, instance)
+				}
+			}
+			return nil
+		})
+		bus.AddHandler("test", func(cmd *m.SetAlertInstanceStateCommand) error {
+			hash := m.AlertInstanceLabelsHash(cmd.Labels)
+			key := fmt.Sprintf("%d-%s", cmd.DashboardId, hash)
+			instance, exist := stored[key]
+			if !exist {
+				instance = &m.AlertInstance{Id: int64(len(stored) + 1), DashboardId: cmd.DashboardId, LabelsHash: hash, Labels: cmd.Labels}
+				stored[key] = instance
+			} else if instance.State == cmd.State {
+				return m.ErrRequiresNewState
+			}
+			instance.State = cmd.State
+			instance.StateChanges++
+			cmd.Result = *instance
+			return nil
+		})
+		bus.AddHandler("test", func(cmd *m.DeleteAlertInstanceCommand) error {
+			deleted++
+			return nil
+		})
+
+		repo := &fakeReceiverAnnotationsRepo{}
+		annotations.SetRepository(repo)
+
+		notifier := &fakeReceiverNotificationService{}
+		receiver := newAlertReceiver(notifier)
+
+		firing := &ReceivedAlert{
+			Title:  "HighLatency",
+			State:  m.AlertStateAlerting,
+			Labels: map[string]string{"service": "api"},
+			Time:   time.Unix(1500000000, 0),
+		}
+
+		cmd := &ReceiveAlertsCommand{OrgId: 1, DashboardId: 2, NotifierUids: []string{"ops"}, Alerts: []*ReceivedAlert{firing}}
+		So(receiver.handleReceiveAlertsCommand(cmd), ShouldBeNil)
+
+		Convey("Should record instance and annotation and notify", func() {
+			So(cmd.Changed, ShouldEqual, 1)
+			So(len(repo.saved), ShouldEqual, 1)
+			So(repo.saved[0].DashboardId, ShouldEqual, 2)
+			So(repo.saved[0].Tags, ShouldResemble, []string{"alertname:HighLatency", "service:api"})
+
+			So(len(notifier.sent), ShouldEqual, 1)
+			sent := notifier.sent[0]
+			So(sent.Rule.Notifications, ShouldResemble, []int64{4})
+			So(sent.Rule.Name, ShouldEqual, "HighLatency")
+			So(sent.PrevAlertState, ShouldEqual, m.AlertStateUnknown)
+			So(sent.Instance.Labels["alertname"], ShouldEqual, "HighLatency")
+		})
+
+		Convey("Should ignore repeated reports of the same state", func() {
+			So(receiver.handleReceiveAlertsCommand(cmd), ShouldBeNil)
+			So(cmd.Changed, ShouldEqual, 1)
+			So(len(notifier.sent), ShouldEqual, 1)
+		})
+
+		Convey("Should notify and forget resolved alerts", func() {
+			resolved := *firing
+			resolved.State = m.AlertStateOK
+
+			cmd := &ReceiveAlertsCommand{OrgId: 1, DashboardId: 2, Alerts: []*ReceivedAlert{&resolved}}
+			So(receiver.handleReceiveAlertsCommand(cmd), ShouldBeNil)
+			So(cmd.Changed, ShouldEqual, 1)
+			So(notifier.sent[1].PrevAlertState, ShouldEqual, m.AlertStateAlerting)
+			So(notifier.sent[1].Rule.State, ShouldEqual, m.AlertStateOK)
+			So(deleted, ShouldEqual, 1)
+		})
+
+		Convey("Should keep alerts of other dashboards apart", func() {
+			cmd := &ReceiveAlertsCommand{OrgId: 1, DashboardId: 3, Alerts: []*ReceivedAlert{firing}}
+			So(receiver.handleReceiveAlertsCommand(cmd), ShouldBeNil)
+			So(cmd.Changed, ShouldEqual, 1)
+			So(len(stored), ShouldEqual, 2)
+			So(notifier.sent[1].PrevAlertState, ShouldEqual, m.AlertStateUnknown)
+		})
+
+		Convey("Should reject unknown channels and states", func() {
+			cmd := &ReceiveAlertsCommand{OrgId: 1, NotifierUids: []string{"missing"}}
+			So(receiver.handleReceiveAlertsCommand(cmd), ShouldHaveSameTypeAs, ValidationError{})
+
+			cmd = &ReceiveAlertsCommand{OrgId: 1, Alerts: []*ReceivedAlert{{Title: "x", State: m.AlertStatePending}}}
+			So(receiver.handleReceiveAlertsCommand(cmd), ShouldHaveSameTypeAs, ValidationError{})
+		})
+	})
+}
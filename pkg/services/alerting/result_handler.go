@@ -95,8 +95,100 @@ func (handler *DefaultResultHandler) Handle(evalContext *EvalContext) error {
 		if err := annotationRepo.Save(&item); err != nil {
 			handler.log.Error("Failed to save annotation for new alert state", "error", err)
 		}
+
+		handler.saveHistory(evalContext, &m.SaveAlertHistoryCommand{
+			PrevState: evalContext.PrevAlertState,
+			NewState:  evalContext.Rule.State,
+			EvalData:  annotationData,
+			Error:     executionError,
+		})
+	}
+
+	if evalContext.Rule.MultiDimensional {
+		handler.handleInstances(evalContext)
+		return nil
 	}
 
 	handler.notifier.SendIfNeeded(evalContext)
 	return nil
 }
+
+// handleInstances saves the state of every instance of a multi-dimensional
+// rule and sends notifications for each of them separately.
+func (handler *DefaultResultHandler) handleInstances(evalContext *EvalContext) {
+	for _, instance := range evalContext.Instances {
+		if instance.Missing && !instance.ShouldUpdateState() && instance.State == m.AlertStateOK && evalContext.Error == nil {
+			cmd := &m.DeleteAlertInstanceCommand{Id: instance.Id, AlertId: evalContext.Rule.Id, OrgId: evalContext.Rule.OrgId}
+			if err := bus.Dispatch(cmd); err != nil {
+				handler.log.Error("Failed to delete resolved alert instance", "alertId", evalContext.Rule.Id, "labels", instance.LabelsString(), "error", err)
+			}
+			continue
+		}
+
+		if instance.ShouldUpdateState() {
+			handler.log.Info("New instance state change", "alertId", evalContext.Rule.Id, "labels", instance.LabelsString(), "newState", instance.State, "prev state", instance.PrevState)
+
+			evalData := simplejson.New()
+			if len(instance.EvalMatches) > 0 {
+				evalData.Set("evalMatches", simplejson.NewFromAny(instance.EvalMatches))
+			}
+
+			cmd := &m.SetAlertInstanceStateCommand{
+				AlertId:  evalContext.Rule.Id,
+				OrgId:    evalContext.Rule.OrgId,
+				Labels:   instance.Labels,
+				State:    instance.State,
+				EvalData: evalData,
+			}
+
+			if err := bus.Dispatch(cmd); err != nil {
+				if err == m.ErrRequiresNewState {
+					handler.log.Info("Alert instance already updated")
+					continue
+				}
+
+				handler.log.Error("Failed to save instance state", "error", err)
+				continue
+			}
+
+			instance.Id = cmd.Result.Id
+			instance.StateChanges = cmd.Result.StateChanges
+			instance.LastStateChange = time.Now()
+
+			historyCmd := &m.SaveAlertHistoryCommand{
+				InstanceId: instance.Id,
+				Labels:     instance.Labels,
+				PrevState:  instance.PrevState,
+				NewState:   instance.State,
+				EvalData:   evalData,
+			}
+			if evalContext.Error != nil {
+				historyCmd.Error = evalContext.Error.Error()
+			}
+			handler.saveHistory(evalContext, historyCmd)
+		}
+
+		instanceContext := evalContext.ForInstance(instance)
+		handler.notifier.SendIfNeeded(instanceContext)
+
+		// reuse the rendered panel image for the remaining instances
+		evalContext.ImagePublicUrl = instanceContext.ImagePublicUrl
+		evalContext.ImageOnDiskPath = instanceContext.ImageOnDiskPath
+	}
+}
+
+// saveHistory records a state transition in the alert history. The rule,
+// organization, evaluation duration and time are taken from the context.
+func (handler *DefaultResultHandler) saveHistory(evalContext *EvalContext, cmd *m.SaveAlertHistoryCommand) {
+	cmd.OrgId = evalContext.Rule.OrgId
+	cmd.AlertId = evalContext.Rule.Id
+	cmd.Time = time.Now()
+
+	if !evalContext.EndTime.IsZero() {
+		cmd.Duration = evalContext.EndTime.Sub(evalContext.StartTime)
+	}
+
+	if err := bus.Dispatch(cmd); err != nil {
+		handler.log.Error("Failed to save alert history", "alertId", evalContext.Rule.Id, "error", err)
+	}
+}
@@ -16,6 +16,7 @@ type Rule struct {
 	OrgId               int64
 	DashboardId         int64
 	PanelId             int64
+	FolderId            int64
 	Frequency           int64
 	Name                string
 	Message             string
@@ -26,6 +27,8 @@ type Rule struct {
 	State               m.AlertStateType
 	Conditions          []Condition
 	Notifications       []int64
+	MultiDimensional    bool
+	Timeout             time.Duration
 
 	StateChanges int64
 }
@@ -99,6 +102,7 @@ func NewRuleFromDBAlert(ruleDef *m.Alert) (*Rule, error) {
 	model.OrgId = ruleDef.OrgId
 	model.DashboardId = ruleDef.DashboardId
 	model.PanelId = ruleDef.PanelId
+	model.FolderId = ruleDef.FolderId
 	model.Name = ruleDef.Name
 	model.Message = ruleDef.Message
 	model.Frequency = ruleDef.Frequency
@@ -108,6 +112,15 @@ func NewRuleFromDBAlert(ruleDef *m.Alert) (*Rule, error) {
 	model.NoDataState = m.NoDataOption(ruleDef.Settings.Get("noDataState").MustString("no_data"))
 	model.ExecutionErrorState = m.ExecutionErrorOption(ruleDef.Settings.Get("executionErrorState").MustString("alerting"))
 	model.StateChanges = ruleDef.StateChanges
+	model.MultiDimensional = ruleDef.Settings.Get("multiDimensional").MustBool(false)
+
+	if timeout := ruleDef.Settings.Get("timeout").MustString(); timeout != "" {
+		duration, err := time.ParseDuration(timeout)
+		if err != nil || duration < 0 {
+			return nil, ValidationError{Reason: "Invalid alert timeout: " + timeout, DashboardId: model.DashboardId, Alertid: model.Id, PanelId: model.PanelId}
+		}
+		model.Timeout = duration
+	}
 
 	for _, v := range ruleDef.Settings.Get("notifications").MustArray() {
 		jsonModel := simplejson.NewFromAny(v)
@@ -2,6 +2,7 @@ package alerting
 
 import (
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	m "github.com/grafana/grafana/pkg/models"
@@ -88,6 +89,19 @@ func TestAlertRuleModel(t *testing.T) {
 			Convey("Can read notifications", func() {
 				So(len(alertRule.Notifications), ShouldEqual, 2)
 			})
+
+			Convey("Can read timeout override", func() {
+				So(alertRule.Timeout, ShouldEqual, 0)
+
+				alertJSON.Set("timeout", "2m")
+				alertRule, err := NewRuleFromDBAlert(alert)
+				So(err, ShouldBeNil)
+				So(alertRule.Timeout, ShouldEqual, 2*time.Minute)
+
+				alertJSON.Set("timeout", "soon")
+				_, err = NewRuleFromDBAlert(alert)
+				So(err, ShouldNotBeNil)
+			})
 		})
 	})
 }
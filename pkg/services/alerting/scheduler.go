@@ -5,18 +5,25 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 type SchedulerImpl struct {
 	jobs map[int64]*Job
 	log  log.Logger
+
+	// skipWhenFull drops due jobs while the exec queue is full instead of
+	// waiting for a free slot
+	skipWhenFull bool
 }
 
 func NewScheduler() Scheduler {
 	return &SchedulerImpl{
-		jobs: make(map[int64]*Job),
-		log:  log.New("alerting.scheduler"),
+		jobs:         make(map[int64]*Job),
+		log:          log.New("alerting.scheduler"),
+		skipWhenFull: setting.AlertingEvaluationBackpressure == "skip",
 	}
 }
 
@@ -72,7 +79,24 @@ func (s *SchedulerImpl) Tick(tickTime time.Time, execQueue chan *Job) {
 	}
 }
 
+// enqueue marks the job as running while it waits in the queue so that a
+// rule is never queued twice.
 func (s *SchedulerImpl) enqueue(job *Job, execQueue chan *Job) {
 	s.log.Debug("Scheduler: Putting job on to exec queue", "name", job.Rule.Name, "id", job.Rule.Id)
-	execQueue <- job
+
+	if s.skipWhenFull {
+		job.Running = true
+		select {
+		case execQueue <- job:
+		default:
+			job.Running = false
+			s.log.Warn("Scheduler: Exec queue is full, skipping evaluation", "name", job.Rule.Name, "id", job.Rule.Id)
+			metrics.M_Alerting_Skipped_Evaluations.Inc()
+		}
+	} else {
+		job.Running = true
+		execQueue <- job
+	}
+
+	metrics.M_Alerting_Queue_Depth.Set(float64(len(execQueue)))
 }
@@ -0,0 +1,121 @@
+package alerting
+
+import (
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// getActiveSilence returns the first active silence matching the alert
+// or nil when notifications should be sent.
+func (c *EvalContext) getActiveSilence() (*m.AlertSilence, error) {
+	query := &m.GetAlertSilencesQuery{OrgId: c.Rule.OrgId, State: "active"}
+	if err := bus.Dispatch(query); err != nil {
+		return nil, err
+	}
+
+	for _, silence := range query.Result {
+		matches, err := c.matchesSilence(silence)
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			return silence, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// matchesSilence returns true if all matchers of the silence match the alert.
+func (c *EvalContext) matchesSilence(silence *m.AlertSilence) (bool, error) {
+	if len(silence.Matchers) == 0 {
+		return false, nil
+	}
+
+	for _, matcher := range silence.Matchers {
+		matches, err := c.matchesSilenceMatcher(matcher)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (c *EvalContext) matchesSilenceMatcher(matcher *m.AlertSilenceMatcher) (bool, error) {
+	switch matcher.Type {
+	case m.AlertSilenceMatchDashboard:
+		if c.Rule.DashboardId == 0 {
+			return false, nil
+		}
+
+		ref, err := c.GetDashboardUID()
+		if err != nil {
+			return false, err
+		}
+		return matcher.MatchValue(ref.Uid), nil
+
+	case m.AlertSilenceMatchPanel:
+		return matcher.MatchValue(strconv.FormatInt(c.Rule.PanelId, 10)), nil
+
+	case m.AlertSilenceMatchRule:
+		return matcher.MatchValue(c.Rule.Name), nil
+
+	case m.AlertSilenceMatchTag:
+		tags, err := c.getDashboardTags()
+		if err != nil {
+			return false, err
+		}
+
+		for _, tag := range tags {
+			if matcher.MatchValue(tag) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case m.AlertSilenceMatchLabel:
+		if c.Instance != nil {
+			value, exist := c.Instance.Labels[matcher.Name]
+			return exist && matcher.MatchValue(value), nil
+		}
+
+		// rules that are not multi-dimensional are silenced when
+		// every matching series carries the label
+		if len(c.EvalMatches) == 0 {
+			return false, nil
+		}
+
+		for _, match := range c.EvalMatches {
+			value, exist := match.Tags[matcher.Name]
+			if !exist || !matcher.MatchValue(value) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (c *EvalContext) getDashboardTags() ([]string, error) {
+	if c.dashboardTags != nil {
+		return c.dashboardTags, nil
+	}
+
+	// standalone alert rules have no tags
+	if c.Rule.DashboardId == 0 {
+		return []string{}, nil
+	}
+
+	query := &m.GetDashboardQuery{Id: c.Rule.DashboardId, OrgId: c.Rule.OrgId}
+	if err := bus.Dispatch(query); err != nil {
+		return nil, err
+	}
+
+	c.dashboardTags = query.Result.GetTags()
+	return c.dashboardTags, nil
+}
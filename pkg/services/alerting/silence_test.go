@@ -0,0 +1,112 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertSilences(t *testing.T) {
+	Convey("Test alert silences", t, func() {
+		silences := []*m.AlertSilence{}
+
+		bus.ClearBusHandlers()
+		bus.AddHandler("test", func(query *m.GetAlertSilencesQuery) error {
+			query.Result = silences
+			return nil
+		})
+		bus.AddHandler("test", func(query *m.GetDashboardRefByIdQuery) error {
+			query.Result = &m.DashboardRef{Uid: "dash-uid", Slug: "dash"}
+			return nil
+		})
+		bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+			query.Result = &m.Dashboard{Data: simplejson.NewFromAny(map[string]interface{}{"tags": []interface{}{"prod", "database"}})}
+			return nil
+		})
+
+		ctx := NewEvalContext(context.TODO(), &Rule{OrgId: 1, DashboardId: 1, PanelId: 2, Name: "cpu usage"})
+		ctx.EvalMatches = []*EvalMatch{
+			{Metric: "cpu", Tags: map[string]string{"host": "server-1", "dc": "eu"}},
+			{Metric: "cpu", Tags: map[string]string{"host": "server-2", "dc": "eu"}},
+		}
+
+		silence := func(matchers ...*m.AlertSilenceMatcher) {
+			silences = append(silences, &m.AlertSilence{
+				Id:       int64(len(silences) + 1),
+				StartsAt: time.Now().Add(-time.Hour),
+				EndsAt:   time.Now().Add(time.Hour),
+				Matchers: matchers,
+			})
+		}
+
+		Convey("Should not be silenced without silences", func() {
+			s, err := ctx.getActiveSilence()
+			So(err, ShouldBeNil)
+			So(s, ShouldBeNil)
+		})
+
+		Convey("Should match dashboard, panel and rule name", func() {
+			silence(
+				&m.AlertSilenceMatcher{Type: m.AlertSilenceMatchDashboard, Value: "dash-uid"},
+				&m.AlertSilenceMatcher{Type: m.AlertSilenceMatchPanel, Value: "2"},
+				&m.AlertSilenceMatcher{Type: m.AlertSilenceMatchRule, Value: "cpu.*", IsRegex: true},
+			)
+
+			s, err := ctx.getActiveSilence()
+			So(err, ShouldBeNil)
+			So(s, ShouldNotBeNil)
+		})
+
+		Convey("Should require all matchers to match", func() {
+			silence(
+				&m.AlertSilenceMatcher{Type: m.AlertSilenceMatchDashboard, Value: "dash-uid"},
+				&m.AlertSilenceMatcher{Type: m.AlertSilenceMatchRule, Value: "cpu"},
+			)
+
+			s, err := ctx.getActiveSilence()
+			So(err, ShouldBeNil)
+			So(s, ShouldBeNil)
+		})
+
+		Convey("Should match dashboard tags", func() {
+			silence(&m.AlertSilenceMatcher{Type: m.AlertSilenceMatchTag, Value: "database"})
+
+			s, err := ctx.getActiveSilence()
+			So(err, ShouldBeNil)
+			So(s, ShouldNotBeNil)
+		})
+
+		Convey("Should match labels of every series", func() {
+			silence(&m.AlertSilenceMatcher{Type: m.AlertSilenceMatchLabel, Name: "host", Value: "server-1"})
+
+			s, err := ctx.getActiveSilence()
+			So(err, ShouldBeNil)
+			So(s, ShouldBeNil)
+
+			silence(&m.AlertSilenceMatcher{Type: m.AlertSilenceMatchLabel, Name: "dc", Value: "eu"})
+
+			s, err = ctx.getActiveSilence()
+			So(err, ShouldBeNil)
+			So(s.Id, ShouldEqual, 2)
+		})
+
+		Convey("Should match labels of an instance", func() {
+			silence(&m.AlertSilenceMatcher{Type: m.AlertSilenceMatchLabel, Name: "host", Value: "server-1"})
+
+			instanceCtx := ctx.ForInstance(&Instance{Labels: map[string]string{"host": "server-1"}})
+			s, err := instanceCtx.getActiveSilence()
+			So(err, ShouldBeNil)
+			So(s, ShouldNotBeNil)
+
+			instanceCtx = ctx.ForInstance(&Instance{Labels: map[string]string{"host": "server-2"}})
+			s, err = instanceCtx.getActiveSilence()
+			So(err, ShouldBeNil)
+			So(s, ShouldBeNil)
+		})
+	})
+}
@@ -16,6 +16,11 @@ type NotificationTestCommand struct {
 	Name     string
 	Type     string
 	Settings *simplejson.Json
+	Preview  bool
+
+	// Title and Body are the rendered templates of the test notification
+	Title string
+	Body  string
 }
 
 func init() {
@@ -32,6 +37,10 @@ func handleNotificationTestCommand(cmd *NotificationTestCommand) error {
 		Settings: cmd.Settings,
 	}
 
+	if err := ValidateNotificationTemplates(cmd.Settings); err != nil {
+		return err
+	}
+
 	notifiers, err := notifier.createNotifierFor(model)
 
 	if err != nil {
@@ -39,7 +48,35 @@ func handleNotificationTestCommand(cmd *NotificationTestCommand) error {
 		return err
 	}
 
-	return notifier.sendNotifications(createTestEvalContext(cmd), notifierStateSlice{{notifier: notifiers}})
+	evalContext := createTestEvalContext(cmd)
+	if err := renderTestNotification(cmd, evalContext); err != nil {
+		return err
+	}
+
+	if cmd.Preview {
+		return nil
+	}
+
+	return notifier.sendNotifications(evalContext, notifierStateSlice{{notifier: notifiers}})
+}
+
+func renderTestNotification(cmd *NotificationTestCommand, evalContext *EvalContext) error {
+	titleTemplate, err := ParseNotificationTemplate("title", cmd.Settings.Get("titleTemplate").MustString())
+	if err != nil {
+		return err
+	}
+
+	bodyTemplate, err := ParseNotificationTemplate("body", cmd.Settings.Get("bodyTemplate").MustString())
+	if err != nil {
+		return err
+	}
+
+	if cmd.Title, err = RenderNotificationTemplate(titleTemplate, evalContext, evalContext.GetNotificationTitle()); err != nil {
+		return err
+	}
+
+	cmd.Body, err = RenderNotificationTemplate(bodyTemplate, evalContext, evalContext.Rule.Message)
+	return err
 }
 
 func createTestEvalContext(cmd *NotificationTestCommand) *EvalContext {
@@ -23,28 +23,33 @@ func init() {
 }
 
 func handleAlertTestCommand(cmd *AlertTestCommand) error {
+	rule, err := getPanelAlertRule(cmd.Dashboard, cmd.PanelId, cmd.OrgId, cmd.User)
+	if err != nil {
+		return err
+	}
 
-	dash := m.NewDashboardFromJson(cmd.Dashboard)
+	cmd.Result = testAlertRule(rule)
+	return nil
+}
+
+// getPanelAlertRule builds the alert rule of a panel from an unsaved
+// dashboard model.
+func getPanelAlertRule(dashboard *simplejson.Json, panelId int64, orgId int64, user *m.SignedInUser) (*Rule, error) {
+	dash := m.NewDashboardFromJson(dashboard)
 
-	extractor := NewDashAlertExtractor(dash, cmd.OrgId, cmd.User)
+	extractor := NewDashAlertExtractor(dash, orgId, user)
 	alerts, err := extractor.GetAlerts()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, alert := range alerts {
-		if alert.PanelId == cmd.PanelId {
-			rule, err := NewRuleFromDBAlert(alert)
-			if err != nil {
-				return err
-			}
-
-			cmd.Result = testAlertRule(rule)
-			return nil
+		if alert.PanelId == panelId {
+			return NewRuleFromDBAlert(alert)
 		}
 	}
 
-	return fmt.Errorf("Could not find alert with panel id %d", cmd.PanelId)
+	return nil, fmt.Errorf("Could not find alert with panel id %d", panelId)
 }
 
 func testAlertRule(rule *Rule) *EvalContext {
@@ -55,6 +60,7 @@ func testAlertRule(rule *Rule) *EvalContext {
 
 	handler.Eval(context)
 	context.Rule.State = context.GetNewState()
+	context.UpdateInstanceStates()
 
 	return context
 }
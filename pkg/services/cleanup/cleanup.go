@@ -40,9 +40,16 @@ func (srv *CleanUpService) Run(ctx context.Context) error {
 			srv.cleanUpTmpFiles()
 			srv.deleteExpiredSnapshots()
 			srv.deleteExpiredDashboardVersions()
+			srv.deleteExpiredAlertSilences()
+			srv.deleteExpiredAlertHistory()
+			srv.deleteExpiredNotificationDeliveries()
+			srv.deleteExpiredQueryCacheItems()
 			srv.ServerLockService.LockAndExecute(ctx, "delete old login attempts", time.Minute*10, func() {
 				srv.deleteOldLoginAttempts()
 			})
+			srv.ServerLockService.LockAndExecute(ctx, "delete stale alert heartbeats", time.Minute*10, func() {
+				srv.deleteStaleAlertHeartbeats()
+			})
 
 		case <-ctx.Done():
 			return ctx.Err()
@@ -107,6 +114,64 @@ func (srv *CleanUpService) deleteExpiredDashboardVersions() {
 	}
 }
 
+func (srv *CleanUpService) deleteExpiredAlertSilences() {
+	if srv.Cfg.AlertingSilenceMaxAge == 0 {
+		return
+	}
+
+	cmd := m.DeleteExpiredAlertSilencesCommand{
+		EndedBefore: time.Now().Add(-srv.Cfg.AlertingSilenceMaxAge),
+	}
+	if err := bus.Dispatch(&cmd); err != nil {
+		srv.log.Error("Failed to delete expired alert silences", "error", err.Error())
+	} else {
+		srv.log.Debug("Deleted expired alert silences", "rows affected", cmd.DeletedRows)
+	}
+}
+
+func (srv *CleanUpService) deleteExpiredAlertHistory() {
+	if srv.Cfg.AlertingHistoryMaxAge == 0 {
+		return
+	}
+
+	cmd := m.DeleteExpiredAlertHistoryCommand{
+		OlderThan: time.Now().Add(-srv.Cfg.AlertingHistoryMaxAge),
+	}
+	if err := bus.Dispatch(&cmd); err != nil {
+		srv.log.Error("Failed to delete expired alert history", "error", err.Error())
+	} else {
+		srv.log.Debug("Deleted expired alert history", "rows affected", cmd.DeletedRows)
+	}
+}
+
+func (srv *CleanUpService) deleteExpiredNotificationDeliveries() {
+	if srv.Cfg.NotificationDeliveryMaxAge == 0 {
+		return
+	}
+
+	cmd := m.DeleteExpiredNotificationDeliveriesCommand{
+		OlderThan: time.Now().Add(-srv.Cfg.NotificationDeliveryMaxAge),
+	}
+	if err := bus.Dispatch(&cmd); err != nil {
+		srv.log.Error("Failed to delete expired notification deliveries", "error", err.Error())
+	} else {
+		srv.log.Debug("Deleted expired notification deliveries", "rows affected", cmd.DeletedRows)
+	}
+}
+
+func (srv *CleanUpService) deleteExpiredQueryCacheItems() {
+	if !srv.Cfg.QueryCacheEnabled || srv.Cfg.QueryCacheBackend != "database" {
+		return
+	}
+
+	cmd := m.DeleteExpiredQueryCacheItemsCommand{}
+	if err := bus.Dispatch(&cmd); err != nil {
+		srv.log.Error("Failed to delete expired query cache items", "error", err.Error())
+	} else {
+		srv.log.Debug("Deleted expired query cache items", "rows affected", cmd.DeletedRows)
+	}
+}
+
 func (srv *CleanUpService) deleteOldLoginAttempts() {
 	if srv.Cfg.DisableBruteForceLoginProtection {
 		return
@@ -121,3 +186,14 @@ func (srv *CleanUpService) deleteOldLoginAttempts() {
 		srv.log.Debug("Deleted expired login attempts", "rows affected", cmd.DeletedRows)
 	}
 }
+
+func (srv *CleanUpService) deleteStaleAlertHeartbeats() {
+	cmd := m.DeleteStaleAlertHeartbeatsCommand{
+		OlderThan: time.Now().Add(-time.Hour),
+	}
+	if err := bus.Dispatch(&cmd); err != nil {
+		srv.log.Error("Problem deleting stale alert heartbeats", "error", err.Error())
+	} else {
+		srv.log.Debug("Deleted stale alert heartbeats", "rows affected", cmd.DeletedRows)
+	}
+}
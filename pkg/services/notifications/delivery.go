@@ -0,0 +1,193 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/metrics"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+const (
+	deliveryRetryInterval = 10 * time.Second
+	deliveryBatchSize     = 100
+
+	// deliveryClaimTimeout hides a claimed delivery from other servers
+	// while it is sent, it must be longer than the webhook timeout.
+	deliveryClaimTimeout = time.Minute
+)
+
+// deliverWebhook sends a webhook of a notification channel and records the
+// delivery. Webhooks that fail with a network error or a server error are
+// queued for retry and reported as sent, any other failure is a dead letter.
+func (ns *NotificationService) deliverWebhook(ctx context.Context, target *m.NotificationDeliveryTarget, webhook *Webhook) error {
+	start := time.Now()
+	err := ns.sendWebRequestSync(ctx, webhook)
+
+	cmd := &m.CreateNotificationDeliveryCommand{
+		Target:   target,
+		Attempts: 1,
+		Request:  webhookToRequest(webhook),
+	}
+
+	switch {
+	case err == nil:
+		metrics.M_Alerting_Notification_Latency.WithLabelValues(target.NotifierType).Observe(float64(time.Since(start) / time.Millisecond))
+		cmd.State = m.NotificationDeliveryDelivered
+	case isRetryable(err):
+		metrics.M_Alerting_Notification_Failed.WithLabelValues(target.NotifierType).Inc()
+		cmd.State = m.NotificationDeliveryPending
+		cmd.LastError = err.Error()
+		cmd.NextAttemptAt = start.Add(ns.retryBackoff(1)).Unix()
+	default:
+		metrics.M_Alerting_Notification_Failed.WithLabelValues(target.NotifierType).Inc()
+		metrics.M_Alerting_Notification_Dead_Letters.WithLabelValues(target.NotifierType).Inc()
+		cmd.State = m.NotificationDeliveryFailed
+		cmd.LastError = err.Error()
+	}
+
+	if dispatchErr := ns.Bus.Dispatch(cmd); dispatchErr != nil {
+		ns.log.Error("Failed to save notification delivery", "notifier", target.NotifierId, "error", dispatchErr)
+		return err
+	}
+
+	if cmd.State == m.NotificationDeliveryPending {
+		ns.log.Warn("Failed to send notification, queued for retry", "notifier", target.NotifierId, "delivery", cmd.Result.Id, "error", err)
+		return nil
+	}
+
+	return err
+}
+
+// runDeliveryRetries retries the due deliveries until ctx is done.
+func (ns *NotificationService) runDeliveryRetries(ctx context.Context) {
+	ticker := time.NewTicker(deliveryRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ns.retryDueDeliveries(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// retryDueDeliveries sends the queued deliveries that are due.
+func (ns *NotificationService) retryDueDeliveries(ctx context.Context) {
+	query := &m.GetDueNotificationDeliveriesQuery{Now: time.Now().Unix(), Limit: deliveryBatchSize}
+	if err := ns.Bus.Dispatch(query); err != nil {
+		ns.log.Error("Failed to get due notification deliveries", "error", err)
+		return
+	}
+
+	for _, delivery := range query.Result {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := ns.retryDelivery(delivery); err != nil {
+			ns.log.Error("Failed to retry notification delivery", "delivery", delivery.Id, "error", err)
+		}
+	}
+}
+
+func (ns *NotificationService) retryDelivery(delivery *m.NotificationDelivery) error {
+	now := time.Now()
+
+	claim := &m.ClaimNotificationDeliveryCommand{
+		Id:       delivery.Id,
+		Attempts: delivery.Attempts,
+		RetryAt:  now.Add(deliveryClaimTimeout).Unix(),
+	}
+	if err := ns.Bus.Dispatch(claim); err != nil {
+		if err == m.ErrNotificationDeliveryConflict {
+			return nil
+		}
+		return err
+	}
+	attempts := delivery.Attempts + 1
+
+	err := ns.sendWebRequestSync(context.Background(), requestToWebhook(delivery.Request.Decrypt()))
+
+	cmd := &m.SetNotificationDeliveryStateCommand{Id: delivery.Id}
+
+	switch {
+	case err == nil:
+		metrics.M_Alerting_Notification_Latency.WithLabelValues(delivery.NotifierType).Observe(float64(time.Since(time.Unix(delivery.Created, 0)) / time.Millisecond))
+		cmd.State = m.NotificationDeliveryDelivered
+	case isRetryable(err) && attempts < ns.Cfg.NotificationMaxAttempts:
+		metrics.M_Alerting_Notification_Failed.WithLabelValues(delivery.NotifierType).Inc()
+		cmd.State = m.NotificationDeliveryPending
+		cmd.LastError = err.Error()
+		cmd.NextAttemptAt = now.Add(ns.retryBackoff(attempts)).Unix()
+	default:
+		metrics.M_Alerting_Notification_Failed.WithLabelValues(delivery.NotifierType).Inc()
+		metrics.M_Alerting_Notification_Dead_Letters.WithLabelValues(delivery.NotifierType).Inc()
+		cmd.State = m.NotificationDeliveryFailed
+		cmd.LastError = err.Error()
+		ns.log.Error("Giving up sending notification", "notifier", delivery.NotifierId, "delivery", delivery.Id, "attempts", attempts, "error", err)
+	}
+
+	return ns.Bus.Dispatch(cmd)
+}
+
+// retryBackoff doubles the wait after every failed attempt, up to the
+// configured maximum.
+func (ns *NotificationService) retryBackoff(attempts int) time.Duration {
+	backoff := ns.Cfg.NotificationRetryBackoff
+	for i := 1; i < attempts && backoff < ns.Cfg.NotificationMaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > ns.Cfg.NotificationMaxRetryBackoff {
+		return ns.Cfg.NotificationMaxRetryBackoff
+	}
+
+	return backoff
+}
+
+// isRetryable reports whether sending the webhook again might succeed.
+// Client errors other than rate limits will fail the same way again.
+func isRetryable(err error) bool {
+	webhookErr, ok := err.(*WebhookError)
+	if !ok {
+		return true
+	}
+
+	return webhookErr.StatusCode >= 500 || webhookErr.StatusCode == http.StatusTooManyRequests
+}
+
+func webhookToRequest(webhook *Webhook) map[string]string {
+	headers, _ := json.Marshal(webhook.HttpHeader)
+
+	return map[string]string{
+		"url":         webhook.Url,
+		"user":        webhook.User,
+		"password":    webhook.Password,
+		"body":        webhook.Body,
+		"httpMethod":  webhook.HttpMethod,
+		"contentType": webhook.ContentType,
+		"httpHeader":  string(headers),
+	}
+}
+
+func requestToWebhook(request map[string]string) *Webhook {
+	webhook := &Webhook{
+		Url:         request["url"],
+		User:        request["user"],
+		Password:    request["password"],
+		Body:        request["body"],
+		HttpMethod:  request["httpMethod"],
+		ContentType: request["contentType"],
+	}
+
+	if headers := request["httpHeader"]; headers != "" {
+		json.Unmarshal([]byte(headers), &webhook.HttpHeader)
+	}
+
+	return webhook
+}
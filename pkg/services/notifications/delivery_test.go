@@ -0,0 +1,137 @@
+package notifications
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/securejsondata"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNotificationDelivery(t *testing.T) {
+	Convey("Given the notifications service with retries", t, func() {
+		status := http.StatusOK
+		requests := 0
+		token := ""
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			token = r.Header.Get("X-Token")
+			w.WriteHeader(status)
+			w.Write([]byte(`{"id": 1}`))
+		}))
+		defer server.Close()
+
+		ns := &NotificationService{Bus: bus.New(), Cfg: setting.NewCfg(), log: log.New("notifications")}
+		ns.Cfg.NotificationMaxAttempts = 3
+		ns.Cfg.NotificationRetryBackoff = time.Minute
+		ns.Cfg.NotificationMaxRetryBackoff = 3 * time.Minute
+
+		var created *m.CreateNotificationDeliveryCommand
+		ns.Bus.AddHandler(func(cmd *m.CreateNotificationDeliveryCommand) error {
+			created = cmd
+			cmd.Result = &m.NotificationDelivery{Id: 1}
+			return nil
+		})
+
+		var updated *m.SetNotificationDeliveryStateCommand
+		ns.Bus.AddHandler(func(cmd *m.SetNotificationDeliveryStateCommand) error {
+			updated = cmd
+			return nil
+		})
+
+		claimed := false
+		ns.Bus.AddHandler(func(cmd *m.ClaimNotificationDeliveryCommand) error {
+			if claimed {
+				return m.ErrNotificationDeliveryConflict
+			}
+			claimed = true
+			return nil
+		})
+
+		target := &m.NotificationDeliveryTarget{OrgId: 1, AlertId: 2, NotifierId: 3, NotifierType: "webhook"}
+		ctx := m.WithNotificationDeliveryTarget(context.Background(), target)
+		cmd := &m.SendWebhookSync{Url: server.URL, Body: "{}", HttpHeader: map[string]string{"X-Token": "secret"}}
+
+		Convey("Webhooks without target are not recorded", func() {
+			status = http.StatusServiceUnavailable
+			So(ns.SendWebhookSync(context.Background(), cmd), ShouldNotBeNil)
+			So(created, ShouldBeNil)
+		})
+
+		Convey("Successful webhook is recorded as delivered", func() {
+			So(ns.SendWebhookSync(ctx, cmd), ShouldBeNil)
+			So(created.State, ShouldEqual, m.NotificationDeliveryDelivered)
+			So(created.Target, ShouldEqual, target)
+			So(string(cmd.ResponseBody), ShouldEqual, `{"id": 1}`)
+		})
+
+		Convey("Webhook with server error is queued for retry", func() {
+			status = http.StatusBadGateway
+			So(ns.SendWebhookSync(ctx, cmd), ShouldBeNil)
+			So(created.State, ShouldEqual, m.NotificationDeliveryPending)
+			So(created.Attempts, ShouldEqual, 1)
+			So(created.LastError, ShouldEqual, "Webhook response status 502 Bad Gateway")
+			So(created.NextAttemptAt, ShouldBeGreaterThan, time.Now().Unix())
+
+			delivery := &m.NotificationDelivery{
+				Id:           1,
+				NotifierType: "webhook",
+				Attempts:     1,
+				Request:      securejsondata.GetEncryptedJsonData(created.Request),
+			}
+
+			Convey("Retry that succeeds marks it as delivered", func() {
+				status = http.StatusOK
+				So(ns.retryDelivery(delivery), ShouldBeNil)
+				So(requests, ShouldEqual, 2)
+				So(token, ShouldEqual, "secret")
+				So(updated.State, ShouldEqual, m.NotificationDeliveryDelivered)
+			})
+
+			Convey("Retry that fails is queued again", func() {
+				So(ns.retryDelivery(delivery), ShouldBeNil)
+				So(updated.State, ShouldEqual, m.NotificationDeliveryPending)
+				So(updated.NextAttemptAt, ShouldBeGreaterThan, time.Now().Add(time.Minute).Unix())
+			})
+
+			Convey("Retry that fails the last attempt is a dead letter", func() {
+				delivery.Attempts = 2
+				So(ns.retryDelivery(delivery), ShouldBeNil)
+				So(updated.State, ShouldEqual, m.NotificationDeliveryFailed)
+			})
+
+			Convey("Delivery claimed by another server is skipped", func() {
+				claimed = true
+				So(ns.retryDelivery(delivery), ShouldBeNil)
+				So(requests, ShouldEqual, 1)
+				So(updated, ShouldBeNil)
+			})
+		})
+
+		Convey("Webhook with client error is a dead letter", func() {
+			status = http.StatusBadRequest
+			So(ns.SendWebhookSync(ctx, cmd), ShouldNotBeNil)
+			So(created.State, ShouldEqual, m.NotificationDeliveryFailed)
+		})
+
+		Convey("Rate limited webhook is queued for retry", func() {
+			status = http.StatusTooManyRequests
+			So(ns.SendWebhookSync(ctx, cmd), ShouldBeNil)
+			So(created.State, ShouldEqual, m.NotificationDeliveryPending)
+		})
+
+		Convey("Retries back off exponentially up to the maximum", func() {
+			So(ns.retryBackoff(1), ShouldEqual, time.Minute)
+			So(ns.retryBackoff(2), ShouldEqual, 2*time.Minute)
+			So(ns.retryBackoff(3), ShouldEqual, 3*time.Minute)
+			So(ns.retryBackoff(10), ShouldEqual, 3*time.Minute)
+		})
+	})
+}
@@ -74,6 +74,9 @@ func (ns *NotificationService) Init() error {
 }
 
 func (ns *NotificationService) Run(ctx context.Context) error {
+	// retries can take a while, they must not hold up the queued notifications
+	go ns.runDeliveryRetries(ctx)
+
 	for {
 		select {
 		case webhook := <-ns.webhookQueue:
@@ -101,7 +104,7 @@ func (ns *NotificationService) Run(ctx context.Context) error {
 }
 
 func (ns *NotificationService) SendWebhookSync(ctx context.Context, cmd *m.SendWebhookSync) error {
-	return ns.sendWebRequestSync(ctx, &Webhook{
+	webhook := &Webhook{
 		Url:         cmd.Url,
 		User:        cmd.User,
 		Password:    cmd.Password,
@@ -109,7 +112,17 @@ func (ns *NotificationService) SendWebhookSync(ctx context.Context, cmd *m.SendW
 		HttpMethod:  cmd.HttpMethod,
 		HttpHeader:  cmd.HttpHeader,
 		ContentType: cmd.ContentType,
-	})
+	}
+
+	var err error
+	if target := m.NotificationDeliveryTargetFromContext(ctx); target != nil && ns.Cfg.NotificationMaxAttempts > 1 {
+		err = ns.deliverWebhook(ctx, target, webhook)
+	} else {
+		err = ns.sendWebRequestSync(ctx, webhook)
+	}
+
+	cmd.ResponseBody = webhook.ResponseBody
+	return err
 }
 
 func subjectTemplateFunc(obj map[string]interface{}, value string) string {
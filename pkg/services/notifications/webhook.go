@@ -22,6 +22,19 @@ type Webhook struct {
 	HttpMethod  string
 	HttpHeader  map[string]string
 	ContentType string
+
+	// ResponseBody is set to the body of a successful response.
+	ResponseBody []byte
+}
+
+// WebhookError is returned when a webhook was answered with a non 2xx status.
+type WebhookError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("Webhook response status %v", e.Status)
 }
 
 var netTransport = &http.Transport{
@@ -69,16 +82,17 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 		return err
 	}
 
-	if resp.StatusCode/100 == 2 {
-		return nil
-	}
-
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
 
+	if resp.StatusCode/100 == 2 {
+		webhook.ResponseBody = body
+		return nil
+	}
+
 	ns.log.Debug("Webhook failed", "statuscode", resp.Status, "body", string(body))
-	return fmt.Errorf("Webhook response status %v", resp.Status)
+	return &WebhookError{StatusCode: resp.StatusCode, Status: resp.Status}
 }
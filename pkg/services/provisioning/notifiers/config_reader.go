@@ -0,0 +1,118 @@
+package notifiers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"gopkg.in/yaml.v2"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*NotificationsAsConfig, error) {
+	var notifications []*NotificationsAsConfig
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		cr.log.Error("can't read alert notification provisioning files from directory", "path", path)
+		return notifications, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			notification, err := cr.parseNotificationConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if notification != nil {
+				notifications = append(notifications, notification)
+			}
+		}
+	}
+
+	err = validateNotifications(notifications)
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (cr *configReader) parseNotificationConfig(path string, file os.FileInfo) (*NotificationsAsConfig, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+	yamlFile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *ConfigVersion
+	err = yaml.Unmarshal(yamlFile, &apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiVersion == nil {
+		apiVersion = &ConfigVersion{ApiVersion: 1}
+	}
+
+	var v1 *NotificationsAsConfigV1
+	err = yaml.Unmarshal(yamlFile, &v1)
+	if err != nil {
+		return nil, err
+	}
+
+	return v1.mapToNotificationFromConfig(apiVersion.ApiVersion), nil
+}
+
+func validateNotifications(notifications []*NotificationsAsConfig) error {
+	uids := map[int64]map[string]bool{}
+
+	for i := range notifications {
+		for _, n := range notifications[i].Notifications {
+			if n.OrgId == 0 {
+				n.OrgId = 1
+			}
+
+			if n.Uid == "" {
+				return ErrInvalidConfigMissingUid
+			}
+
+			if n.Name == "" || n.Type == "" {
+				return fmt.Errorf("alert notification %s is missing name or type", n.Uid)
+			}
+
+			if err := alerting.ValidateNotificationTemplates(createSettings(n)); err != nil {
+				return fmt.Errorf("alert notification %s: %v", n.Uid, err)
+			}
+
+			if uids[n.OrgId] == nil {
+				uids[n.OrgId] = map[string]bool{}
+			}
+
+			if uids[n.OrgId][n.Uid] {
+				return fmt.Errorf("alert notification uid %s is used more than once in organization %d", n.Uid, n.OrgId)
+			}
+			uids[n.OrgId][n.Uid] = true
+		}
+
+		for _, n := range notifications[i].DeleteNotifications {
+			if n.OrgId == 0 {
+				n.OrgId = 1
+			}
+
+			if n.Uid == "" {
+				return ErrInvalidConfigMissingUid
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package notifiers
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/models"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var (
+	logger log.Logger = log.New("fake.log")
+
+	twoNotificationsConfig   = "testdata/two-notifications"
+	deleteNotificationConfig = "testdata/delete-notifications"
+	duplicateUidConfig       = "testdata/duplicate-uid"
+	brokenYaml               = "testdata/broken-yaml"
+	zeroNotificationsConfig  = "testdata/zero-notifications"
+
+	fakeRepo *fakeRepository
+)
+
+func TestNotificationAsConfig(t *testing.T) {
+	Convey("Testing alert notifications as configuration", t, func() {
+		fakeRepo = &fakeRepository{}
+		bus.ClearBusHandlers()
+		bus.AddHandler("test", mockDelete)
+		bus.AddHandler("test", mockInsert)
+		bus.AddHandler("test", mockUpdate)
+		bus.AddHandler("test", mockGetByUid)
+		bus.AddHandler("test", mockGetByName)
+
+		Convey("Two configured notifications", func() {
+			Convey("no notifications in database", func() {
+				nc := newNotificationProvisioner(logger)
+				err := nc.applyChanges(twoNotificationsConfig)
+				if err != nil {
+					t.Fatalf("applyChanges return an error %v", err)
+				}
+
+				So(len(fakeRepo.deleted), ShouldEqual, 0)
+				So(len(fakeRepo.inserted), ShouldEqual, 2)
+				So(len(fakeRepo.updated), ShouldEqual, 0)
+
+				So(fakeRepo.inserted[0].Uid, ShouldEqual, "ops-slack")
+				So(fakeRepo.inserted[0].ReadOnly, ShouldBeTrue)
+				So(fakeRepo.inserted[1].ReadOnly, ShouldBeFalse)
+			})
+
+			Convey("one notification in database with same uid", func() {
+				fakeRepo.loadAll = []*models.AlertNotification{
+					{Uid: "ops-slack", Name: "old-name", OrgId: 2, Id: 1},
+				}
+
+				Convey("should update one notification", func() {
+					nc := newNotificationProvisioner(logger)
+					err := nc.applyChanges(twoNotificationsConfig)
+					if err != nil {
+						t.Fatalf("applyChanges return an error %v", err)
+					}
+
+					So(len(fakeRepo.deleted), ShouldEqual, 0)
+					So(len(fakeRepo.inserted), ShouldEqual, 1)
+					So(len(fakeRepo.updated), ShouldEqual, 1)
+					So(fakeRepo.updated[0].Id, ShouldEqual, 1)
+					So(fakeRepo.updated[0].Name, ShouldEqual, "ops-slack")
+				})
+			})
+
+			Convey("one notification in database with same name", func() {
+				fakeRepo.loadAll = []*models.AlertNotification{
+					{Uid: "abc123", Name: "ops-slack", OrgId: 2, Id: 3},
+				}
+
+				Convey("should adopt the notification", func() {
+					nc := newNotificationProvisioner(logger)
+					err := nc.applyChanges(twoNotificationsConfig)
+					if err != nil {
+						t.Fatalf("applyChanges return an error %v", err)
+					}
+
+					So(len(fakeRepo.inserted), ShouldEqual, 1)
+					So(len(fakeRepo.updated), ShouldEqual, 1)
+					So(fakeRepo.updated[0].Id, ShouldEqual, 3)
+					So(fakeRepo.updated[0].Uid, ShouldEqual, "ops-slack")
+					So(fakeRepo.updated[0].ReadOnly, ShouldBeTrue)
+				})
+			})
+		})
+
+		Convey("Deleting notifications", func() {
+			fakeRepo.loadAll = []*models.AlertNotification{
+				{Uid: "old-email", OrgId: 1, Id: 1},
+				{Uid: "old-slack", OrgId: 1, Id: 2},
+			}
+
+			Convey("should only delete notifications in the configured organization", func() {
+				nc := newNotificationProvisioner(logger)
+				err := nc.applyChanges(deleteNotificationConfig)
+				if err != nil {
+					t.Fatalf("applyChanges return an error %v", err)
+				}
+
+				So(len(fakeRepo.deleted), ShouldEqual, 1)
+				So(fakeRepo.deleted[0].Id, ShouldEqual, 1)
+			})
+		})
+
+		Convey("Duplicate uids should return error", func() {
+			nc := newNotificationProvisioner(logger)
+			err := nc.applyChanges(duplicateUidConfig)
+			So(err, ShouldNotBeNil)
+			So(len(fakeRepo.inserted), ShouldEqual, 0)
+		})
+
+		Convey("broken yaml should return error", func() {
+			reader := &configReader{}
+			_, err := reader.readConfig(brokenYaml)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("skip invalid directory", func() {
+			cfgProvider := &configReader{log: log.New("test logger")}
+			cfg, err := cfgProvider.readConfig("./invalid-directory")
+			if err != nil {
+				t.Fatalf("readConfig return an error %v", err)
+			}
+
+			So(len(cfg), ShouldEqual, 0)
+		})
+
+		Convey("empty directory should not return error", func() {
+			cfgProvider := &configReader{log: log.New("test logger")}
+			cfg, err := cfgProvider.readConfig(zeroNotificationsConfig)
+			So(err, ShouldBeNil)
+			So(len(cfg), ShouldEqual, 0)
+		})
+
+		Convey("can read all properties", func() {
+			cfgProvider := &configReader{log: log.New("test logger")}
+			cfg, err := cfgProvider.readConfig(twoNotificationsConfig)
+			if err != nil {
+				t.Fatalf("readConfig return an error %v", err)
+			}
+
+			So(len(cfg), ShouldEqual, 1)
+			So(cfg[0].ApiVersion, ShouldEqual, 1)
+
+			n := cfg[0].Notifications[0]
+			So(n.Name, ShouldEqual, "ops-slack")
+			So(n.Type, ShouldEqual, "slack")
+			So(n.Uid, ShouldEqual, "ops-slack")
+			So(n.OrgId, ShouldEqual, 2)
+			So(n.IsDefault, ShouldBeTrue)
+			So(n.SendReminder, ShouldBeTrue)
+			So(n.Frequency, ShouldEqual, "1h")
+			So(n.Editable, ShouldBeFalse)
+			So(n.Settings["recipient"], ShouldEqual, "#ops")
+
+			So(cfg[0].Notifications[1].OrgId, ShouldEqual, 1)
+		})
+	})
+}
+
+type fakeRepository struct {
+	inserted []*models.CreateAlertNotificationCommand
+	deleted  []*models.DeleteAlertNotificationCommand
+	updated  []*models.UpdateAlertNotificationCommand
+
+	loadAll []*models.AlertNotification
+}
+
+func mockDelete(cmd *models.DeleteAlertNotificationCommand) error {
+	fakeRepo.deleted = append(fakeRepo.deleted, cmd)
+	return nil
+}
+
+func mockUpdate(cmd *models.UpdateAlertNotificationCommand) error {
+	fakeRepo.updated = append(fakeRepo.updated, cmd)
+	return nil
+}
+
+func mockInsert(cmd *models.CreateAlertNotificationCommand) error {
+	fakeRepo.inserted = append(fakeRepo.inserted, cmd)
+	return nil
+}
+
+func mockGetByUid(cmd *models.GetAlertNotificationsWithUidQuery) error {
+	for _, v := range fakeRepo.loadAll {
+		if cmd.Uid == v.Uid && cmd.OrgId == v.OrgId {
+			cmd.Result = v
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func mockGetByName(cmd *models.GetAlertNotificationsQuery) error {
+	for _, v := range fakeRepo.loadAll {
+		if cmd.Name == v.Name && cmd.OrgId == v.OrgId {
+			cmd.Result = v
+			return nil
+		}
+	}
+
+	return nil
+}
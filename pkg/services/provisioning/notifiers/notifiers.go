@@ -0,0 +1,110 @@
+package notifiers
+
+import (
+	"errors"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+var (
+	ErrInvalidConfigMissingUid = errors.New("alert notification provisioning config is invalid. Every notifier requires a uid")
+)
+
+func Provision(configDirectory string) error {
+	nc := newNotificationProvisioner(log.New("provisioning.notifiers"))
+	return nc.applyChanges(configDirectory)
+}
+
+type NotificationProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+}
+
+func newNotificationProvisioner(log log.Logger) NotificationProvisioner {
+	return NotificationProvisioner{
+		log:         log,
+		cfgProvider: &configReader{log: log},
+	}
+}
+
+func (nc *NotificationProvisioner) apply(cfg *NotificationsAsConfig) error {
+	if err := nc.deleteNotifications(cfg.DeleteNotifications); err != nil {
+		return err
+	}
+
+	for _, n := range cfg.Notifications {
+		cmd := &models.GetAlertNotificationsWithUidQuery{OrgId: n.OrgId, Uid: n.Uid}
+		if err := bus.Dispatch(cmd); err != nil {
+			return err
+		}
+
+		// a notifier created in the UI with the same name is taken over by the
+		// configuration, since names have to be unique within an organization.
+		if cmd.Result == nil {
+			sameNameQuery := &models.GetAlertNotificationsQuery{OrgId: n.OrgId, Name: n.Name}
+			if err := bus.Dispatch(sameNameQuery); err != nil {
+				return err
+			}
+
+			if sameNameQuery.Result != nil {
+				nc.log.Info("adopting existing alert notification with the same name", "name", n.Name, "uid", n.Uid, "previousUid", sameNameQuery.Result.Uid)
+				cmd.Result = sameNameQuery.Result
+			}
+		}
+
+		if cmd.Result == nil {
+			nc.log.Info("inserting alert notification from configuration", "name", n.Name, "uid", n.Uid)
+			insertCmd := createInsertCommand(n)
+			if err := bus.Dispatch(insertCmd); err != nil {
+				return err
+			}
+		} else {
+			nc.log.Debug("updating alert notification from configuration", "name", n.Name, "uid", n.Uid)
+			updateCmd := createUpdateCommand(n, cmd.Result.Id)
+			if err := bus.Dispatch(updateCmd); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (nc *NotificationProvisioner) applyChanges(configPath string) error {
+	configs, err := nc.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := nc.apply(cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (nc *NotificationProvisioner) deleteNotifications(notificationsToDelete []*DeleteNotificationConfig) error {
+	for _, n := range notificationsToDelete {
+		getCmd := &models.GetAlertNotificationsWithUidQuery{OrgId: n.OrgId, Uid: n.Uid}
+		if err := bus.Dispatch(getCmd); err != nil {
+			return err
+		}
+
+		if getCmd.Result == nil {
+			continue
+		}
+
+		cmd := &models.DeleteAlertNotificationCommand{OrgId: n.OrgId, Id: getCmd.Result.Id}
+		if err := bus.Dispatch(cmd); err != nil {
+			return err
+		}
+
+		nc.log.Info("deleted alert notification based on configuration", "uid", n.Uid)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package notifiers
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+type ConfigVersion struct {
+	ApiVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+type NotificationsAsConfig struct {
+	ApiVersion int64
+
+	Notifications       []*NotificationFromConfig
+	DeleteNotifications []*DeleteNotificationConfig
+}
+
+type DeleteNotificationConfig struct {
+	OrgId int64
+	Uid   string
+}
+
+type NotificationFromConfig struct {
+	OrgId int64
+	Uid   string
+
+	Name                  string
+	Type                  string
+	IsDefault             bool
+	SendReminder          bool
+	DisableResolveMessage bool
+	Frequency             string
+	Settings              map[string]interface{}
+	Editable              bool
+}
+
+type NotificationsAsConfigV1 struct {
+	ConfigVersion
+
+	Notifications       []*NotificationFromConfigV1   `json:"notifiers" yaml:"notifiers"`
+	DeleteNotifications []*DeleteNotificationConfigV1 `json:"deleteNotifiers" yaml:"deleteNotifiers"`
+}
+
+type DeleteNotificationConfigV1 struct {
+	OrgId int64  `json:"orgId" yaml:"orgId"`
+	Uid   string `json:"uid" yaml:"uid"`
+}
+
+type NotificationFromConfigV1 struct {
+	OrgId                 int64                  `json:"orgId" yaml:"orgId"`
+	Uid                   string                 `json:"uid" yaml:"uid"`
+	Name                  string                 `json:"name" yaml:"name"`
+	Type                  string                 `json:"type" yaml:"type"`
+	IsDefault             bool                   `json:"isDefault" yaml:"isDefault"`
+	SendReminder          bool                   `json:"sendReminder" yaml:"sendReminder"`
+	DisableResolveMessage bool                   `json:"disableResolveMessage" yaml:"disableResolveMessage"`
+	Frequency             string                 `json:"frequency" yaml:"frequency"`
+	Settings              map[string]interface{} `json:"settings" yaml:"settings"`
+	Editable              bool                   `json:"editable" yaml:"editable"`
+}
+
+func (cfg *NotificationsAsConfigV1) mapToNotificationFromConfig(apiVersion int64) *NotificationsAsConfig {
+	r := &NotificationsAsConfig{}
+
+	r.ApiVersion = apiVersion
+
+	if cfg == nil {
+		return r
+	}
+
+	for _, n := range cfg.Notifications {
+		r.Notifications = append(r.Notifications, &NotificationFromConfig{
+			OrgId:                 n.OrgId,
+			Uid:                   n.Uid,
+			Name:                  n.Name,
+			Type:                  n.Type,
+			IsDefault:             n.IsDefault,
+			SendReminder:          n.SendReminder,
+			DisableResolveMessage: n.DisableResolveMessage,
+			Frequency:             n.Frequency,
+			Settings:              n.Settings,
+			Editable:              n.Editable,
+		})
+	}
+
+	for _, n := range cfg.DeleteNotifications {
+		r.DeleteNotifications = append(r.DeleteNotifications, &DeleteNotificationConfig{
+			OrgId: n.OrgId,
+			Uid:   n.Uid,
+		})
+	}
+
+	return r
+}
+
+func createSettings(n *NotificationFromConfig) *simplejson.Json {
+	settings := simplejson.New()
+	for k, v := range n.Settings {
+		settings.Set(k, v)
+	}
+
+	return settings
+}
+
+func createInsertCommand(n *NotificationFromConfig) *models.CreateAlertNotificationCommand {
+	return &models.CreateAlertNotificationCommand{
+		OrgId:                 n.OrgId,
+		Uid:                   n.Uid,
+		Name:                  n.Name,
+		Type:                  n.Type,
+		IsDefault:             n.IsDefault,
+		SendReminder:          n.SendReminder,
+		DisableResolveMessage: n.DisableResolveMessage,
+		Frequency:             n.Frequency,
+		Settings:              createSettings(n),
+		ReadOnly:              !n.Editable,
+	}
+}
+
+func createUpdateCommand(n *NotificationFromConfig, id int64) *models.UpdateAlertNotificationCommand {
+	return &models.UpdateAlertNotificationCommand{
+		Id:                    id,
+		OrgId:                 n.OrgId,
+		Uid:                   n.Uid,
+		Name:                  n.Name,
+		Type:                  n.Type,
+		IsDefault:             n.IsDefault,
+		SendReminder:          n.SendReminder,
+		DisableResolveMessage: n.DisableResolveMessage,
+		Frequency:             n.Frequency,
+		Settings:              createSettings(n),
+		ReadOnly:              !n.Editable,
+	}
+}
@@ -0,0 +1,187 @@
+package querycache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb"
+)
+
+func init() {
+	registry.RegisterService(&QueryCacheService{})
+}
+
+// QueryCacheService caches the responses of datasource queries so that
+// dashboards viewed by many users and alert rules on the same queries
+// don't query the datasource over and over.
+type QueryCacheService struct {
+	Cfg *setting.Cfg `inject:""`
+
+	storage cacheStorage
+	log     log.Logger
+}
+
+func (s *QueryCacheService) Init() error {
+	s.log = log.New("query-cache")
+
+	if !s.Cfg.QueryCacheEnabled {
+		return nil
+	}
+
+	storage, err := newCacheStorage(s.Cfg)
+	if err != nil {
+		return err
+	}
+	s.storage = storage
+
+	s.log.Info("Caching datasource queries", "backend", s.Cfg.QueryCacheBackend, "ttl", s.Cfg.QueryCacheTTL)
+	tsdb.SetQueryCache(s)
+	return nil
+}
+
+// HandleRequest returns the cached response of the request, or executes the
+// request and caches its response when it has no errors.
+func (s *QueryCacheService) HandleRequest(ctx context.Context, dsInfo *m.DataSource, req *tsdb.TsdbQuery, next tsdb.HandleRequestFunc) (*tsdb.Response, error) {
+	ttl := cacheTTL(dsInfo, s.Cfg.QueryCacheTTL)
+	if ttl <= 0 || dsInfo.Id == 0 {
+		return next(ctx, dsInfo, req)
+	}
+
+	key, err := cacheKey(dsInfo, req, ttl)
+	if err != nil {
+		return next(ctx, dsInfo, req)
+	}
+
+	data, found, err := s.storage.Get(key)
+	if err != nil {
+		s.log.Warn("Failed to read query cache", "error", err)
+	}
+
+	if found {
+		resp := &tsdb.Response{}
+		if err := json.Unmarshal(data, resp); err == nil {
+			metrics.M_DataSource_Query_Cache.WithLabelValues("hit").Inc()
+			return resp, nil
+		}
+		s.log.Warn("Failed to decode cached response", "error", err)
+	}
+
+	metrics.M_DataSource_Query_Cache.WithLabelValues("miss").Inc()
+
+	resp, err := next(ctx, dsInfo, req)
+	if err != nil || !cacheable(resp) {
+		return resp, err
+	}
+
+	data, err = json.Marshal(resp)
+	if err != nil {
+		s.log.Warn("Failed to encode response", "error", err)
+		return resp, nil
+	}
+
+	if err := s.storage.Set(key, data, ttl); err != nil {
+		s.log.Warn("Failed to write query cache", "error", err)
+	}
+
+	return resp, nil
+}
+
+// cacheTTL returns how long responses of the datasource are cached. The
+// queryCacheTTL in the json data overrides the default, 0 disables caching.
+func cacheTTL(dsInfo *m.DataSource, defaultTTL time.Duration) time.Duration {
+	if dsInfo.JsonData == nil {
+		return defaultTTL
+	}
+
+	raw := dsInfo.JsonData.Get("queryCacheTTL").MustString()
+	if raw == "" {
+		return defaultTTL
+	}
+
+	ttl, err := time.ParseDuration(raw)
+	if err != nil {
+		return defaultTTL
+	}
+
+	return ttl
+}
+
+type cacheKeyQuery struct {
+	RefId         string
+	Model         *simplejson.Json
+	MaxDataPoints int64
+	IntervalMs    int64
+}
+
+type cacheKeyRequest struct {
+	OrgId             int64
+	DatasourceId      int64
+	DatasourceVersion int
+	From              int64
+	To                int64
+	Queries           []cacheKeyQuery
+}
+
+// cacheKey identifies a request by the datasource and its version, the
+// queries and the time range aligned to the ttl. Requests within the same
+// interval share a response, changing the datasource invalidates it.
+func cacheKey(dsInfo *m.DataSource, req *tsdb.TsdbQuery, ttl time.Duration) (string, error) {
+	step := int64(ttl / time.Millisecond)
+	if step <= 0 {
+		step = 1
+	}
+
+	keyReq := cacheKeyRequest{
+		OrgId:             dsInfo.OrgId,
+		DatasourceId:      dsInfo.Id,
+		DatasourceVersion: dsInfo.Version,
+		Queries:           make([]cacheKeyQuery, 0, len(req.Queries)),
+	}
+
+	if req.TimeRange != nil {
+		from, to := req.TimeRange.GetFromAsMsEpoch(), req.TimeRange.GetToAsMsEpoch()
+		keyReq.From = from - from%step
+		keyReq.To = to - to%step
+	}
+
+	for _, query := range req.Queries {
+		keyReq.Queries = append(keyReq.Queries, cacheKeyQuery{
+			RefId:         query.RefId,
+			Model:         query.Model,
+			MaxDataPoints: query.MaxDataPoints,
+			IntervalMs:    query.IntervalMs,
+		})
+	}
+
+	// maps are encoded with sorted keys which normalizes the query models
+	data, err := json.Marshal(keyReq)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func cacheable(resp *tsdb.Response) bool {
+	if resp == nil {
+		return false
+	}
+
+	for _, result := range resp.Results {
+		if result.Error != nil || result.ErrorString != "" {
+			return false
+		}
+	}
+
+	return true
+}
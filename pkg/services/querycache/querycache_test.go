@@ -0,0 +1,137 @@
+package querycache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueryCache(t *testing.T) {
+	Convey("Given a query cache in memory", t, func() {
+		service := &QueryCacheService{Cfg: &setting.Cfg{QueryCacheTTL: time.Minute}}
+		So(service.Init(), ShouldBeNil)
+		service.storage = newMemoryStorage(time.Minute)
+
+		now := time.Unix(1500000000, 0)
+		dsInfo := &m.DataSource{Id: 1, OrgId: 1, Version: 1, Type: "graphite"}
+
+		newRequest := func(target string, now time.Time) *tsdb.TsdbQuery {
+			return &tsdb.TsdbQuery{
+				TimeRange: tsdb.NewFakeTimeRange("1h", "now", now),
+				Queries: []*tsdb.Query{
+					{RefId: "A", Model: simplejson.NewFromAny(map[string]interface{}{"target": target, "refId": "A"})},
+				},
+			}
+		}
+
+		queries := 0
+		next := func(ctx context.Context, dsInfo *m.DataSource, req *tsdb.TsdbQuery) (*tsdb.Response, error) {
+			queries++
+			return &tsdb.Response{
+				Results: map[string]*tsdb.QueryResult{
+					"A": {RefId: "A", Series: tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("cpu", tsdb.NewTimeSeriesPointsFromArgs(1, 1000))}},
+				},
+			}, nil
+		}
+
+		Convey("Answers the same request from the cache", func() {
+			_, err := service.HandleRequest(context.TODO(), dsInfo, newRequest("cpu", now), next)
+			So(err, ShouldBeNil)
+
+			resp, err := service.HandleRequest(context.TODO(), dsInfo, newRequest("cpu", now.Add(10*time.Second)), next)
+			So(err, ShouldBeNil)
+			So(queries, ShouldEqual, 1)
+			So(resp.Results["A"].Series[0].Name, ShouldEqual, "cpu")
+			So(resp.Results["A"].Series[0].Points[0][0].Float64, ShouldEqual, 1)
+		})
+
+		Convey("Queries the datasource for other queries", func() {
+			service.HandleRequest(context.TODO(), dsInfo, newRequest("cpu", now), next)
+			service.HandleRequest(context.TODO(), dsInfo, newRequest("memory", now), next)
+
+			So(queries, ShouldEqual, 2)
+		})
+
+		Convey("Queries the datasource when the ttl of the datasource is 0", func() {
+			dsInfo.JsonData = simplejson.NewFromAny(map[string]interface{}{"queryCacheTTL": "0s"})
+
+			service.HandleRequest(context.TODO(), dsInfo, newRequest("cpu", now), next)
+			service.HandleRequest(context.TODO(), dsInfo, newRequest("cpu", now), next)
+
+			So(queries, ShouldEqual, 2)
+		})
+
+		Convey("Does not cache responses with errors", func() {
+			failing := func(ctx context.Context, dsInfo *m.DataSource, req *tsdb.TsdbQuery) (*tsdb.Response, error) {
+				queries++
+				return &tsdb.Response{
+					Results: map[string]*tsdb.QueryResult{"A": {RefId: "A", Error: errors.New("timeout")}},
+				}, nil
+			}
+
+			service.HandleRequest(context.TODO(), dsInfo, newRequest("cpu", now), failing)
+			service.HandleRequest(context.TODO(), dsInfo, newRequest("cpu", now), failing)
+
+			So(queries, ShouldEqual, 2)
+		})
+	})
+
+	Convey("Cache keys", t, func() {
+		now := time.Unix(1500000000, 0)
+		dsInfo := &m.DataSource{Id: 1, OrgId: 1, Version: 1}
+
+		key := func(dsInfo *m.DataSource, model string, now time.Time) string {
+			json, err := simplejson.NewJson([]byte(model))
+			So(err, ShouldBeNil)
+
+			req := &tsdb.TsdbQuery{
+				TimeRange: tsdb.NewFakeTimeRange("1h", "now", now),
+				Queries:   []*tsdb.Query{{RefId: "A", Model: json}},
+			}
+
+			k, err := cacheKey(dsInfo, req, time.Minute)
+			So(err, ShouldBeNil)
+			return k
+		}
+
+		original := key(dsInfo, `{"target": "cpu", "hide": false}`, now)
+
+		Convey("Are the same for the same query in the same interval", func() {
+			So(key(dsInfo, `{"hide": false, "target": "cpu"}`, now.Add(59*time.Second)), ShouldEqual, original)
+		})
+
+		Convey("Change with the next interval", func() {
+			So(key(dsInfo, `{"target": "cpu", "hide": false}`, now.Add(time.Minute)), ShouldNotEqual, original)
+		})
+
+		Convey("Change with the query", func() {
+			So(key(dsInfo, `{"target": "memory", "hide": false}`, now), ShouldNotEqual, original)
+		})
+
+		Convey("Change with the version of the datasource", func() {
+			So(key(&m.DataSource{Id: 1, OrgId: 1, Version: 2}, `{"target": "cpu", "hide": false}`, now), ShouldNotEqual, original)
+		})
+	})
+
+	Convey("Cache ttl", t, func() {
+		So(cacheTTL(&m.DataSource{}, time.Minute), ShouldEqual, time.Minute)
+		So(cacheTTL(&m.DataSource{JsonData: simplejson.NewFromAny(map[string]interface{}{"queryCacheTTL": "5m"})}, time.Minute), ShouldEqual, 5*time.Minute)
+		So(cacheTTL(&m.DataSource{JsonData: simplejson.NewFromAny(map[string]interface{}{"queryCacheTTL": "0"})}, time.Minute), ShouldEqual, 0)
+	})
+
+	Convey("Redis connection strings", t, func() {
+		storage, err := newRedisStorage("addr=redis:6379,password=secret,db=2,pool_size=10")
+		So(err, ShouldBeNil)
+		So(storage.client, ShouldNotBeNil)
+
+		_, err = newRedisStorage("addr=redis:6379,timeout=5")
+		So(err, ShouldNotBeNil)
+	})
+}
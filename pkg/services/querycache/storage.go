@@ -0,0 +1,134 @@
+package querycache
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	gocache "github.com/patrickmn/go-cache"
+	redis "gopkg.in/redis.v2"
+)
+
+// cacheStorage stores encoded responses until their ttl expired.
+type cacheStorage interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, data []byte, ttl time.Duration) error
+}
+
+func newCacheStorage(cfg *setting.Cfg) (cacheStorage, error) {
+	switch cfg.QueryCacheBackend {
+	case "database":
+		return &databaseStorage{}, nil
+	case "redis":
+		return newRedisStorage(cfg.QueryCacheRedisConnStr)
+	default:
+		return newMemoryStorage(cfg.QueryCacheTTL), nil
+	}
+}
+
+// memoryStorage keeps responses in the memory of this server. Responses
+// are stored encoded so every hit gets its own copy.
+type memoryStorage struct {
+	cache *gocache.Cache
+}
+
+func newMemoryStorage(defaultTTL time.Duration) *memoryStorage {
+	return &memoryStorage{cache: gocache.New(defaultTTL, time.Minute*10)}
+}
+
+func (s *memoryStorage) Get(key string) ([]byte, bool, error) {
+	data, found := s.cache.Get(key)
+	if !found {
+		return nil, false, nil
+	}
+
+	return data.([]byte), true, nil
+}
+
+func (s *memoryStorage) Set(key string, data []byte, ttl time.Duration) error {
+	s.cache.Set(key, data, ttl)
+	return nil
+}
+
+// databaseStorage shares responses between servers through the Grafana
+// database, expired items are deleted by the cleanup service.
+type databaseStorage struct{}
+
+func (s *databaseStorage) Get(key string) ([]byte, bool, error) {
+	query := m.GetQueryCacheItemQuery{CacheKey: key}
+	if err := bus.Dispatch(&query); err != nil {
+		if err == m.ErrQueryCacheItemNotFound {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return []byte(query.Result.Data), true, nil
+}
+
+func (s *databaseStorage) Set(key string, data []byte, ttl time.Duration) error {
+	return bus.Dispatch(&m.SetQueryCacheItemCommand{
+		CacheKey: key,
+		Data:     string(data),
+		Expires:  time.Now().Add(ttl),
+	})
+}
+
+const redisKeyPrefix = "grafana:query-cache:"
+
+// redisStorage shares responses between servers through redis.
+type redisStorage struct {
+	client *redis.Client
+}
+
+// newRedisStorage connects to the redis server of the connection string,
+// e.g. addr=127.0.0.1:6379,password=secret,db=0,pool_size=100.
+func newRedisStorage(connStr string) (*redisStorage, error) {
+	opt := &redis.Options{Network: "tcp"}
+
+	for _, option := range strings.Split(connStr, ",") {
+		parts := strings.SplitN(strings.TrimSpace(option), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("query cache: invalid redis option '%s'", option)
+		}
+
+		var err error
+		switch parts[0] {
+		case "addr":
+			opt.Addr = parts[1]
+		case "password":
+			opt.Password = parts[1]
+		case "db":
+			opt.DB, err = strconv.ParseInt(parts[1], 10, 64)
+		case "pool_size":
+			opt.PoolSize, err = strconv.Atoi(parts[1])
+		default:
+			return nil, fmt.Errorf("query cache: unsupported redis option '%s'", parts[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("query cache: invalid redis option '%s': %v", option, err)
+		}
+	}
+
+	return &redisStorage{client: redis.NewClient(opt)}, nil
+}
+
+func (s *redisStorage) Get(key string) ([]byte, bool, error) {
+	data, err := s.client.Get(redisKeyPrefix + key).Result()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return []byte(data), true, nil
+}
+
+func (s *redisStorage) Set(key string, data []byte, ttl time.Duration) error {
+	return s.client.SetEx(redisKeyPrefix+key, ttl, string(data)).Err()
+}
@@ -22,17 +22,19 @@ func init() {
 	bus.AddHandler("sql", GetAlertStatesForDashboard)
 	bus.AddHandler("sql", PauseAlert)
 	bus.AddHandler("sql", PauseAllAlerts)
+	bus.AddHandler("sql", SaveAlertRule)
+	bus.AddHandler("sql", DeleteAlertRule)
 }
 
 func GetAlertById(query *m.GetAlertByIdQuery) error {
 	alert := m.Alert{}
 	has, err := x.ID(query.Id).Get(&alert)
-	if !has {
-		return fmt.Errorf("could not find alert")
-	}
 	if err != nil {
 		return err
 	}
+	if !has {
+		return m.ErrAlertNotFound
+	}
 
 	query.Result = &alert
 	return nil
@@ -64,6 +66,14 @@ func deleteAlertByIdInternal(alertId int64, reason string, sess *DBSession) erro
 		return err
 	}
 
+	if _, err := sess.Exec("DELETE FROM alert_instance WHERE alert_id = ?", alertId); err != nil {
+		return err
+	}
+
+	if _, err := sess.Exec("DELETE FROM alert_escalation_state WHERE alert_id = ?", alertId); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -74,6 +84,7 @@ func HandleAlertsQuery(query *m.GetAlertsQuery) error {
 		alert.id,
 		alert.dashboard_id,
 		alert.panel_id,
+		alert.folder_id,
 		alert.name,
 		alert.state,
 		alert.new_state_date,
@@ -83,9 +94,10 @@ func HandleAlertsQuery(query *m.GetAlertsQuery) error {
 		dashboard.uid as dashboard_uid,
 		dashboard.slug as dashboard_slug
 		FROM alert
-		INNER JOIN dashboard on dashboard.id = alert.dashboard_id `)
+		LEFT OUTER JOIN dashboard on dashboard.id = alert.dashboard_id `)
 
-	builder.Write(`WHERE alert.org_id = ?`, query.OrgId)
+	// standalone alert rules have no dashboard
+	builder.Write(`WHERE alert.org_id = ? AND (alert.dashboard_id = 0 OR dashboard.id IS NOT NULL)`, query.OrgId)
 
 	if len(strings.TrimSpace(query.Query)) > 0 {
 		builder.Write(" AND alert.name "+dialect.LikeStr()+" ?", "%"+query.Query+"%")
@@ -103,6 +115,18 @@ func HandleAlertsQuery(query *m.GetAlertsQuery) error {
 		builder.Write(` AND alert.panel_id = ?`, query.PanelId)
 	}
 
+	if query.Standalone {
+		builder.Write(` AND alert.dashboard_id = 0`)
+	}
+
+	if len(query.FolderIDs) > 0 {
+		builder.sql.WriteString(` AND alert.folder_id IN (?` + strings.Repeat(",?", len(query.FolderIDs)-1) + `) `)
+
+		for _, folderID := range query.FolderIDs {
+			builder.AddParams(folderID)
+		}
+	}
+
 	if len(query.State) > 0 && query.State[0] != "all" {
 		builder.Write(` AND (`)
 		for i, v := range query.State {
@@ -121,7 +145,12 @@ func HandleAlertsQuery(query *m.GetAlertsQuery) error {
 	}
 
 	if query.User.OrgRole != m.ROLE_ADMIN {
-		builder.writeDashboardPermissionFilter(query.User, m.PERMISSION_VIEW)
+		// standalone alert rules have the permissions of their folder,
+		// everyone can view the ones in the general folder
+		builder.Write(` AND ((alert.dashboard_id = 0 AND alert.folder_id = 0) OR
+			(CASE WHEN alert.dashboard_id <> 0 THEN alert.dashboard_id ELSE alert.folder_id END) IN (`)
+		builder.writePermittedDashboardIds(query.User, m.PERMISSION_VIEW)
+		builder.Write(`))`)
 	}
 
 	builder.Write(" ORDER BY name ASC")
@@ -156,6 +185,95 @@ func deleteAlertDefinition(dashboardId int64, sess *DBSession) error {
 	return nil
 }
 
+// deleteFolderAlertRules deletes the standalone alert rules in a folder.
+func deleteFolderAlertRules(folderId int64, sess *DBSession) error {
+	alerts := make([]*m.Alert, 0)
+	if err := sess.Where("folder_id = ? AND dashboard_id = 0", folderId).Find(&alerts); err != nil {
+		return err
+	}
+
+	for _, alert := range alerts {
+		if err := deleteAlertByIdInternal(alert.Id, "Folder deleted", sess); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getAlertRule returns a standalone alert rule of the org.
+func getAlertRule(id int64, orgId int64, sess *DBSession) (*m.Alert, error) {
+	alert := &m.Alert{}
+	has, err := sess.Where("id = ? AND org_id = ?", id, orgId).Get(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	if !has {
+		return nil, m.ErrAlertRuleNotFound
+	}
+
+	if !alert.IsStandalone() {
+		return nil, m.ErrAlertRuleIsDashboardAlert
+	}
+
+	return alert, nil
+}
+
+func SaveAlertRule(cmd *m.SaveAlertRuleCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		alert := &m.Alert{
+			Id:        cmd.Id,
+			OrgId:     cmd.OrgId,
+			FolderId:  cmd.FolderId,
+			Name:      cmd.Name,
+			Message:   cmd.Message,
+			Frequency: cmd.Frequency,
+			For:       cmd.For,
+			Settings:  cmd.Settings,
+			Updated:   timeNow(),
+		}
+
+		if cmd.Id == 0 {
+			alert.Created = timeNow()
+			alert.State = m.AlertStateUnknown
+			alert.NewStateDate = timeNow()
+
+			if _, err := sess.Insert(alert); err != nil {
+				return err
+			}
+
+			sqlog.Debug("Alert rule inserted", "name", alert.Name, "id", alert.Id)
+			cmd.Result = alert
+			return nil
+		}
+
+		if _, err := getAlertRule(cmd.Id, cmd.OrgId, sess); err != nil {
+			return err
+		}
+
+		if _, err := sess.ID(alert.Id).Cols("folder_id", "name", "message", "frequency", "for", "settings", "updated").Update(alert); err != nil {
+			return err
+		}
+
+		sqlog.Debug("Alert rule updated", "name", alert.Name, "id", alert.Id)
+
+		result, err := getAlertRule(cmd.Id, cmd.OrgId, sess)
+		cmd.Result = result
+		return err
+	})
+}
+
+func DeleteAlertRule(cmd *m.DeleteAlertRuleCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		if _, err := getAlertRule(cmd.Id, cmd.OrgId, sess); err != nil {
+			return err
+		}
+
+		return deleteAlertByIdInternal(cmd.Id, "Alert rule deleted", sess)
+	})
+}
+
 func SaveAlerts(cmd *m.SaveAlertsCommand) error {
 	return inTransaction(func(sess *DBSession) error {
 		existingAlerts, err := GetAlertsByDashboardId2(cmd.DashboardId, sess)
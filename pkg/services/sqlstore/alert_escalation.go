@@ -0,0 +1,130 @@
+package sqlstore
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", StartAlertEscalation)
+	bus.AddHandler("sql", AdvanceAlertEscalation)
+	bus.AddHandler("sql", DeleteAlertEscalation)
+	bus.AddHandler("sql", AcknowledgeAlert)
+	bus.AddHandler("sql", GetAlertEscalation)
+	bus.AddHandler("sql", GetAlertEscalations)
+	bus.AddHandler("sql", GetDueAlertEscalations)
+}
+
+func StartAlertEscalation(cmd *m.StartAlertEscalationCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		state := m.AlertEscalationState{}
+		has, err := sess.Where("org_id = ? AND alert_id = ? AND instance_id = ? AND notifier_id = ?",
+			cmd.OrgId, cmd.AlertId, cmd.InstanceId, cmd.NotifierId).Get(&state)
+		if err != nil {
+			return err
+		}
+
+		state.OrgId = cmd.OrgId
+		state.AlertId = cmd.AlertId
+		state.InstanceId = cmd.InstanceId
+		state.NotifierId = cmd.NotifierId
+		state.Step = -1
+		state.StartedAt = timeNow().Unix()
+		state.NextStepAt = cmd.NextStepAt
+		state.AcknowledgedAt = 0
+		state.AcknowledgedBy = 0
+		state.Comment = ""
+
+		if has {
+			_, err = sess.ID(state.Id).AllCols().Update(&state)
+		} else {
+			_, err = sess.Insert(&state)
+		}
+		if err != nil {
+			return err
+		}
+
+		cmd.Result = &state
+		return nil
+	})
+}
+
+func AdvanceAlertEscalation(cmd *m.AdvanceAlertEscalationCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec(`UPDATE alert_escalation_state SET step = ?, next_step_at = ?
+			WHERE id = ? AND step = ? AND acknowledged_at = 0`,
+			cmd.NextStep, cmd.NextStepAt, cmd.Id, cmd.Step)
+		if err != nil {
+			return err
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return m.ErrAlertEscalationConflict
+		}
+
+		return nil
+	})
+}
+
+func DeleteAlertEscalation(cmd *m.DeleteAlertEscalationCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		_, err := sess.Exec("DELETE FROM alert_escalation_state WHERE org_id = ? AND alert_id = ? AND instance_id = ? AND notifier_id = ?",
+			cmd.OrgId, cmd.AlertId, cmd.InstanceId, cmd.NotifierId)
+		return err
+	})
+}
+
+// AcknowledgeAlert stops the escalations of an alert or alert instance.
+func AcknowledgeAlert(cmd *m.AcknowledgeAlertCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec(`UPDATE alert_escalation_state SET acknowledged_at = ?, acknowledged_by = ?, comment = ?
+			WHERE org_id = ? AND alert_id = ? AND instance_id = ? AND acknowledged_at = 0`,
+			timeNow().Unix(), cmd.UserId, cmd.Comment, cmd.OrgId, cmd.AlertId, cmd.InstanceId)
+		if err != nil {
+			return err
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return m.ErrAlertEscalationNotFound
+		}
+
+		return nil
+	})
+}
+
+func GetAlertEscalation(query *m.GetAlertEscalationQuery) error {
+	state := m.AlertEscalationState{}
+	has, err := x.Where("org_id = ? AND alert_id = ? AND instance_id = ? AND notifier_id = ?",
+		query.OrgId, query.AlertId, query.InstanceId, query.NotifierId).Get(&state)
+	if err != nil {
+		return err
+	}
+
+	if !has {
+		return m.ErrAlertEscalationNotFound
+	}
+
+	query.Result = &state
+	return nil
+}
+
+func GetAlertEscalations(query *m.GetAlertEscalationsQuery) error {
+	states := make([]*m.AlertEscalationState, 0)
+	if err := x.Where("org_id = ? AND alert_id = ?", query.OrgId, query.AlertId).Asc("id").Find(&states); err != nil {
+		return err
+	}
+
+	query.Result = states
+	return nil
+}
+
+func GetDueAlertEscalations(query *m.GetDueAlertEscalationsQuery) error {
+	states := make([]*m.AlertEscalationState, 0)
+	err := x.Where("acknowledged_at = 0 AND next_step_at > 0 AND next_step_at <= ?", query.Now).Asc("next_step_at").Find(&states)
+	if err != nil {
+		return err
+	}
+
+	query.Result = states
+	return nil
+}
@@ -0,0 +1,80 @@
+package sqlstore
+
+import (
+	"testing"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertEscalationDataAccess(t *testing.T) {
+	Convey("Testing Alert escalation data access", t, func() {
+		InitTestDB(t)
+
+		start := &m.StartAlertEscalationCommand{OrgId: 1, AlertId: 1, InstanceId: 2, NotifierId: 3, NextStepAt: 100}
+		So(StartAlertEscalation(start), ShouldBeNil)
+		So(start.Result.Id, ShouldBeGreaterThan, 0)
+		So(start.Result.Step, ShouldEqual, -1)
+
+		Convey("Should return due escalations", func() {
+			query := &m.GetDueAlertEscalationsQuery{Now: 99}
+			So(GetDueAlertEscalations(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+
+			query = &m.GetDueAlertEscalationsQuery{Now: 100}
+			So(GetDueAlertEscalations(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+		})
+
+		Convey("Can advance escalation once", func() {
+			cmd := &m.AdvanceAlertEscalationCommand{Id: start.Result.Id, Step: -1, NextStep: 0, NextStepAt: 200}
+			So(AdvanceAlertEscalation(cmd), ShouldBeNil)
+			So(AdvanceAlertEscalation(cmd), ShouldEqual, m.ErrAlertEscalationConflict)
+
+			query := &m.GetAlertEscalationQuery{OrgId: 1, AlertId: 1, InstanceId: 2, NotifierId: 3}
+			So(GetAlertEscalation(query), ShouldBeNil)
+			So(query.Result.Step, ShouldEqual, 0)
+			So(query.Result.NextStepAt, ShouldEqual, 200)
+		})
+
+		Convey("Acknowledged escalations are not due and cannot advance", func() {
+			ack := &m.AcknowledgeAlertCommand{OrgId: 1, AlertId: 1, InstanceId: 2, UserId: 5, Comment: "on it"}
+			So(AcknowledgeAlert(ack), ShouldBeNil)
+			So(AcknowledgeAlert(ack), ShouldEqual, m.ErrAlertEscalationNotFound)
+
+			due := &m.GetDueAlertEscalationsQuery{Now: 1000}
+			So(GetDueAlertEscalations(due), ShouldBeNil)
+			So(len(due.Result), ShouldEqual, 0)
+
+			cmd := &m.AdvanceAlertEscalationCommand{Id: start.Result.Id, Step: -1, NextStep: 0}
+			So(AdvanceAlertEscalation(cmd), ShouldEqual, m.ErrAlertEscalationConflict)
+
+			query := &m.GetAlertEscalationsQuery{OrgId: 1, AlertId: 1}
+			So(GetAlertEscalations(query), ShouldBeNil)
+			So(query.Result[0].IsAcknowledged(), ShouldBeTrue)
+			So(query.Result[0].AcknowledgedBy, ShouldEqual, 5)
+			So(query.Result[0].Comment, ShouldEqual, "on it")
+
+			Convey("Restarting clears the acknowledgement", func() {
+				So(StartAlertEscalation(start), ShouldBeNil)
+
+				query := &m.GetAlertEscalationQuery{OrgId: 1, AlertId: 1, InstanceId: 2, NotifierId: 3}
+				So(GetAlertEscalation(query), ShouldBeNil)
+				So(query.Result.IsAcknowledged(), ShouldBeFalse)
+				So(query.Result.Id, ShouldEqual, start.Result.Id)
+			})
+		})
+
+		Convey("Cannot acknowledge alert of another org", func() {
+			ack := &m.AcknowledgeAlertCommand{OrgId: 2, AlertId: 1, InstanceId: 2}
+			So(AcknowledgeAlert(ack), ShouldEqual, m.ErrAlertEscalationNotFound)
+		})
+
+		Convey("Can delete escalation", func() {
+			So(DeleteAlertEscalation(&m.DeleteAlertEscalationCommand{OrgId: 1, AlertId: 1, InstanceId: 2, NotifierId: 3}), ShouldBeNil)
+
+			query := &m.GetAlertEscalationQuery{OrgId: 1, AlertId: 1, InstanceId: 2, NotifierId: 3}
+			So(GetAlertEscalation(query), ShouldEqual, m.ErrAlertEscalationNotFound)
+		})
+	})
+}
@@ -0,0 +1,68 @@
+package sqlstore
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", AlertHeartbeat)
+	bus.AddHandler("sql", DeleteAlertHeartbeat)
+	bus.AddHandler("sql", DeleteStaleAlertHeartbeats)
+	bus.AddHandler("sql", GetActiveAlertServers)
+}
+
+func AlertHeartbeat(cmd *m.AlertHeartbeatCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		now := timeNow().Unix()
+
+		// MySQL reports no affected rows for an update that does not change
+		// the row, so the existing heartbeat is looked up first
+		heartbeat := m.AlertHeartbeat{}
+		has, err := sess.Where("server_id = ?", cmd.ServerId).Get(&heartbeat)
+		if err != nil {
+			return err
+		}
+
+		if has {
+			_, err = sess.Exec("UPDATE alert_heartbeat SET updated = ? WHERE id = ?", now, heartbeat.Id)
+			return err
+		}
+
+		_, err = sess.Insert(&m.AlertHeartbeat{ServerId: cmd.ServerId, Created: now, Updated: now})
+		return err
+	})
+}
+
+func DeleteAlertHeartbeat(cmd *m.DeleteAlertHeartbeatCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		_, err := sess.Exec("DELETE FROM alert_heartbeat WHERE server_id = ?", cmd.ServerId)
+		return err
+	})
+}
+
+func DeleteStaleAlertHeartbeats(cmd *m.DeleteStaleAlertHeartbeatsCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_heartbeat WHERE updated < ?", cmd.OlderThan.Unix())
+		if err != nil {
+			return err
+		}
+
+		cmd.DeletedRows, err = res.RowsAffected()
+		return err
+	})
+}
+
+func GetActiveAlertServers(query *m.GetActiveAlertServersQuery) error {
+	heartbeats := make([]*m.AlertHeartbeat, 0)
+	if err := x.Where("updated >= ?", query.Since.Unix()).Asc("server_id").Find(&heartbeats); err != nil {
+		return err
+	}
+
+	query.Result = make([]string, 0, len(heartbeats))
+	for _, heartbeat := range heartbeats {
+		query.Result = append(query.Result, heartbeat.ServerId)
+	}
+
+	return nil
+}
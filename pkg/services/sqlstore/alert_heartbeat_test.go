@@ -0,0 +1,56 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertHeartbeatDataAccess(t *testing.T) {
+	Convey("Testing Alert heartbeat data access", t, func() {
+		InitTestDB(t)
+
+		now := time.Now()
+		timeNow = func() time.Time { return now.Add(-time.Minute) }
+		So(AlertHeartbeat(&m.AlertHeartbeatCommand{ServerId: "b"}), ShouldBeNil)
+		So(AlertHeartbeat(&m.AlertHeartbeatCommand{ServerId: "c"}), ShouldBeNil)
+
+		timeNow = func() time.Time { return now }
+		So(AlertHeartbeat(&m.AlertHeartbeatCommand{ServerId: "a"}), ShouldBeNil)
+		So(AlertHeartbeat(&m.AlertHeartbeatCommand{ServerId: "b"}), ShouldBeNil)
+
+		Reset(func() { timeNow = time.Now })
+
+		Convey("Should return servers with recent heartbeats", func() {
+			query := &m.GetActiveAlertServersQuery{Since: now.Add(-30 * time.Second)}
+			So(GetActiveAlertServers(query), ShouldBeNil)
+			So(query.Result, ShouldResemble, []string{"a", "b"})
+
+			query = &m.GetActiveAlertServersQuery{Since: now.Add(-2 * time.Minute)}
+			So(GetActiveAlertServers(query), ShouldBeNil)
+			So(query.Result, ShouldResemble, []string{"a", "b", "c"})
+		})
+
+		Convey("Should accept repeated heartbeats within the same second", func() {
+			So(AlertHeartbeat(&m.AlertHeartbeatCommand{ServerId: "a"}), ShouldBeNil)
+
+			query := &m.GetActiveAlertServersQuery{Since: now.Add(-30 * time.Second)}
+			So(GetActiveAlertServers(query), ShouldBeNil)
+			So(query.Result, ShouldResemble, []string{"a", "b"})
+		})
+
+		Convey("Should delete stale and stopped servers", func() {
+			cmd := &m.DeleteStaleAlertHeartbeatsCommand{OlderThan: now.Add(-30 * time.Second)}
+			So(DeleteStaleAlertHeartbeats(cmd), ShouldBeNil)
+			So(cmd.DeletedRows, ShouldEqual, 1)
+
+			So(DeleteAlertHeartbeat(&m.DeleteAlertHeartbeatCommand{ServerId: "a"}), ShouldBeNil)
+
+			query := &m.GetActiveAlertServersQuery{Since: now.Add(-time.Hour)}
+			So(GetActiveAlertServers(query), ShouldBeNil)
+			So(query.Result, ShouldResemble, []string{"b"})
+		})
+	})
+}
@@ -0,0 +1,102 @@
+package sqlstore
+
+import (
+	"strings"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", SaveAlertHistory)
+	bus.AddHandler("sql", GetAlertHistory)
+	bus.AddHandler("sql", DeleteExpiredAlertHistory)
+}
+
+func SaveAlertHistory(cmd *m.SaveAlertHistoryCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		epoch := cmd.Time
+		if epoch.IsZero() {
+			epoch = timeNow()
+		}
+
+		item := &m.AlertHistory{
+			OrgId:      cmd.OrgId,
+			AlertId:    cmd.AlertId,
+			InstanceId: cmd.InstanceId,
+			Labels:     cmd.Labels,
+			PrevState:  cmd.PrevState,
+			NewState:   cmd.NewState,
+			EvalData:   cmd.EvalData,
+			Error:      cmd.Error,
+			DurationMs: int64(cmd.Duration / 1e6),
+			Epoch:      epoch.UnixNano() / 1e6,
+		}
+
+		if _, err := sess.Insert(item); err != nil {
+			return err
+		}
+
+		cmd.Result = item
+		return nil
+	})
+}
+
+func GetAlertHistory(query *m.GetAlertHistoryQuery) error {
+	query.Result = m.AlertHistoryQueryResult{
+		History: make([]*m.AlertHistory, 0),
+	}
+
+	whereConditions := []string{"org_id = ?", "alert_id = ?"}
+	whereParams := []interface{}{query.OrgId, query.AlertId}
+
+	if len(query.State) > 0 {
+		whereConditions = append(whereConditions, "new_state IN (?"+strings.Repeat(",?", len(query.State)-1)+")")
+		for _, state := range query.State {
+			whereParams = append(whereParams, state)
+		}
+	}
+
+	if query.From > 0 {
+		whereConditions = append(whereConditions, "epoch >= ?")
+		whereParams = append(whereParams, query.From)
+	}
+
+	if query.To > 0 {
+		whereConditions = append(whereConditions, "epoch <= ?")
+		whereParams = append(whereParams, query.To)
+	}
+
+	sess := x.Table("alert_history").Where(strings.Join(whereConditions, " AND "), whereParams...)
+	if query.Limit > 0 {
+		page := query.Page
+		if page < 1 {
+			page = 1
+		}
+		sess.Limit(query.Limit, query.Limit*(page-1))
+	}
+
+	if err := sess.Desc("epoch").Desc("id").Find(&query.Result.History); err != nil {
+		return err
+	}
+
+	// get total
+	count, err := x.Table("alert_history").Where(strings.Join(whereConditions, " AND "), whereParams...).Count(&m.AlertHistory{})
+	query.Result.TotalCount = count
+
+	return err
+}
+
+// DeleteExpiredAlertHistory removes history entries older than the
+// configured retention.
+func DeleteExpiredAlertHistory(cmd *m.DeleteExpiredAlertHistoryCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_history WHERE epoch < ?", cmd.OlderThan.UnixNano()/1e6)
+		if err != nil {
+			return err
+		}
+
+		cmd.DeletedRows, _ = res.RowsAffected()
+		return nil
+	})
+}
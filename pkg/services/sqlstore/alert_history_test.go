@@ -0,0 +1,92 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertHistoryDataAccess(t *testing.T) {
+	Convey("Testing Alert history data access", t, func() {
+		InitTestDB(t)
+
+		now := time.Now()
+		save := func(alertId int64, prev, next m.AlertStateType, at time.Time) *m.SaveAlertHistoryCommand {
+			cmd := &m.SaveAlertHistoryCommand{
+				OrgId:     1,
+				AlertId:   alertId,
+				Labels:    map[string]string{"host": "server-1"},
+				PrevState: prev,
+				NewState:  next,
+				EvalData:  simplejson.NewFromAny(map[string]interface{}{"evalMatches": []interface{}{}}),
+				Error:     "",
+				Duration:  150 * time.Millisecond,
+				Time:      at,
+			}
+			So(SaveAlertHistory(cmd), ShouldBeNil)
+			return cmd
+		}
+
+		first := save(1, m.AlertStateOK, m.AlertStatePending, now.Add(-3*time.Hour))
+		save(1, m.AlertStatePending, m.AlertStateAlerting, now.Add(-2*time.Hour))
+		last := save(1, m.AlertStateAlerting, m.AlertStateOK, now.Add(-time.Hour))
+		save(2, m.AlertStateOK, m.AlertStateAlerting, now)
+
+		Convey("Should store duration and time in milliseconds", func() {
+			So(first.Result.DurationMs, ShouldEqual, 150)
+			So(first.Result.Epoch, ShouldEqual, now.Add(-3*time.Hour).UnixNano()/1e6)
+		})
+
+		Convey("Can get history of an alert newest first", func() {
+			query := &m.GetAlertHistoryQuery{OrgId: 1, AlertId: 1}
+			So(GetAlertHistory(query), ShouldBeNil)
+			So(query.Result.TotalCount, ShouldEqual, 3)
+			So(len(query.Result.History), ShouldEqual, 3)
+			So(query.Result.History[0].Id, ShouldEqual, last.Result.Id)
+			So(query.Result.History[0].Labels["host"], ShouldEqual, "server-1")
+		})
+
+		Convey("Can page history", func() {
+			query := &m.GetAlertHistoryQuery{OrgId: 1, AlertId: 1, Page: 2, Limit: 2}
+			So(GetAlertHistory(query), ShouldBeNil)
+			So(query.Result.TotalCount, ShouldEqual, 3)
+			So(len(query.Result.History), ShouldEqual, 1)
+			So(query.Result.History[0].Id, ShouldEqual, first.Result.Id)
+		})
+
+		Convey("Can filter history by state and time range", func() {
+			query := &m.GetAlertHistoryQuery{OrgId: 1, AlertId: 1, State: []string{"ok", "alerting"}}
+			So(GetAlertHistory(query), ShouldBeNil)
+			So(query.Result.TotalCount, ShouldEqual, 2)
+
+			query = &m.GetAlertHistoryQuery{
+				OrgId:   1,
+				AlertId: 1,
+				From:    now.Add(-150*time.Minute).UnixNano() / 1e6,
+				To:      now.Add(-90*time.Minute).UnixNano() / 1e6,
+			}
+			So(GetAlertHistory(query), ShouldBeNil)
+			So(len(query.Result.History), ShouldEqual, 1)
+			So(query.Result.History[0].NewState, ShouldEqual, m.AlertStateAlerting)
+		})
+
+		Convey("Cannot get history from another org", func() {
+			query := &m.GetAlertHistoryQuery{OrgId: 2, AlertId: 1}
+			So(GetAlertHistory(query), ShouldBeNil)
+			So(query.Result.TotalCount, ShouldEqual, 0)
+		})
+
+		Convey("Can delete expired history", func() {
+			cmd := &m.DeleteExpiredAlertHistoryCommand{OlderThan: now.Add(-90 * time.Minute)}
+			So(DeleteExpiredAlertHistory(cmd), ShouldBeNil)
+			So(cmd.DeletedRows, ShouldEqual, 2)
+
+			query := &m.GetAlertHistoryQuery{OrgId: 1, AlertId: 1}
+			So(GetAlertHistory(query), ShouldBeNil)
+			So(query.Result.TotalCount, ShouldEqual, 1)
+		})
+	})
+}
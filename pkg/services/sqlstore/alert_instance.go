@@ -0,0 +1,111 @@
+package sqlstore
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", GetAlertInstances)
+	bus.AddHandler("sql", SetAlertInstanceState)
+	bus.AddHandler("sql", DeleteAlertInstance)
+}
+
+func GetAlertInstances(query *m.GetAlertInstancesQuery) error {
+	sess := x.Where("org_id = ? AND alert_id = ?", query.OrgId, query.AlertId)
+
+	if query.Id > 0 {
+		sess.And("id = ?", query.Id)
+	}
+
+	if query.DashboardId > 0 {
+		sess.And("dashboard_id = ?", query.DashboardId)
+	}
+
+	if query.LabelsHash != "" {
+		sess.And("labels_hash = ?", query.LabelsHash)
+	}
+
+	if len(query.State) > 0 {
+		states := make([]interface{}, 0, len(query.State))
+		for _, state := range query.State {
+			states = append(states, state)
+		}
+		sess.In("state", states...)
+	}
+
+	instances := make([]*m.AlertInstance, 0)
+	if err := sess.Asc("id").Find(&instances); err != nil {
+		return err
+	}
+
+	query.Result = instances
+	return nil
+}
+
+func SetAlertInstanceState(cmd *m.SetAlertInstanceStateCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		labelsHash := m.AlertInstanceLabelsHash(cmd.Labels)
+		instance := m.AlertInstance{}
+
+		has, err := sess.Where("org_id = ? AND alert_id = ? AND dashboard_id = ? AND labels_hash = ?", cmd.OrgId, cmd.AlertId, cmd.DashboardId, labelsHash).Get(&instance)
+		if err != nil {
+			return err
+		}
+
+		if !has {
+			instance = m.AlertInstance{
+				OrgId:        cmd.OrgId,
+				AlertId:      cmd.AlertId,
+				DashboardId:  cmd.DashboardId,
+				Labels:       cmd.Labels,
+				LabelsHash:   labelsHash,
+				State:        cmd.State,
+				EvalData:     cmd.EvalData,
+				NewStateDate: timeNow(),
+				StateChanges: 1,
+				Created:      timeNow(),
+				Updated:      timeNow(),
+			}
+
+			if _, err := sess.Insert(&instance); err != nil {
+				return err
+			}
+
+			cmd.Result = instance
+			return nil
+		}
+
+		if instance.State == cmd.State {
+			return m.ErrRequiresNewState
+		}
+
+		instance.State = cmd.State
+		instance.StateChanges++
+		instance.NewStateDate = timeNow()
+		instance.Updated = timeNow()
+		instance.EvalData = cmd.EvalData
+
+		if _, err := sess.ID(instance.Id).Update(&instance); err != nil {
+			return err
+		}
+
+		cmd.Result = instance
+		return nil
+	})
+}
+
+func DeleteAlertInstance(cmd *m.DeleteAlertInstanceCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		if _, err := sess.Exec("DELETE FROM alert_notification_state WHERE org_id = ? AND alert_id = ? AND instance_id = ?", cmd.OrgId, cmd.AlertId, cmd.Id); err != nil {
+			return err
+		}
+
+		if _, err := sess.Exec("DELETE FROM alert_escalation_state WHERE org_id = ? AND alert_id = ? AND instance_id = ?", cmd.OrgId, cmd.AlertId, cmd.Id); err != nil {
+			return err
+		}
+
+		_, err := sess.Exec("DELETE FROM alert_instance WHERE org_id = ? AND alert_id = ? AND id = ?", cmd.OrgId, cmd.AlertId, cmd.Id)
+		return err
+	})
+}
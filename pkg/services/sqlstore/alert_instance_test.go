@@ -0,0 +1,126 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertInstanceDataAccess(t *testing.T) {
+	mockTimeNow()
+	defer resetTimeNow()
+
+	Convey("Testing Alert instance data access", t, func() {
+		InitTestDB(t)
+
+		labels := map[string]string{"host": "server-1", "dc": "eu"}
+		cmd := &m.SetAlertInstanceStateCommand{
+			AlertId:  1,
+			OrgId:    1,
+			Labels:   labels,
+			State:    m.AlertStateAlerting,
+			EvalData: simplejson.New(),
+		}
+
+		err := SetAlertInstanceState(cmd)
+		So(err, ShouldBeNil)
+		So(cmd.Result.Id, ShouldBeGreaterThan, 0)
+		So(cmd.Result.StateChanges, ShouldEqual, 1)
+
+		Convey("Can read instances", func() {
+			query := &m.GetAlertInstancesQuery{AlertId: 1, OrgId: 1}
+			err := GetAlertInstances(query)
+
+			So(err, ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.Result[0].Labels, ShouldResemble, labels)
+			So(query.Result[0].LabelsHash, ShouldEqual, m.AlertInstanceLabelsHash(labels))
+			So(query.Result[0].State, ShouldEqual, m.AlertStateAlerting)
+		})
+
+		Convey("Can read instance by id", func() {
+			query := &m.GetAlertInstancesQuery{Id: cmd.Result.Id, AlertId: 1, OrgId: 1}
+			So(GetAlertInstances(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+
+			query = &m.GetAlertInstancesQuery{Id: cmd.Result.Id + 1, AlertId: 1, OrgId: 1}
+			So(GetAlertInstances(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+		})
+
+		Convey("Can filter instances by labels", func() {
+			query := &m.GetAlertInstancesQuery{AlertId: 1, OrgId: 1, LabelsHash: m.AlertInstanceLabelsHash(labels)}
+			So(GetAlertInstances(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+
+			query = &m.GetAlertInstancesQuery{AlertId: 1, OrgId: 1, LabelsHash: m.AlertInstanceLabelsHash(map[string]string{"host": "server-2"})}
+			So(GetAlertInstances(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+		})
+
+		Convey("Can filter instances by state", func() {
+			query := &m.GetAlertInstancesQuery{AlertId: 1, OrgId: 1, State: []string{"ok"}}
+			err := GetAlertInstances(query)
+
+			So(err, ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+		})
+
+		Convey("Updates existing instance for same labels", func() {
+			update := &m.SetAlertInstanceStateCommand{AlertId: 1, OrgId: 1, Labels: map[string]string{"dc": "eu", "host": "server-1"}, State: m.AlertStateOK}
+			err := SetAlertInstanceState(update)
+
+			So(err, ShouldBeNil)
+			So(update.Result.Id, ShouldEqual, cmd.Result.Id)
+			So(update.Result.StateChanges, ShouldEqual, 2)
+
+			Convey("Requires a new state", func() {
+				err := SetAlertInstanceState(update)
+				So(err, ShouldEqual, m.ErrRequiresNewState)
+			})
+		})
+
+		Convey("Keeps instances of different dashboards apart", func() {
+			received := &m.SetAlertInstanceStateCommand{OrgId: 1, DashboardId: 2, Labels: labels, State: m.AlertStateAlerting}
+			So(SetAlertInstanceState(received), ShouldBeNil)
+
+			other := &m.SetAlertInstanceStateCommand{OrgId: 1, DashboardId: 3, Labels: labels, State: m.AlertStateAlerting}
+			So(SetAlertInstanceState(other), ShouldBeNil)
+			So(other.Result.Id, ShouldNotEqual, received.Result.Id)
+
+			query := &m.GetAlertInstancesQuery{OrgId: 1, DashboardId: 3}
+			So(GetAlertInstances(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.Result[0].Id, ShouldEqual, other.Result.Id)
+			So(query.Result[0].DashboardId, ShouldEqual, 3)
+		})
+
+		Convey("Can delete instance and its notification and escalation states", func() {
+			stateQuery := &m.GetOrCreateNotificationStateQuery{AlertId: 1, OrgId: 1, InstanceId: cmd.Result.Id, NotifierId: 1}
+			err := GetOrCreateAlertNotificationState(context.Background(), stateQuery)
+			So(err, ShouldBeNil)
+			So(stateQuery.Result.InstanceId, ShouldEqual, cmd.Result.Id)
+
+			start := &m.StartAlertEscalationCommand{OrgId: 1, AlertId: 1, InstanceId: cmd.Result.Id, NotifierId: 1, NextStepAt: 100}
+			So(StartAlertEscalation(start), ShouldBeNil)
+
+			err = DeleteAlertInstance(&m.DeleteAlertInstanceCommand{Id: cmd.Result.Id, AlertId: 1, OrgId: 1})
+			So(err, ShouldBeNil)
+
+			query := &m.GetAlertInstancesQuery{AlertId: 1, OrgId: 1}
+			So(GetAlertInstances(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+
+			count, err := x.Where("instance_id = ?", cmd.Result.Id).Count(&m.AlertNotificationState{})
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+
+			count, err = x.Where("instance_id = ?", cmd.Result.Id).Count(&m.AlertEscalationState{})
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+		})
+	})
+}
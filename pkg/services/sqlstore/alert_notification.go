@@ -10,10 +10,12 @@ import (
 
 	"github.com/grafana/grafana/pkg/bus"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func init() {
 	bus.AddHandler("sql", GetAlertNotifications)
+	bus.AddHandler("sql", GetAlertNotificationsWithUid)
 	bus.AddHandler("sql", CreateAlertNotificationCommand)
 	bus.AddHandler("sql", UpdateAlertNotification)
 	bus.AddHandler("sql", DeleteAlertNotification)
@@ -22,6 +24,7 @@ func init() {
 	bus.AddHandlerCtx("sql", GetOrCreateAlertNotificationState)
 	bus.AddHandlerCtx("sql", SetAlertNotificationStateToCompleteCommand)
 	bus.AddHandlerCtx("sql", SetAlertNotificationStateToPendingCommand)
+	bus.AddHandlerCtx("sql", SetAlertNotificationStateExternalId)
 }
 
 func DeleteAlertNotification(cmd *m.DeleteAlertNotificationCommand) error {
@@ -43,6 +46,10 @@ func GetAlertNotifications(query *m.GetAlertNotificationsQuery) error {
 	return getAlertNotificationInternal(query, newSession())
 }
 
+func GetAlertNotificationsWithUid(query *m.GetAlertNotificationsWithUidQuery) error {
+	return getAlertNotificationWithUidInternal(query, newSession())
+}
+
 func GetAllAlertNotifications(query *m.GetAllAlertNotificationsQuery) error {
 	results := make([]*m.AlertNotification, 0)
 	if err := x.Where("org_id = ?", query.OrgId).Find(&results); err != nil {
@@ -59,6 +66,7 @@ func GetAlertNotificationsToSend(query *m.GetAlertNotificationsToSendQuery) erro
 
 	sql.WriteString(`SELECT
 										alert_notification.id,
+										alert_notification.uid,
 										alert_notification.org_id,
 										alert_notification.name,
 										alert_notification.type,
@@ -68,7 +76,8 @@ func GetAlertNotificationsToSend(query *m.GetAlertNotificationsToSendQuery) erro
 										alert_notification.is_default,
 										alert_notification.disable_resolve_message,
 										alert_notification.send_reminder,
-										alert_notification.frequency
+										alert_notification.frequency,
+										alert_notification.read_only
 										FROM alert_notification
 	  							`)
 
@@ -100,6 +109,7 @@ func getAlertNotificationInternal(query *m.GetAlertNotificationsQuery, sess *DBS
 
 	sql.WriteString(`SELECT
 										alert_notification.id,
+										alert_notification.uid,
 										alert_notification.org_id,
 										alert_notification.name,
 										alert_notification.type,
@@ -109,7 +119,8 @@ func getAlertNotificationInternal(query *m.GetAlertNotificationsQuery, sess *DBS
 										alert_notification.is_default,
 										alert_notification.disable_resolve_message,
 										alert_notification.send_reminder,
-										alert_notification.frequency
+										alert_notification.frequency,
+										alert_notification.read_only
 										FROM alert_notification
 	  							`)
 
@@ -142,6 +153,21 @@ func getAlertNotificationInternal(query *m.GetAlertNotificationsQuery, sess *DBS
 	return nil
 }
 
+func getAlertNotificationWithUidInternal(query *m.GetAlertNotificationsWithUidQuery, sess *DBSession) error {
+	results := make([]*m.AlertNotification, 0)
+	if err := sess.Where("org_id = ? AND uid = ?", query.OrgId, query.Uid).Find(&results); err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		query.Result = nil
+	} else {
+		query.Result = results[0]
+	}
+
+	return nil
+}
+
 func CreateAlertNotificationCommand(cmd *m.CreateAlertNotificationCommand) error {
 	return inTransaction(func(sess *DBSession) error {
 		existingQuery := &m.GetAlertNotificationsQuery{OrgId: cmd.OrgId, Name: cmd.Name}
@@ -155,6 +181,19 @@ func CreateAlertNotificationCommand(cmd *m.CreateAlertNotificationCommand) error
 			return fmt.Errorf("Alert notification name %s already exists", cmd.Name)
 		}
 
+		if cmd.Uid == "" {
+			cmd.Uid = util.GenerateShortUid()
+		} else {
+			sameUidQuery := &m.GetAlertNotificationsWithUidQuery{OrgId: cmd.OrgId, Uid: cmd.Uid}
+			if err := getAlertNotificationWithUidInternal(sameUidQuery, sess); err != nil {
+				return err
+			}
+
+			if sameUidQuery.Result != nil {
+				return fmt.Errorf("Alert notification uid %s already exists", cmd.Uid)
+			}
+		}
+
 		var frequency time.Duration
 		if cmd.SendReminder {
 			if cmd.Frequency == "" {
@@ -168,6 +207,7 @@ func CreateAlertNotificationCommand(cmd *m.CreateAlertNotificationCommand) error
 		}
 
 		alertNotification := &m.AlertNotification{
+			Uid:                   cmd.Uid,
 			OrgId:                 cmd.OrgId,
 			Name:                  cmd.Name,
 			Type:                  cmd.Type,
@@ -178,6 +218,7 @@ func CreateAlertNotificationCommand(cmd *m.CreateAlertNotificationCommand) error
 			Created:               time.Now(),
 			Updated:               time.Now(),
 			IsDefault:             cmd.IsDefault,
+			ReadOnly:              cmd.ReadOnly,
 		}
 
 		if _, err = sess.MustCols("send_reminder").Insert(alertNotification); err != nil {
@@ -207,6 +248,19 @@ func UpdateAlertNotification(cmd *m.UpdateAlertNotificationCommand) error {
 			return fmt.Errorf("Alert notification name %s already exists", cmd.Name)
 		}
 
+		if cmd.Uid != "" && cmd.Uid != current.Uid {
+			sameUidQuery := &m.GetAlertNotificationsWithUidQuery{OrgId: cmd.OrgId, Uid: cmd.Uid}
+			if err := getAlertNotificationWithUidInternal(sameUidQuery, sess); err != nil {
+				return err
+			}
+
+			if sameUidQuery.Result != nil {
+				return fmt.Errorf("Alert notification uid %s already exists", cmd.Uid)
+			}
+
+			current.Uid = cmd.Uid
+		}
+
 		current.Updated = time.Now()
 		current.Settings = cmd.Settings
 		current.Name = cmd.Name
@@ -214,6 +268,7 @@ func UpdateAlertNotification(cmd *m.UpdateAlertNotificationCommand) error {
 		current.IsDefault = cmd.IsDefault
 		current.SendReminder = cmd.SendReminder
 		current.DisableResolveMessage = cmd.DisableResolveMessage
+		current.ReadOnly = cmd.ReadOnly
 
 		if current.SendReminder {
 			if cmd.Frequency == "" {
@@ -228,7 +283,7 @@ func UpdateAlertNotification(cmd *m.UpdateAlertNotificationCommand) error {
 			current.Frequency = frequency
 		}
 
-		sess.UseBool("is_default", "send_reminder", "disable_resolve_message")
+		sess.UseBool("is_default", "send_reminder", "disable_resolve_message", "read_only")
 
 		if affected, err := sess.ID(cmd.Id).Update(current); err != nil {
 			return err
@@ -273,6 +328,11 @@ func SetAlertNotificationStateToCompleteCommand(ctx context.Context, cmd *m.SetA
 func SetAlertNotificationStateToPendingCommand(ctx context.Context, cmd *m.SetAlertNotificationStateToPendingCommand) error {
 	return withDbSession(ctx, func(sess *DBSession) error {
 		newVersion := cmd.Version + 1
+		state := m.AlertNotificationStatePending
+		if cmd.Silenced {
+			state = m.AlertNotificationStateSilenced
+		}
+
 		sql := `UPDATE alert_notification_state SET
 			state = ?,
 			version = ?,
@@ -283,7 +343,7 @@ func SetAlertNotificationStateToPendingCommand(ctx context.Context, cmd *m.SetAl
 			(version = ? OR alert_rule_state_updated_version < ?)`
 
 		res, err := sess.Exec(sql,
-			m.AlertNotificationStatePending,
+			state,
 			newVersion,
 			timeNow().Unix(),
 			cmd.AlertRuleStateUpdatedVersion,
@@ -306,6 +366,13 @@ func SetAlertNotificationStateToPendingCommand(ctx context.Context, cmd *m.SetAl
 	})
 }
 
+func SetAlertNotificationStateExternalId(ctx context.Context, cmd *m.SetAlertNotificationStateExternalIdCommand) error {
+	return withDbSession(ctx, func(sess *DBSession) error {
+		_, err := sess.Exec("UPDATE alert_notification_state SET external_id = ? WHERE id = ?", cmd.ExternalId, cmd.Id)
+		return err
+	})
+}
+
 func GetOrCreateAlertNotificationState(ctx context.Context, cmd *m.GetOrCreateNotificationStateQuery) error {
 	return inTransactionCtx(ctx, func(sess *DBSession) error {
 		nj := &m.AlertNotificationState{}
@@ -325,6 +392,7 @@ func GetOrCreateAlertNotificationState(ctx context.Context, cmd *m.GetOrCreateNo
 		notificationState := &m.AlertNotificationState{
 			OrgId:      cmd.OrgId,
 			AlertId:    cmd.AlertId,
+			InstanceId: cmd.InstanceId,
 			NotifierId: cmd.NotifierId,
 			State:      m.AlertNotificationStateUnknown,
 			UpdatedAt:  timeNow().Unix(),
@@ -358,6 +426,7 @@ func getAlertNotificationState(sess *DBSession, cmd *m.GetOrCreateNotificationSt
 	return sess.
 		Where("alert_notification_state.org_id = ?", cmd.OrgId).
 		Where("alert_notification_state.alert_id = ?", cmd.AlertId).
+		Where("alert_notification_state.instance_id = ?", cmd.InstanceId).
 		Where("alert_notification_state.notifier_id = ?", cmd.NotifierId).
 		Get(nj)
 }
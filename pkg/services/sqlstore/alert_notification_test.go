@@ -40,6 +40,16 @@ func TestAlertNotificationSQLAccess(t *testing.T) {
 					So(query2.Result.UpdatedAt, ShouldEqual, now.Unix())
 				})
 
+				Convey("Setting the external id should not change the version", func() {
+					cmd := &models.SetAlertNotificationStateExternalIdCommand{Id: query.Result.Id, ExternalId: "OPS-42"}
+					So(SetAlertNotificationStateExternalId(context.Background(), cmd), ShouldBeNil)
+
+					query2 := &models.GetOrCreateNotificationStateQuery{AlertId: alertID, OrgId: orgID, NotifierId: notifierID}
+					So(GetOrCreateAlertNotificationState(context.Background(), query2), ShouldBeNil)
+					So(query2.Result.ExternalId, ShouldEqual, "OPS-42")
+					So(query2.Result.Version, ShouldEqual, query.Result.Version)
+				})
+
 				Convey("Update existing state to pending with correct version should update database", func() {
 					s := *query.Result
 
@@ -257,6 +267,53 @@ func TestAlertNotificationSQLAccess(t *testing.T) {
 				So(err, ShouldBeNil)
 				So(newCmd.Result.SendReminder, ShouldBeFalse)
 			})
+
+			Convey("Should generate uid", func() {
+				So(cmd.Result.Uid, ShouldNotBeEmpty)
+				So(cmd.Result.ReadOnly, ShouldBeFalse)
+			})
+		})
+
+		Convey("Can save Alert Notification with uid", func() {
+			cmd := &models.CreateAlertNotificationCommand{
+				Uid:      "ops-email",
+				Name:     "ops",
+				Type:     "email",
+				OrgId:    1,
+				Settings: simplejson.New(),
+				ReadOnly: true,
+			}
+
+			err := CreateAlertNotificationCommand(cmd)
+			So(err, ShouldBeNil)
+
+			Convey("Can get Alert Notification by uid", func() {
+				query := &models.GetAlertNotificationsWithUidQuery{OrgId: 1, Uid: "ops-email"}
+				err := GetAlertNotificationsWithUid(query)
+				So(err, ShouldBeNil)
+				So(query.Result.Id, ShouldEqual, cmd.Result.Id)
+				So(query.Result.ReadOnly, ShouldBeTrue)
+			})
+
+			Convey("Cannot save Alert Notification with the same uid", func() {
+				other := &models.CreateAlertNotificationCommand{Uid: "ops-email", Name: "other", Type: "email", OrgId: 1, Settings: simplejson.New()}
+				err := CreateAlertNotificationCommand(other)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Update without uid should keep uid", func() {
+				newCmd := &models.UpdateAlertNotificationCommand{
+					Id:       cmd.Result.Id,
+					Name:     "ops",
+					Type:     "email",
+					OrgId:    1,
+					Settings: simplejson.New(),
+				}
+				err := UpdateAlertNotification(newCmd)
+				So(err, ShouldBeNil)
+				So(newCmd.Result.Uid, ShouldEqual, "ops-email")
+				So(newCmd.Result.ReadOnly, ShouldBeFalse)
+			})
 		})
 
 		Convey("Can search using an array of ids", func() {
@@ -0,0 +1,107 @@
+package sqlstore
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", CreateAlertSilence)
+	bus.AddHandler("sql", DeleteAlertSilence)
+	bus.AddHandler("sql", DeleteExpiredAlertSilences)
+	bus.AddHandler("sql", GetAlertSilenceById)
+	bus.AddHandler("sql", GetAlertSilences)
+}
+
+func CreateAlertSilence(cmd *m.CreateAlertSilenceCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		startsAt := cmd.StartsAt
+		if startsAt.IsZero() {
+			startsAt = timeNow()
+		}
+
+		silence := &m.AlertSilence{
+			OrgId:     cmd.OrgId,
+			Comment:   cmd.Comment,
+			CreatedBy: cmd.CreatedBy,
+			StartsAt:  startsAt,
+			EndsAt:    cmd.EndsAt,
+			Matchers:  cmd.Matchers,
+			Created:   timeNow(),
+			Updated:   timeNow(),
+		}
+
+		if _, err := sess.Insert(silence); err != nil {
+			return err
+		}
+
+		cmd.Result = silence
+		return nil
+	})
+}
+
+func DeleteAlertSilence(cmd *m.DeleteAlertSilenceCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_silence WHERE id = ? AND org_id = ?", cmd.Id, cmd.OrgId)
+		if err != nil {
+			return err
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return m.ErrAlertSilenceNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteExpiredAlertSilences removes silences that have ended before the
+// retention period, so recently expired silences can still be listed.
+func DeleteExpiredAlertSilences(cmd *m.DeleteExpiredAlertSilencesCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_silence WHERE ends_at <= ?", cmd.EndedBefore)
+		if err != nil {
+			return err
+		}
+
+		cmd.DeletedRows, _ = res.RowsAffected()
+		return nil
+	})
+}
+
+func GetAlertSilenceById(query *m.GetAlertSilenceByIdQuery) error {
+	silence := m.AlertSilence{}
+	has, err := x.Where("id = ? AND org_id = ?", query.Id, query.OrgId).Get(&silence)
+	if err != nil {
+		return err
+	}
+
+	if !has {
+		return m.ErrAlertSilenceNotFound
+	}
+
+	query.Result = &silence
+	return nil
+}
+
+func GetAlertSilences(query *m.GetAlertSilencesQuery) error {
+	sess := x.Where("org_id = ?", query.OrgId)
+	now := timeNow()
+
+	switch query.State {
+	case "active":
+		sess.And("starts_at <= ? AND ends_at > ?", now, now)
+	case "pending":
+		sess.And("starts_at > ?", now)
+	case "expired":
+		sess.And("ends_at <= ?", now)
+	}
+
+	silences := make([]*m.AlertSilence, 0)
+	if err := sess.Asc("ends_at").Find(&silences); err != nil {
+		return err
+	}
+
+	query.Result = silences
+	return nil
+}
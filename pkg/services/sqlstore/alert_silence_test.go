@@ -0,0 +1,83 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertSilenceDataAccess(t *testing.T) {
+	Convey("Testing Alert silence data access", t, func() {
+		InitTestDB(t)
+
+		now := time.Now()
+		matchers := []*m.AlertSilenceMatcher{
+			{Type: m.AlertSilenceMatchRule, Value: "cpu.*", IsRegex: true},
+			{Type: m.AlertSilenceMatchLabel, Name: "host", Value: "server-1"},
+		}
+
+		active := &m.CreateAlertSilenceCommand{OrgId: 1, Comment: "maintenance", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Matchers: matchers}
+		pending := &m.CreateAlertSilenceCommand{OrgId: 1, StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour), Matchers: matchers}
+		expired := &m.CreateAlertSilenceCommand{OrgId: 1, StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour), Matchers: matchers}
+		otherOrg := &m.CreateAlertSilenceCommand{OrgId: 2, EndsAt: now.Add(time.Hour), Matchers: matchers}
+
+		for _, cmd := range []*m.CreateAlertSilenceCommand{active, pending, expired, otherOrg} {
+			So(CreateAlertSilence(cmd), ShouldBeNil)
+			So(cmd.Result.Id, ShouldBeGreaterThan, 0)
+		}
+
+		Convey("Should default start time to now", func() {
+			So(otherOrg.Result.StartsAt.IsZero(), ShouldBeFalse)
+		})
+
+		Convey("Can get silence by id", func() {
+			query := &m.GetAlertSilenceByIdQuery{Id: active.Result.Id, OrgId: 1}
+			So(GetAlertSilenceById(query), ShouldBeNil)
+			So(query.Result.Comment, ShouldEqual, "maintenance")
+			So(query.Result.Matchers, ShouldResemble, matchers)
+		})
+
+		Convey("Cannot get silence from another org", func() {
+			query := &m.GetAlertSilenceByIdQuery{Id: active.Result.Id, OrgId: 2}
+			So(GetAlertSilenceById(query), ShouldEqual, m.ErrAlertSilenceNotFound)
+		})
+
+		Convey("Can filter silences by state", func() {
+			for state, expected := range map[string]int64{"active": active.Result.Id, "pending": pending.Result.Id, "expired": expired.Result.Id} {
+				query := &m.GetAlertSilencesQuery{OrgId: 1, State: state}
+				So(GetAlertSilences(query), ShouldBeNil)
+				So(len(query.Result), ShouldEqual, 1)
+				So(query.Result[0].Id, ShouldEqual, expected)
+			}
+
+			query := &m.GetAlertSilencesQuery{OrgId: 1}
+			So(GetAlertSilences(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 3)
+		})
+
+		Convey("Can delete silence", func() {
+			So(DeleteAlertSilence(&m.DeleteAlertSilenceCommand{Id: active.Result.Id, OrgId: 1}), ShouldBeNil)
+			So(DeleteAlertSilence(&m.DeleteAlertSilenceCommand{Id: active.Result.Id, OrgId: 1}), ShouldEqual, m.ErrAlertSilenceNotFound)
+		})
+
+		Convey("Can delete expired silences", func() {
+			cmd := &m.DeleteExpiredAlertSilencesCommand{EndedBefore: now.Add(-2 * time.Hour)}
+			So(DeleteExpiredAlertSilences(cmd), ShouldBeNil)
+			So(cmd.DeletedRows, ShouldEqual, 0)
+
+			expiredQuery := &m.GetAlertSilencesQuery{OrgId: 1, State: "expired"}
+			So(GetAlertSilences(expiredQuery), ShouldBeNil)
+			So(len(expiredQuery.Result), ShouldEqual, 1)
+
+			cmd = &m.DeleteExpiredAlertSilencesCommand{EndedBefore: now}
+			So(DeleteExpiredAlertSilences(cmd), ShouldBeNil)
+			So(cmd.DeletedRows, ShouldEqual, 1)
+
+			query := &m.GetAlertSilencesQuery{OrgId: 1}
+			So(GetAlertSilences(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 2)
+		})
+	})
+}
@@ -290,6 +290,109 @@ func TestPausingAlerts(t *testing.T) {
 		})
 	})
 }
+
+func TestAlertRuleDataAccess(t *testing.T) {
+	Convey("Testing standalone alert rule data access", t, func() {
+		InitTestDB(t)
+
+		folder := insertTestDashboard("alert rule folder", 1, 0, true)
+		testDash := insertTestDashboard("dashboard with alerts", 1, 0, false)
+		_, err := insertTestAlert("dashboard alert", "message", 1, testDash.Id, simplejson.New())
+		So(err, ShouldBeNil)
+
+		cmd := m.SaveAlertRuleCommand{
+			OrgId:     1,
+			FolderId:  folder.Id,
+			Name:      "standalone rule",
+			Message:   "rule message",
+			Frequency: 60,
+			For:       5 * time.Minute,
+			Settings:  simplejson.NewFromAny(map[string]interface{}{"name": "standalone rule"}),
+		}
+		err = SaveAlertRule(&cmd)
+
+		Convey("Can create an alert rule", func() {
+			So(err, ShouldBeNil)
+			So(cmd.Result.Id, ShouldBeGreaterThan, 0)
+			So(cmd.Result.State, ShouldEqual, m.AlertStateUnknown)
+
+			alert, _ := getAlertById(cmd.Result.Id)
+			So(alert.IsStandalone(), ShouldBeTrue)
+			So(alert.FolderId, ShouldEqual, folder.Id)
+			So(alert.For, ShouldEqual, 5*time.Minute)
+		})
+
+		Convey("Can update an alert rule", func() {
+			updateCmd := m.SaveAlertRuleCommand{
+				Id:        cmd.Result.Id,
+				OrgId:     1,
+				Name:      "renamed rule",
+				Frequency: 10,
+				Settings:  simplejson.New(),
+			}
+			So(SaveAlertRule(&updateCmd), ShouldBeNil)
+			So(updateCmd.Result.Name, ShouldEqual, "renamed rule")
+			So(updateCmd.Result.FolderId, ShouldEqual, 0)
+			So(updateCmd.Result.Frequency, ShouldEqual, 10)
+			So(updateCmd.Result.State, ShouldEqual, m.AlertStateUnknown)
+		})
+
+		Convey("Cannot update an alert rule of another org", func() {
+			updateCmd := m.SaveAlertRuleCommand{Id: cmd.Result.Id, OrgId: 2, Name: "renamed rule", Settings: simplejson.New()}
+			So(SaveAlertRule(&updateCmd), ShouldEqual, m.ErrAlertRuleNotFound)
+		})
+
+		Convey("Cannot update or delete a dashboard alert as alert rule", func() {
+			alertQuery := m.GetAlertsQuery{DashboardIDs: []int64{testDash.Id}, OrgId: 1, User: &m.SignedInUser{OrgRole: m.ROLE_ADMIN}}
+			So(HandleAlertsQuery(&alertQuery), ShouldBeNil)
+
+			updateCmd := m.SaveAlertRuleCommand{Id: alertQuery.Result[0].Id, OrgId: 1, Name: "renamed rule", Settings: simplejson.New()}
+			So(SaveAlertRule(&updateCmd), ShouldEqual, m.ErrAlertRuleIsDashboardAlert)
+			So(DeleteAlertRule(&m.DeleteAlertRuleCommand{Id: alertQuery.Result[0].Id, OrgId: 1}), ShouldEqual, m.ErrAlertRuleIsDashboardAlert)
+		})
+
+		Convey("Can list alert rules together with dashboard alerts", func() {
+			alertQuery := m.GetAlertsQuery{OrgId: 1, User: &m.SignedInUser{OrgRole: m.ROLE_ADMIN}}
+			So(HandleAlertsQuery(&alertQuery), ShouldBeNil)
+			So(alertQuery.Result, ShouldHaveLength, 2)
+		})
+
+		Convey("Can list only alert rules in a folder", func() {
+			alertQuery := m.GetAlertsQuery{OrgId: 1, Standalone: true, FolderIDs: []int64{folder.Id}, User: &m.SignedInUser{OrgRole: m.ROLE_ADMIN}}
+			So(HandleAlertsQuery(&alertQuery), ShouldBeNil)
+			So(alertQuery.Result, ShouldHaveLength, 1)
+			So(alertQuery.Result[0].Name, ShouldEqual, "standalone rule")
+			So(alertQuery.Result[0].FolderId, ShouldEqual, folder.Id)
+		})
+
+		Convey("Viewer without access to the folder cannot list the alert rule", func() {
+			viewer := createUser("viewer", "Viewer", false)
+			testHelperUpdateDashboardAcl(folder.Id, m.DashboardAcl{DashboardId: folder.Id, OrgId: 1, UserId: 999, Permission: m.PERMISSION_EDIT})
+
+			alertQuery := m.GetAlertsQuery{OrgId: 1, User: &m.SignedInUser{UserId: viewer.Id, OrgId: 1, OrgRole: m.ROLE_VIEWER}}
+			So(HandleAlertsQuery(&alertQuery), ShouldBeNil)
+			So(alertQuery.Result, ShouldHaveLength, 1)
+			So(alertQuery.Result[0].Name, ShouldEqual, "dashboard alert")
+		})
+
+		Convey("Can delete an alert rule", func() {
+			start := &m.StartAlertEscalationCommand{OrgId: 1, AlertId: cmd.Result.Id, NotifierId: 3, NextStepAt: 100}
+			So(StartAlertEscalation(start), ShouldBeNil)
+
+			So(DeleteAlertRule(&m.DeleteAlertRuleCommand{Id: cmd.Result.Id, OrgId: 1}), ShouldBeNil)
+			So(GetAlertById(&m.GetAlertByIdQuery{Id: cmd.Result.Id}), ShouldEqual, m.ErrAlertNotFound)
+
+			escalationQuery := &m.GetAlertEscalationQuery{OrgId: 1, AlertId: cmd.Result.Id, NotifierId: 3}
+			So(GetAlertEscalation(escalationQuery), ShouldEqual, m.ErrAlertEscalationNotFound)
+		})
+
+		Convey("Deleting the folder removes its alert rules", func() {
+			So(DeleteDashboard(&m.DeleteDashboardCommand{Id: folder.Id, OrgId: 1}), ShouldBeNil)
+			So(GetAlertById(&m.GetAlertByIdQuery{Id: cmd.Result.Id}), ShouldNotBeNil)
+		})
+	})
+}
+
 func pauseAlert(orgId int64, alertId int64, pauseState bool) (int64, error) {
 	cmd := &m.PauseAlertCommand{
 		OrgId:    orgId,
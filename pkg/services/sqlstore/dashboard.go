@@ -341,6 +341,10 @@ func DeleteDashboard(cmd *m.DeleteDashboardCommand) error {
 					return nil
 				}
 			}
+
+			if err := deleteFolderAlertRules(dashboard.Id, sess); err != nil {
+				return err
+			}
 		}
 
 		if err := deleteAlertDefinition(dashboard.Id, sess); err != nil {
@@ -137,4 +137,170 @@ func addAlertMigrations(mg *Migrator) {
 	mg.AddMigration("Add for to alert table", NewAddColumnMigration(alertV1, &Column{
 		Name: "for", Type: DB_BigInt, Nullable: true,
 	}))
+
+	alert_instance := Table{
+		Name: "alert_instance",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "labels", Type: DB_Text, Nullable: false},
+			{Name: "labels_hash", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "state", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "eval_data", Type: DB_Text, Nullable: true},
+			{Name: "new_state_date", Type: DB_DateTime, Nullable: false},
+			{Name: "state_changes", Type: DB_Int, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "alert_id", "labels_hash"}, Type: UniqueIndex},
+			{Cols: []string{"alert_id"}, Type: IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_instance table v1", NewAddTableMigration(alert_instance))
+	mg.AddMigration("add unique index alert_instance org_id & alert_id & labels_hash", NewAddIndexMigration(alert_instance, alert_instance.Indices[0]))
+	mg.AddMigration("add index alert_instance alert_id", NewAddIndexMigration(alert_instance, alert_instance.Indices[1]))
+
+	mg.AddMigration("Add column instance_id to alert_notification_state", NewAddColumnMigration(alert_notification_state, &Column{
+		Name: "instance_id", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("drop index alert_notification_state org_id & alert_id & notifier_id", NewDropIndexMigration(alert_notification_state, alert_notification_state.Indices[0]))
+	mg.AddMigration("add index alert_notification_state org_id & alert_id & instance_id & notifier_id", NewAddIndexMigration(alert_notification_state, &Index{
+		Cols: []string{"org_id", "alert_id", "instance_id", "notifier_id"}, Type: UniqueIndex,
+	}))
+
+	mg.AddMigration("Add column uid in alert_notification", NewAddColumnMigration(alert_notification, &Column{
+		Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: true,
+	}))
+
+	mg.AddMigration("Update uid column values in alert_notification", NewRawSqlMigration("").
+		Sqlite("UPDATE alert_notification SET uid=printf('%09d',id) WHERE uid IS NULL;").
+		Postgres("UPDATE alert_notification SET uid=lpad('' || id,9,'0') WHERE uid IS NULL;").
+		Mysql("UPDATE alert_notification SET uid=lpad(id,9,'0') WHERE uid IS NULL;"))
+
+	mg.AddMigration("Add unique index alert_notification_org_id_uid", NewAddIndexMigration(alert_notification, &Index{
+		Cols: []string{"org_id", "uid"}, Type: UniqueIndex,
+	}))
+
+	mg.AddMigration("Add column read_only in alert_notification", NewAddColumnMigration(alert_notification, &Column{
+		Name: "read_only", Type: DB_Bool, Nullable: true, Default: "0",
+	}))
+
+	alert_silence := Table{
+		Name: "alert_silence",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "comment", Type: DB_Text, Nullable: true},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "starts_at", Type: DB_DateTime, Nullable: false},
+			{Name: "ends_at", Type: DB_DateTime, Nullable: false},
+			{Name: "matchers", Type: DB_Text, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "ends_at"}, Type: IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_silence table v1", NewAddTableMigration(alert_silence))
+	mg.AddMigration("add index alert_silence org_id & ends_at", NewAddIndexMigration(alert_silence, alert_silence.Indices[0]))
+
+	alert_history := Table{
+		Name: "alert_history",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "instance_id", Type: DB_BigInt, Nullable: false},
+			{Name: "labels", Type: DB_Text, Nullable: true},
+			{Name: "prev_state", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "new_state", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "eval_data", Type: DB_Text, Nullable: true},
+			{Name: "error", Type: DB_Text, Nullable: true},
+			{Name: "duration_ms", Type: DB_BigInt, Nullable: false},
+			{Name: "epoch", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "alert_id", "epoch"}, Type: IndexType},
+			{Cols: []string{"epoch"}, Type: IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_history table v1", NewAddTableMigration(alert_history))
+	mg.AddMigration("add index alert_history org_id & alert_id & epoch", NewAddIndexMigration(alert_history, alert_history.Indices[0]))
+	mg.AddMigration("add index alert_history epoch", NewAddIndexMigration(alert_history, alert_history.Indices[1]))
+
+	alert_escalation_state := Table{
+		Name: "alert_escalation_state",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "instance_id", Type: DB_BigInt, Nullable: false},
+			{Name: "notifier_id", Type: DB_BigInt, Nullable: false},
+			{Name: "step", Type: DB_Int, Nullable: false},
+			{Name: "started_at", Type: DB_BigInt, Nullable: false},
+			{Name: "next_step_at", Type: DB_BigInt, Nullable: false},
+			{Name: "acknowledged_at", Type: DB_BigInt, Nullable: false},
+			{Name: "acknowledged_by", Type: DB_BigInt, Nullable: false},
+			{Name: "comment", Type: DB_Text, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "alert_id", "instance_id", "notifier_id"}, Type: UniqueIndex},
+			{Cols: []string{"next_step_at"}, Type: IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_escalation_state table v1", NewAddTableMigration(alert_escalation_state))
+	mg.AddMigration("add unique index alert_escalation_state org_id & alert_id & instance_id & notifier_id", NewAddIndexMigration(alert_escalation_state, alert_escalation_state.Indices[0]))
+	mg.AddMigration("add index alert_escalation_state next_step_at", NewAddIndexMigration(alert_escalation_state, alert_escalation_state.Indices[1]))
+
+	notification_delivery := Table{
+		Name: "notification_delivery",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "notifier_id", Type: DB_BigInt, Nullable: false},
+			{Name: "notifier_type", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "state", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "attempts", Type: DB_Int, Nullable: false},
+			{Name: "last_error", Type: DB_Text, Nullable: true},
+			{Name: "request", Type: DB_Text, Nullable: true},
+			{Name: "next_attempt_at", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_BigInt, Nullable: false},
+			{Name: "updated", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "notifier_id", "state"}, Type: IndexType},
+			{Cols: []string{"state", "next_attempt_at"}, Type: IndexType},
+		},
+	}
+
+	mg.AddMigration("create notification_delivery table v1", NewAddTableMigration(notification_delivery))
+	mg.AddMigration("add index notification_delivery org_id & notifier_id & state", NewAddIndexMigration(notification_delivery, notification_delivery.Indices[0]))
+	mg.AddMigration("add index notification_delivery state & next_attempt_at", NewAddIndexMigration(notification_delivery, notification_delivery.Indices[1]))
+
+	mg.AddMigration("Add column external_id to alert_notification_state", NewAddColumnMigration(alert_notification_state, &Column{
+		Name: "external_id", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+
+	mg.AddMigration("Add column folder_id to alert", NewAddColumnMigration(alertV1, &Column{
+		Name: "folder_id", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add index alert org_id & folder_id", NewAddIndexMigration(alertV1, &Index{
+		Cols: []string{"org_id", "folder_id"}, Type: IndexType,
+	}))
+
+	mg.AddMigration("Add column dashboard_id to alert_instance", NewAddColumnMigration(alert_instance, &Column{
+		Name: "dashboard_id", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("drop index alert_instance org_id & alert_id & labels_hash", NewDropIndexMigration(alert_instance, alert_instance.Indices[0]))
+	mg.AddMigration("add unique index alert_instance org_id & alert_id & dashboard_id & labels_hash", NewAddIndexMigration(alert_instance, &Index{
+		Cols: []string{"org_id", "alert_id", "dashboard_id", "labels_hash"}, Type: UniqueIndex,
+	}))
 }
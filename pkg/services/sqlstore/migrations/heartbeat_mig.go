@@ -1,18 +1,22 @@
 package migrations
 
-// // create table
-// 	mg.AddMigration("create alert table v1", NewAddTableMigration(alertV1))
-//
-// 	alert_heartbeat := Table{
-// 		Name: "alert_heartbeat",
-// 		Columns: []*Column{
-// 			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
-// 			{Name: "server_id", Type: DB_NVarchar, Length: 50, Nullable: false},
-// 			{Name: "created", Type: DB_DateTime, Nullable: false},
-// 			{Name: "updated", Type: DB_DateTime, Nullable: false},
-// 		},
-// 	}
-//
-// 	mg.AddMigration("create alert_heartbeat table v1", NewAddTableMigration(alert_heartbeat))
-//
-//
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addAlertHeartbeatMigrations(mg *Migrator) {
+	alertHeartbeat := Table{
+		Name: "alert_heartbeat",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "server_id", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "created", Type: DB_BigInt, Nullable: false},
+			{Name: "updated", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"server_id"}, Type: UniqueIndex},
+			{Cols: []string{"updated"}},
+		},
+	}
+
+	mg.AddMigration("create alert_heartbeat table v1", NewAddTableMigration(alertHeartbeat))
+	addTableIndicesMigrations(mg, "v1", alertHeartbeat)
+}
@@ -32,6 +32,8 @@ func AddMigrations(mg *Migrator) {
 	addLoginAttemptMigrations(mg)
 	addUserAuthMigrations(mg)
 	addServerlockMigrations(mg)
+	addAlertHeartbeatMigrations(mg)
+	addQueryCacheMigrations(mg)
 }
 
 func addMigrationLogMigrations(mg *Migrator) {
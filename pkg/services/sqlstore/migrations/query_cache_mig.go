@@ -0,0 +1,22 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addQueryCacheMigrations(mg *Migrator) {
+	queryCache := Table{
+		Name: "query_cache_item",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "cache_key", Type: DB_NVarchar, Length: 100, Nullable: false},
+			{Name: "data", Type: DB_MediumText, Nullable: false},
+			{Name: "expires", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"cache_key"}, Type: UniqueIndex},
+			{Cols: []string{"expires"}},
+		},
+	}
+
+	mg.AddMigration("create query_cache_item table v1", NewAddTableMigration(queryCache))
+	addTableIndicesMigrations(mg, "v1", queryCache)
+}
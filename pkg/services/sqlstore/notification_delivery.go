@@ -0,0 +1,183 @@
+package sqlstore
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/securejsondata"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", CreateNotificationDelivery)
+	bus.AddHandler("sql", ClaimNotificationDelivery)
+	bus.AddHandler("sql", SetNotificationDeliveryState)
+	bus.AddHandler("sql", RetryNotificationDelivery)
+	bus.AddHandler("sql", DeleteNotificationDelivery)
+	bus.AddHandler("sql", DeleteExpiredNotificationDeliveries)
+	bus.AddHandler("sql", GetNotificationDelivery)
+	bus.AddHandler("sql", GetNotificationDeliveries)
+	bus.AddHandler("sql", GetDueNotificationDeliveries)
+}
+
+func CreateNotificationDelivery(cmd *m.CreateNotificationDeliveryCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		now := timeNow().Unix()
+
+		delivery := &m.NotificationDelivery{
+			OrgId:         cmd.Target.OrgId,
+			AlertId:       cmd.Target.AlertId,
+			NotifierId:    cmd.Target.NotifierId,
+			NotifierType:  cmd.Target.NotifierType,
+			State:         cmd.State,
+			Attempts:      cmd.Attempts,
+			LastError:     cmd.LastError,
+			NextAttemptAt: cmd.NextAttemptAt,
+			Created:       now,
+			Updated:       now,
+		}
+
+		// delivered requests are never sent again
+		if cmd.State != m.NotificationDeliveryDelivered {
+			delivery.Request = securejsondata.GetEncryptedJsonData(cmd.Request)
+		}
+
+		if _, err := sess.Insert(delivery); err != nil {
+			return err
+		}
+
+		cmd.Result = delivery
+		return nil
+	})
+}
+
+func ClaimNotificationDelivery(cmd *m.ClaimNotificationDeliveryCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec(`UPDATE notification_delivery SET attempts = ?, next_attempt_at = ?, updated = ?
+			WHERE id = ? AND attempts = ? AND state = ?`,
+			cmd.Attempts+1, cmd.RetryAt, timeNow().Unix(), cmd.Id, cmd.Attempts, m.NotificationDeliveryPending)
+		if err != nil {
+			return err
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return m.ErrNotificationDeliveryConflict
+		}
+
+		return nil
+	})
+}
+
+func SetNotificationDeliveryState(cmd *m.SetNotificationDeliveryStateCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		delivery := &m.NotificationDelivery{
+			State:         cmd.State,
+			LastError:     cmd.LastError,
+			NextAttemptAt: cmd.NextAttemptAt,
+			Updated:       timeNow().Unix(),
+		}
+
+		cols := []string{"state", "last_error", "next_attempt_at", "updated"}
+		if cmd.State == m.NotificationDeliveryDelivered {
+			cols = append(cols, "request")
+		}
+
+		_, err := sess.ID(cmd.Id).Cols(cols...).Update(delivery)
+		return err
+	})
+}
+
+func RetryNotificationDelivery(cmd *m.RetryNotificationDeliveryCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		now := timeNow().Unix()
+		res, err := sess.Exec(`UPDATE notification_delivery SET state = ?, attempts = 0, next_attempt_at = ?, updated = ?
+			WHERE id = ? AND org_id = ? AND state = ?`,
+			m.NotificationDeliveryPending, now, now, cmd.Id, cmd.OrgId, m.NotificationDeliveryFailed)
+		if err != nil {
+			return err
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return m.ErrNotificationDeliveryNotFound
+		}
+
+		return nil
+	})
+}
+
+func DeleteNotificationDelivery(cmd *m.DeleteNotificationDeliveryCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		_, err := sess.Exec("DELETE FROM notification_delivery WHERE id = ? AND org_id = ?", cmd.Id, cmd.OrgId)
+		return err
+	})
+}
+
+func DeleteExpiredNotificationDeliveries(cmd *m.DeleteExpiredNotificationDeliveriesCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec("DELETE FROM notification_delivery WHERE state <> ? AND updated < ?",
+			m.NotificationDeliveryPending, cmd.OlderThan.Unix())
+		if err != nil {
+			return err
+		}
+
+		cmd.DeletedRows, _ = res.RowsAffected()
+		return nil
+	})
+}
+
+func GetNotificationDelivery(query *m.GetNotificationDeliveryQuery) error {
+	delivery := m.NotificationDelivery{}
+	has, err := x.Where("id = ? AND org_id = ?", query.Id, query.OrgId).Get(&delivery)
+	if err != nil {
+		return err
+	}
+
+	if !has {
+		return m.ErrNotificationDeliveryNotFound
+	}
+
+	query.Result = &delivery
+	return nil
+}
+
+func GetNotificationDeliveries(query *m.GetNotificationDeliveriesQuery) error {
+	sess := x.Where("org_id = ?", query.OrgId)
+
+	if query.NotifierId != 0 {
+		sess.And("notifier_id = ?", query.NotifierId)
+	}
+
+	if len(query.States) > 0 {
+		states := make([]interface{}, 0, len(query.States))
+		for _, state := range query.States {
+			states = append(states, state)
+		}
+		sess.In("state", states...)
+	}
+
+	if query.Limit > 0 {
+		sess.Limit(query.Limit)
+	}
+
+	deliveries := make([]*m.NotificationDelivery, 0)
+	if err := sess.Desc("id").Find(&deliveries); err != nil {
+		return err
+	}
+
+	query.Result = deliveries
+	return nil
+}
+
+func GetDueNotificationDeliveries(query *m.GetDueNotificationDeliveriesQuery) error {
+	sess := x.Where("state = ? AND next_attempt_at <= ?", m.NotificationDeliveryPending, query.Now)
+
+	if query.Limit > 0 {
+		sess.Limit(query.Limit)
+	}
+
+	deliveries := make([]*m.NotificationDelivery, 0)
+	if err := sess.Asc("next_attempt_at").Find(&deliveries); err != nil {
+		return err
+	}
+
+	query.Result = deliveries
+	return nil
+}
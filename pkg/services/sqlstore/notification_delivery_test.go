@@ -0,0 +1,118 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNotificationDeliveryDataAccess(t *testing.T) {
+	Convey("Testing notification delivery data access", t, func() {
+		InitTestDB(t)
+
+		target := &m.NotificationDeliveryTarget{OrgId: 1, AlertId: 2, NotifierId: 3, NotifierType: "slack"}
+
+		cmd := &m.CreateNotificationDeliveryCommand{
+			Target:        target,
+			State:         m.NotificationDeliveryPending,
+			Attempts:      1,
+			LastError:     "Webhook response status 503 Service Unavailable",
+			Request:       map[string]string{"url": "https://hooks.slack.com/services/secret"},
+			NextAttemptAt: 100,
+		}
+		So(CreateNotificationDelivery(cmd), ShouldBeNil)
+		So(cmd.Result.Id, ShouldBeGreaterThan, 0)
+
+		Convey("Should store the request encrypted", func() {
+			query := &m.GetNotificationDeliveryQuery{OrgId: 1, Id: cmd.Result.Id}
+			So(GetNotificationDelivery(query), ShouldBeNil)
+			So(string(query.Result.Request["url"]), ShouldNotEqual, "https://hooks.slack.com/services/secret")
+			So(query.Result.Request.Decrypt()["url"], ShouldEqual, "https://hooks.slack.com/services/secret")
+		})
+
+		Convey("Should return due deliveries", func() {
+			query := &m.GetDueNotificationDeliveriesQuery{Now: 99}
+			So(GetDueNotificationDeliveries(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+
+			query = &m.GetDueNotificationDeliveriesQuery{Now: 100}
+			So(GetDueNotificationDeliveries(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+		})
+
+		Convey("Can claim delivery once", func() {
+			claim := &m.ClaimNotificationDeliveryCommand{Id: cmd.Result.Id, Attempts: 1, RetryAt: 200}
+			So(ClaimNotificationDelivery(claim), ShouldBeNil)
+			So(ClaimNotificationDelivery(claim), ShouldEqual, m.ErrNotificationDeliveryConflict)
+
+			query := &m.GetNotificationDeliveryQuery{OrgId: 1, Id: cmd.Result.Id}
+			So(GetNotificationDelivery(query), ShouldBeNil)
+			So(query.Result.Attempts, ShouldEqual, 2)
+			So(query.Result.NextAttemptAt, ShouldEqual, 200)
+		})
+
+		Convey("Failed deliveries are not due and can be retried", func() {
+			So(SetNotificationDeliveryState(&m.SetNotificationDeliveryStateCommand{
+				Id:        cmd.Result.Id,
+				State:     m.NotificationDeliveryFailed,
+				LastError: "gave up",
+			}), ShouldBeNil)
+
+			due := &m.GetDueNotificationDeliveriesQuery{Now: 1000}
+			So(GetDueNotificationDeliveries(due), ShouldBeNil)
+			So(len(due.Result), ShouldEqual, 0)
+
+			dead := &m.GetNotificationDeliveriesQuery{OrgId: 1, States: []m.NotificationDeliveryState{m.NotificationDeliveryFailed}}
+			So(GetNotificationDeliveries(dead), ShouldBeNil)
+			So(len(dead.Result), ShouldEqual, 1)
+			So(dead.Result[0].LastError, ShouldEqual, "gave up")
+
+			So(RetryNotificationDelivery(&m.RetryNotificationDeliveryCommand{OrgId: 2, Id: cmd.Result.Id}), ShouldEqual, m.ErrNotificationDeliveryNotFound)
+			So(RetryNotificationDelivery(&m.RetryNotificationDeliveryCommand{OrgId: 1, Id: cmd.Result.Id}), ShouldBeNil)
+
+			query := &m.GetNotificationDeliveryQuery{OrgId: 1, Id: cmd.Result.Id}
+			So(GetNotificationDelivery(query), ShouldBeNil)
+			So(query.Result.State, ShouldEqual, m.NotificationDeliveryPending)
+			So(query.Result.Attempts, ShouldEqual, 0)
+		})
+
+		Convey("Should list deliveries of a notifier", func() {
+			other := &m.CreateNotificationDeliveryCommand{
+				Target: &m.NotificationDeliveryTarget{OrgId: 1, NotifierId: 4, NotifierType: "webhook"},
+				State:  m.NotificationDeliveryDelivered,
+			}
+			So(CreateNotificationDelivery(other), ShouldBeNil)
+
+			query := &m.GetNotificationDeliveriesQuery{OrgId: 1, NotifierId: 3}
+			So(GetNotificationDeliveries(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+
+			query = &m.GetNotificationDeliveriesQuery{OrgId: 1}
+			So(GetNotificationDeliveries(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 2)
+		})
+
+		Convey("Should delete expired deliveries but keep pending ones", func() {
+			delivered := &m.CreateNotificationDeliveryCommand{Target: target, State: m.NotificationDeliveryDelivered}
+			So(CreateNotificationDelivery(delivered), ShouldBeNil)
+
+			expire := &m.DeleteExpiredNotificationDeliveriesCommand{OlderThan: time.Now().Add(time.Hour)}
+			So(DeleteExpiredNotificationDeliveries(expire), ShouldBeNil)
+			So(expire.DeletedRows, ShouldEqual, 1)
+
+			query := &m.GetNotificationDeliveriesQuery{OrgId: 1}
+			So(GetNotificationDeliveries(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.Result[0].State, ShouldEqual, m.NotificationDeliveryPending)
+		})
+
+		Convey("Can delete delivery", func() {
+			So(DeleteNotificationDelivery(&m.DeleteNotificationDeliveryCommand{OrgId: 1, Id: cmd.Result.Id}), ShouldBeNil)
+
+			query := &m.GetNotificationDeliveryQuery{OrgId: 1, Id: cmd.Result.Id}
+			So(GetNotificationDelivery(query), ShouldEqual, m.ErrNotificationDeliveryNotFound)
+		})
+	})
+}
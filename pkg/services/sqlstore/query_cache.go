@@ -0,0 +1,53 @@
+package sqlstore
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", GetQueryCacheItem)
+	bus.AddHandler("sql", SetQueryCacheItem)
+	bus.AddHandler("sql", DeleteExpiredQueryCacheItems)
+}
+
+func GetQueryCacheItem(query *m.GetQueryCacheItemQuery) error {
+	item := m.QueryCacheItem{}
+	has, err := x.Where("cache_key = ? AND expires > ?", query.CacheKey, timeNow().Unix()).Get(&item)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return m.ErrQueryCacheItemNotFound
+	}
+
+	query.Result = &item
+	return nil
+}
+
+func SetQueryCacheItem(cmd *m.SetQueryCacheItemCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		if _, err := sess.Exec("DELETE FROM query_cache_item WHERE cache_key = ?", cmd.CacheKey); err != nil {
+			return err
+		}
+
+		_, err := sess.Insert(&m.QueryCacheItem{
+			CacheKey: cmd.CacheKey,
+			Data:     cmd.Data,
+			Expires:  cmd.Expires.Unix(),
+		})
+		return err
+	})
+}
+
+func DeleteExpiredQueryCacheItems(cmd *m.DeleteExpiredQueryCacheItemsCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec("DELETE FROM query_cache_item WHERE expires <= ?", timeNow().Unix())
+		if err != nil {
+			return err
+		}
+
+		cmd.DeletedRows, err = res.RowsAffected()
+		return err
+	})
+}
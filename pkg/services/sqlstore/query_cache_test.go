@@ -0,0 +1,48 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueryCacheDataAccess(t *testing.T) {
+	Convey("Testing query cache data access", t, func() {
+		InitTestDB(t)
+
+		now := time.Now()
+		timeNow = func() time.Time { return now }
+		defer resetTimeNow()
+
+		err := SetQueryCacheItem(&m.SetQueryCacheItemCommand{CacheKey: "key", Data: `{"results": {}}`, Expires: now.Add(time.Minute)})
+		So(err, ShouldBeNil)
+
+		Convey("Can read an item", func() {
+			query := m.GetQueryCacheItemQuery{CacheKey: "key"}
+			So(GetQueryCacheItem(&query), ShouldBeNil)
+			So(query.Result.Data, ShouldEqual, `{"results": {}}`)
+		})
+
+		Convey("Can replace an item", func() {
+			err := SetQueryCacheItem(&m.SetQueryCacheItemCommand{CacheKey: "key", Data: "{}", Expires: now.Add(time.Minute)})
+			So(err, ShouldBeNil)
+
+			query := m.GetQueryCacheItemQuery{CacheKey: "key"}
+			So(GetQueryCacheItem(&query), ShouldBeNil)
+			So(query.Result.Data, ShouldEqual, "{}")
+		})
+
+		Convey("Expired items are not found and can be deleted", func() {
+			timeNow = func() time.Time { return now.Add(2 * time.Minute) }
+
+			query := m.GetQueryCacheItemQuery{CacheKey: "key"}
+			So(GetQueryCacheItem(&query), ShouldEqual, m.ErrQueryCacheItemNotFound)
+
+			cmd := m.DeleteExpiredQueryCacheItemsCommand{}
+			So(DeleteExpiredQueryCacheItems(&cmd), ShouldBeNil)
+			So(cmd.DeletedRows, ShouldEqual, 1)
+		})
+	})
+}
@@ -34,6 +34,18 @@ func (sb *SqlBuilder) writeDashboardPermissionFilter(user *m.SignedInUser, permi
 		return
 	}
 
+	sb.sql.WriteString(` AND
+	(
+		dashboard.id IN (`)
+	sb.writePermittedDashboardIds(user, permission)
+	sb.sql.WriteString(`
+		)
+	)`)
+}
+
+// writePermittedDashboardIds writes a query for the ids of the dashboards
+// and folders the user has the permission for.
+func (sb *SqlBuilder) writePermittedDashboardIds(user *m.SignedInUser, permission m.PermissionType) {
 	okRoles := []interface{}{user.OrgRole}
 
 	if user.OrgRole == m.ROLE_EDITOR {
@@ -42,9 +54,7 @@ func (sb *SqlBuilder) writeDashboardPermissionFilter(user *m.SignedInUser, permi
 
 	falseStr := dialect.BooleanStr(false)
 
-	sb.sql.WriteString(` AND
-	(
-		dashboard.id IN (
+	sb.sql.WriteString(`
 			SELECT distinct d.id AS DashboardId
 			FROM dashboard AS d
 			 	LEFT JOIN dashboard folder on folder.id = d.folder_id
@@ -66,9 +76,7 @@ func (sb *SqlBuilder) writeDashboardPermissionFilter(user *m.SignedInUser, permi
 					da.user_id = ? OR
 					ugm.user_id = ? OR
 					da.role IN (?` + strings.Repeat(",?", len(okRoles)-1) + `)
-				)
-		)
-	)`)
+				)`)
 
 	sb.params = append(sb.params, user.OrgId, permission, user.UserId, user.UserId)
 	sb.params = append(sb.params, okRoles...)
@@ -175,6 +175,12 @@ var (
 	AlertingErrorOrTimeout     string
 	AlertingNoDataOrNullValues string
 
+	AlertingEvaluationTimeout        time.Duration
+	AlertingMaxAttempts              int
+	AlertingMaxConcurrentEvaluations int
+	AlertingEvaluationQueueSize      int
+	AlertingEvaluationBackpressure   string
+
 	// Explore UI
 	ExploreEnabled bool
 
@@ -218,11 +224,23 @@ type Cfg struct {
 
 	DisableBruteForceLoginProtection bool
 	TempDataLifetime                 time.Duration
+	AlertingHistoryMaxAge            time.Duration
+	AlertingSilenceMaxAge            time.Duration
+	NotificationMaxAttempts          int
+	NotificationRetryBackoff         time.Duration
+	NotificationMaxRetryBackoff      time.Duration
+	NotificationDeliveryMaxAge       time.Duration
 	MetricsEndpointEnabled           bool
 	MetricsEndpointBasicAuthUsername string
 	MetricsEndpointBasicAuthPassword string
 	EnableAlphaPanels                bool
 	EnterpriseLicensePath            string
+
+	// Query cache
+	QueryCacheEnabled      bool
+	QueryCacheBackend      string
+	QueryCacheTTL          time.Duration
+	QueryCacheRedisConnStr string
 }
 
 type CommandLineArgs struct {
@@ -703,6 +721,17 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 	AlertingRenderLimit = alerting.Key("concurrent_render_limit").MustInt(5)
 	AlertingErrorOrTimeout = alerting.Key("error_or_timeout").MustString("alerting")
 	AlertingNoDataOrNullValues = alerting.Key("nodata_or_nullvalues").MustString("no_data")
+	AlertingEvaluationTimeout = alerting.Key("evaluation_timeout").MustDuration(time.Second * 30)
+	AlertingMaxAttempts = alerting.Key("max_attempts").MustInt(3)
+	AlertingMaxConcurrentEvaluations = alerting.Key("max_concurrent_evaluations").MustInt(20)
+	AlertingEvaluationQueueSize = alerting.Key("evaluation_queue_size").MustInt(1000)
+	AlertingEvaluationBackpressure = alerting.Key("evaluation_backpressure").In("queue", []string{"queue", "skip"})
+	cfg.AlertingHistoryMaxAge = alerting.Key("history_max_age").MustDuration(time.Hour * 24 * 30)
+	cfg.AlertingSilenceMaxAge = alerting.Key("expired_silences_max_age").MustDuration(time.Hour * 24 * 7)
+	cfg.NotificationMaxAttempts = alerting.Key("notification_max_attempts").MustInt(5)
+	cfg.NotificationRetryBackoff = alerting.Key("notification_retry_backoff").MustDuration(time.Second * 30)
+	cfg.NotificationMaxRetryBackoff = alerting.Key("notification_max_retry_backoff").MustDuration(time.Minute * 30)
+	cfg.NotificationDeliveryMaxAge = alerting.Key("notification_delivery_max_age").MustDuration(time.Hour * 24 * 7)
 
 	explore := iniFile.Section("explore")
 	ExploreEnabled = explore.Key("enabled").MustBool(false)
@@ -710,6 +739,12 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 	panels := iniFile.Section("panels")
 	cfg.EnableAlphaPanels = panels.Key("enable_alpha").MustBool(false)
 
+	queryCache := iniFile.Section("query_cache")
+	cfg.QueryCacheEnabled = queryCache.Key("enabled").MustBool(false)
+	cfg.QueryCacheBackend = queryCache.Key("backend").In("memory", []string{"memory", "database", "redis"})
+	cfg.QueryCacheTTL = queryCache.Key("ttl").MustDuration(time.Second * 30)
+	cfg.QueryCacheRedisConnStr = queryCache.Key("redis_connstr").MustString("addr=127.0.0.1:6379")
+
 	cfg.readSessionConfig()
 	cfg.readSmtpSettings()
 	cfg.readQuotaSettings()
@@ -71,8 +71,13 @@ func (b *SearchRequestBuilder) Size(size int) *SearchRequestBuilder {
 
 // SortDesc adds a sort to the search request
 func (b *SearchRequestBuilder) SortDesc(field, unmappedType string) *SearchRequestBuilder {
+	return b.Sort("desc", field, unmappedType)
+}
+
+// Sort adds a sort with the order, asc or desc, to the search request
+func (b *SearchRequestBuilder) Sort(order, field, unmappedType string) *SearchRequestBuilder {
 	props := map[string]string{
-		"order": "desc",
+		"order": order,
 	}
 
 	if unmappedType != "" {
@@ -102,6 +107,12 @@ func (b *SearchRequestBuilder) AddDocValueField(field string) *SearchRequestBuil
 	return b
 }
 
+// AddSourceFields limits the source of the documents to the fields
+func (b *SearchRequestBuilder) AddSourceFields(fields ...string) *SearchRequestBuilder {
+	b.customProps["_source"] = map[string]interface{}{"includes": fields}
+	return b
+}
+
 // Query creates and return a query builder
 func (b *SearchRequestBuilder) Query() *QueryBuilder {
 	if b.queryBuilder == nil {
@@ -45,7 +45,9 @@ var metricAggType = map[string]string{
 	"cardinality":    "Unique Count",
 	"moving_avg":     "Moving Average",
 	"derivative":     "Derivative",
+	"top_hits":       "Top Hits",
 	"raw_document":   "Raw Document",
+	"logs":           "Logs",
 }
 
 var extendedStats = map[string]string{
@@ -71,6 +73,16 @@ func isPipelineAgg(metricType string) bool {
 	return false
 }
 
+// isDocumentQuery tells if the query returns documents instead of
+// aggregations, raw document queries and logs queries have no buckets.
+func isDocumentQuery(q *Query) bool {
+	if len(q.BucketAggs) > 0 || len(q.Metrics) == 0 {
+		return false
+	}
+
+	return q.Metrics[0].Type == rawDocumentType || q.Metrics[0].Type == logsType
+}
+
 func describeMetric(metricType, field string) string {
 	text := metricAggType[metricType]
 	if metricType == countType {
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/null"
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -18,12 +19,17 @@ const (
 	countType         = "count"
 	percentilesType   = "percentiles"
 	extendedStatsType = "extended_stats"
+	topHitsType       = "top_hits"
+	rawDocumentType   = "raw_document"
+	logsType          = "logs"
 	// Bucket types
 	dateHistType    = "date_histogram"
 	histogramType   = "histogram"
 	filtersType     = "filters"
 	termsType       = "terms"
 	geohashGridType = "geohash_grid"
+	// logsVolumeAggID is the date histogram counting the documents of logs queries
+	logsVolumeAggID = "logs_volume"
 )
 
 type responseParser struct {
@@ -55,6 +61,12 @@ func (rp *responseParser) getTimeSeries() (*tsdb.Response, error) {
 		}
 
 		queryRes := tsdb.NewQueryResult()
+		if isDocumentQuery(target) {
+			rp.processDocuments(res, target, queryRes)
+			result.Results[target.RefID] = queryRes
+			continue
+		}
+
 		props := make(map[string]string)
 		table := tsdb.Table{
 			Columns: make([]tsdb.TableColumn, 0),
@@ -250,6 +262,26 @@ func (rp *responseParser) processMetrics(esAgg *simplejson.Json, target *Query,
 				}
 				*series = append(*series, &newSeries)
 			}
+		case topHitsType:
+			buckets := esAgg.Get("buckets").MustArray()
+
+			for _, field := range metricFields(metric) {
+				newSeries := tsdb.TimeSeries{
+					Tags: make(map[string]string),
+				}
+				for k, v := range props {
+					newSeries.Tags[k] = v
+				}
+				newSeries.Tags["metric"] = topHitsType
+				newSeries.Tags["field"] = field
+
+				for _, v := range buckets {
+					bucket := simplejson.NewFromAny(v)
+					key := castToNullFloat(bucket.Get("key"))
+					newSeries.Points = append(newSeries.Points, tsdb.TimePoint{topHitValue(bucket.Get(metric.ID), field), key})
+				}
+				*series = append(*series, &newSeries)
+			}
 		default:
 			newSeries := tsdb.TimeSeries{
 				Tags: make(map[string]string),
@@ -349,8 +381,22 @@ func (rp *responseParser) processAggregationDocs(esAgg *simplejson.Json, aggDef
 						value = castToNullFloat(bucket.GetPath(metric.ID, statName))
 					}
 
-					addMetricValue(&values, rp.getMetricName(metric.Type), value)
-					break
+					addMetricValue(&values, rp.getMetricName(statName), value)
+				}
+			case percentilesType:
+				percentiles := bucket.GetPath(metric.ID, "values").MustMap()
+				percentileKeys := make([]string, 0)
+				for k := range percentiles {
+					percentileKeys = append(percentileKeys, k)
+				}
+				sort.Strings(percentileKeys)
+
+				for _, percentileName := range percentileKeys {
+					addMetricValue(&values, "p"+percentileName+" "+metric.Field, castToNullFloat(bucket.GetPath(metric.ID, "values", percentileName)))
+				}
+			case topHitsType:
+				for _, field := range metricFields(metric) {
+					addMetricValue(&values, rp.getMetricName(topHitsType)+" "+field, topHitValue(bucket.Get(metric.ID), field))
 				}
 			default:
 				metricName := rp.getMetricName(metric.Type)
@@ -376,6 +422,135 @@ func (rp *responseParser) processAggregationDocs(esAgg *simplejson.Json, aggDef
 	return nil
 }
 
+// processDocuments returns the documents of raw document and logs queries
+// as a table, and the number of documents per interval of logs queries as
+// series.
+func (rp *responseParser) processDocuments(res *es.SearchResponse, target *Query, queryRes *tsdb.QueryResult) {
+	queryRes.Tables = append(queryRes.Tables, rp.processHits(res.Hits, target))
+
+	volume, exists := res.Aggregations[logsVolumeAggID]
+	if !exists {
+		return
+	}
+
+	name := target.Alias
+	if name == "" {
+		name = rp.getMetricName(countType)
+	}
+
+	newSeries := tsdb.TimeSeries{Name: name}
+	for _, v := range simplejson.NewFromAny(volume).Get("buckets").MustArray() {
+		bucket := simplejson.NewFromAny(v)
+		newSeries.Points = append(newSeries.Points, tsdb.TimePoint{castToNullFloat(bucket.Get("doc_count")), castToNullFloat(bucket.Get("key"))})
+	}
+	queryRes.Series = append(queryRes.Series, &newSeries)
+}
+
+// processHits turns documents into a table with the time field as first
+// column and a column per field of the documents. Nested fields are
+// flattened, like host.name.
+func (rp *responseParser) processHits(hits *es.SearchResponseHits, target *Query) *tsdb.Table {
+	timeField := target.TimeField
+	table := &tsdb.Table{
+		Columns: []tsdb.TableColumn{{Text: timeField}},
+		Rows:    make([]tsdb.RowValues, 0),
+	}
+
+	if hits == nil {
+		return table
+	}
+
+	docs := make([]map[string]interface{}, 0, len(hits.Hits))
+	for _, hit := range hits.Hits {
+		doc := map[string]interface{}{
+			"_id":    hit["_id"],
+			"_index": hit["_index"],
+		}
+
+		if source, ok := hit["_source"].(map[string]interface{}); ok {
+			flattenDocument("", source, doc)
+		}
+
+		// doc values of the time field are epochs, which are preferred over the source
+		if fields, ok := hit["fields"].(map[string]interface{}); ok {
+			if values, ok := fields[timeField].([]interface{}); ok && len(values) > 0 {
+				doc[timeField] = values[0]
+			}
+		}
+
+		docs = append(docs, doc)
+	}
+
+	fields := metricFields(target.Metrics[0])
+	if fields == nil {
+		seen := make(map[string]bool)
+		for _, doc := range docs {
+			for field := range doc {
+				if field != timeField && !seen[field] {
+					seen[field] = true
+					fields = append(fields, field)
+				}
+			}
+		}
+		sort.Strings(fields)
+	}
+
+	for _, field := range fields {
+		if field != timeField {
+			table.Columns = append(table.Columns, tsdb.TableColumn{Text: field})
+		}
+	}
+
+	for _, doc := range docs {
+		values := tsdb.RowValues{documentTime(doc[timeField])}
+		for _, field := range fields {
+			if field != timeField {
+				values = append(values, doc[field])
+			}
+		}
+		table.Rows = append(table.Rows, values)
+	}
+
+	return table
+}
+
+func flattenDocument(prefix string, source map[string]interface{}, doc map[string]interface{}) {
+	for k, v := range source {
+		if nested, ok := v.(map[string]interface{}); ok {
+			flattenDocument(prefix+k+".", nested, doc)
+			continue
+		}
+		doc[prefix+k] = v
+	}
+}
+
+// documentTime converts the time of a document to epoch milliseconds.
+func documentTime(value interface{}) interface{} {
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, text); err == nil {
+		return float64(t.UnixNano() / int64(time.Millisecond))
+	}
+
+	if epoch, err := strconv.ParseFloat(text, 64); err == nil {
+		return epoch
+	}
+
+	return value
+}
+
+// topHitValue returns the value of the field of the document of a top hits
+// aggregation.
+func topHitValue(agg *simplejson.Json, field string) null.Float {
+	doc := make(map[string]interface{})
+	flattenDocument("", agg.GetPath("hits", "hits").GetIndex(0).Get("_source").MustMap(), doc)
+
+	return castToNullFloat(simplejson.NewFromAny(doc[field]))
+}
+
 func (rp *responseParser) trimDatapoints(series *tsdb.TimeSeriesSlice, target *Query) {
 	var histogram *BucketAgg
 	for _, bucketAgg := range target.BucketAggs {
@@ -787,58 +787,215 @@ func TestResponseParser(t *testing.T) {
 			So(rows[0][2].(null.Float).Float64, ShouldEqual, 3000)
 		})
 
-		// Convey("Raw documents query", func() {
-		// 	targets := map[string]string{
-		// 		"A": `{
-		// 			"timeField": "@timestamp",
-		// 			"metrics": [{ "type": "raw_document", "id": "1" }]
-		// 		}`,
-		// 	}
-		// 	response := `{
-		//     "responses": [
-		//       {
-		//         "hits": {
-		//           "total": 100,
-		//           "hits": [
-		//             {
-		//               "_id": "1",
-		//               "_type": "type",
-		//               "_index": "index",
-		//               "_source": { "sourceProp": "asd" },
-		//               "fields": { "fieldProp": "field" }
-		//             },
-		//             {
-		//               "_source": { "sourceProp": "asd2" },
-		//               "fields": { "fieldProp": "field2" }
-		//             }
-		//           ]
-		//         }
-		//       }
-		//     ]
-		// 	}`
-		// 	rp, err := newResponseParserForTest(targets, response)
-		// 	So(err, ShouldBeNil)
-		// 	result, err := rp.getTimeSeries()
-		// 	So(err, ShouldBeNil)
-		// 	So(result.Results, ShouldHaveLength, 1)
-
-		// 	queryRes := result.Results["A"]
-		// 	So(queryRes, ShouldNotBeNil)
-		// 	So(queryRes.Tables, ShouldHaveLength, 1)
-
-		// 	rows := queryRes.Tables[0].Rows
-		// 	So(rows, ShouldHaveLength, 1)
-		// 	cols := queryRes.Tables[0].Columns
-		// 	So(cols, ShouldHaveLength, 3)
-
-		// 	So(cols[0].Text, ShouldEqual, "host")
-		// 	So(cols[1].Text, ShouldEqual, "Average test")
-		// 	So(cols[2].Text, ShouldEqual, "Average test2")
-
-		// 	So(rows[0][0].(string), ShouldEqual, "server-1")
-		// 	So(rows[0][1].(null.Float).Float64, ShouldEqual, 1000)
-		// 	So(rows[0][2].(null.Float).Float64, ShouldEqual, 3000)
-		// })
+		Convey("Raw documents query", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [{ "type": "raw_document", "id": "1" }]
+				}`,
+			}
+			response := `{
+        "responses": [
+          {
+            "hits": {
+              "total": 100,
+              "hits": [
+                {
+                  "_id": "1",
+                  "_type": "type",
+                  "_index": "index",
+                  "_source": { "@timestamp": "2018-05-15T17:50:00Z", "message": "started", "host": { "name": "server-1" } },
+                  "fields": { "@timestamp": [1526406600000] }
+                },
+                {
+                  "_id": "2",
+                  "_index": "index",
+                  "_source": { "@timestamp": "2018-05-15T17:51:00Z", "level": "error" }
+                }
+              ]
+            }
+          }
+        ]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			queryRes := result.Results["A"]
+			So(queryRes.Series, ShouldHaveLength, 0)
+			So(queryRes.Tables, ShouldHaveLength, 1)
+
+			table := queryRes.Tables[0]
+			So(table.Columns, ShouldResemble, []tsdb.TableColumn{
+				{Text: "@timestamp"}, {Text: "_id"}, {Text: "_index"}, {Text: "host.name"}, {Text: "level"}, {Text: "message"},
+			})
+			So(table.Rows, ShouldHaveLength, 2)
+			So(table.Rows[0], ShouldResemble, tsdb.RowValues{float64(1526406600000), "1", "index", "server-1", nil, "started"})
+			So(table.Rows[1][0], ShouldEqual, float64(1526406660000))
+			So(table.Rows[1][4], ShouldEqual, "error")
+		})
+
+		Convey("Raw documents query with fields", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [{ "type": "raw_document", "id": "1", "settings": { "fields": ["message", "@timestamp"] } }]
+				}`,
+			}
+			response := `{
+        "responses": [
+          {
+            "hits": {
+              "total": 1,
+              "hits": [
+                { "_id": "1", "_source": { "@timestamp": 1526406600000, "message": "started" } }
+              ]
+            }
+          }
+        ]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			table := result.Results["A"].Tables[0]
+			So(table.Columns, ShouldResemble, []tsdb.TableColumn{{Text: "@timestamp"}, {Text: "message"}})
+			So(table.Rows[0], ShouldResemble, tsdb.RowValues{float64(1526406600000), "started"})
+		})
+
+		Convey("Logs query", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [{ "type": "logs", "id": "1" }]
+				}`,
+			}
+			response := `{
+        "responses": [
+          {
+            "hits": {
+              "total": 1,
+              "hits": [
+                { "_id": "1", "_source": { "@timestamp": "2018-05-15T17:50:00Z", "message": "started" } }
+              ]
+            },
+            "aggregations": {
+              "logs_volume": {
+                "buckets": [
+                  { "doc_count": 1, "key": 1526406600000 },
+                  { "doc_count": 0, "key": 1526406660000 }
+                ]
+              }
+            }
+          }
+        ]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			queryRes := result.Results["A"]
+			So(queryRes.Tables[0].Rows, ShouldHaveLength, 1)
+			So(queryRes.Series, ShouldHaveLength, 1)
+			So(queryRes.Series[0].Name, ShouldEqual, "Count")
+			So(queryRes.Series[0].Points, ShouldHaveLength, 2)
+			So(queryRes.Series[0].Points[0][0].Float64, ShouldEqual, 1)
+			So(queryRes.Series[0].Points[1][0].Float64, ShouldEqual, 0)
+		})
+
+		Convey("Top hits by date histogram", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [{ "type": "top_hits", "id": "1", "settings": { "fields": ["cpu", "memory.used"] } }],
+          "bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "2" }]
+				}`,
+			}
+			response := `{
+        "responses": [
+          {
+            "aggregations": {
+              "2": {
+                "buckets": [
+                  {
+                    "1": { "hits": { "hits": [{ "_source": { "cpu": 0.5, "memory": { "used": 1024 } } }] } },
+                    "doc_count": 10,
+                    "key": 1000
+                  },
+                  {
+                    "1": { "hits": { "hits": [] } },
+                    "doc_count": 0,
+                    "key": 2000
+                  }
+                ]
+              }
+            }
+          }
+        ]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			queryRes := result.Results["A"]
+			So(queryRes.Series, ShouldHaveLength, 2)
+			So(queryRes.Series[0].Name, ShouldEqual, "Top Hits cpu")
+			So(queryRes.Series[0].Points[0][0].Float64, ShouldEqual, 0.5)
+			So(queryRes.Series[0].Points[1][0].Valid, ShouldBeFalse)
+			So(queryRes.Series[1].Name, ShouldEqual, "Top Hits memory.used")
+			So(queryRes.Series[1].Points[0][0].Float64, ShouldEqual, 1024)
+		})
+
+		Convey("Percentiles, extended stats and top hits by terms", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [
+						{ "type": "percentiles", "field": "latency", "id": "1" },
+						{ "type": "extended_stats", "field": "latency", "meta": { "std_deviation_bounds_upper": true, "std_deviation_bounds_lower": true }, "id": "3" },
+						{ "type": "top_hits", "field": "version", "id": "4" }
+					],
+          "bucketAggs": [{ "type": "terms", "field": "host", "id": "2" }]
+				}`,
+			}
+			response := `{
+        "responses": [
+          {
+            "aggregations": {
+              "2": {
+                "buckets": [
+                  {
+                    "1": { "values": { "50.0": 120, "95.0": 300 } },
+                    "3": { "std_deviation_bounds": { "upper": 400, "lower": 20 } },
+                    "4": { "hits": { "hits": [{ "_source": { "version": 3 } }] } },
+                    "key": "server-1",
+                    "doc_count": 10
+                  }
+                ]
+              }
+            }
+          }
+        ]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			table := result.Results["A"].Tables[0]
+			So(table.Columns, ShouldResemble, []tsdb.TableColumn{
+				{Text: "host"}, {Text: "p50.0 latency"}, {Text: "p95.0 latency"}, {Text: "Std Dev Lower"}, {Text: "Std Dev Upper"}, {Text: "Top Hits version"},
+			})
+			So(table.Rows[0][0], ShouldEqual, "server-1")
+			So(table.Rows[0][2].(null.Float).Float64, ShouldEqual, 300)
+			So(table.Rows[0][3].(null.Float).Float64, ShouldEqual, 20)
+			So(table.Rows[0][4].(null.Float).Float64, ShouldEqual, 400)
+			So(table.Rows[0][5].(null.Float).Float64, ShouldEqual, 3)
+		})
 	})
 }
 
@@ -3,6 +3,7 @@ package elasticsearch
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/tsdb"
@@ -55,7 +56,7 @@ func (e *timeSeriesQuery) execute() (*tsdb.Response, error) {
 		}
 
 		if len(q.BucketAggs) == 0 {
-			if len(q.Metrics) == 0 || q.Metrics[0].Type != "raw_document" {
+			if !isDocumentQuery(q) {
 				result.Results[q.RefID] = &tsdb.QueryResult{
 					RefId:       q.RefID,
 					Error:       fmt.Errorf("invalid query, missing metrics and aggregations"),
@@ -63,10 +64,7 @@ func (e *timeSeriesQuery) execute() (*tsdb.Response, error) {
 				}
 				continue
 			}
-			metric := q.Metrics[0]
-			b.Size(metric.Settings.Get("size").MustInt(500))
-			b.SortDesc("@timestamp", "boolean")
-			b.AddDocValueField("@timestamp")
+			addDocumentQuery(b, q.Metrics[0], e.client.GetTimeField(), from, to)
 			continue
 		}
 
@@ -115,9 +113,11 @@ func (e *timeSeriesQuery) execute() (*tsdb.Response, error) {
 				} else {
 					continue
 				}
+			} else if m.Type == topHitsType {
+				addTopHitsAgg(aggBuilder, m, e.client.GetTimeField())
 			} else {
 				aggBuilder.Metric(m.ID, m.Type, m.Field, func(a *es.MetricAggregation) {
-					a.Settings = m.Settings.MustMap()
+					a.Settings = metricAggSettings(m)
 				})
 			}
 		}
@@ -137,6 +137,87 @@ func (e *timeSeriesQuery) execute() (*tsdb.Response, error) {
 	return rp.getTimeSeries()
 }
 
+// addDocumentQuery searches the documents of raw document and logs
+// queries. Logs queries also count the documents per interval for the log
+// volume.
+func addDocumentQuery(b *es.SearchRequestBuilder, metric *MetricAgg, timeField, timeFrom, timeTo string) {
+	b.Size(metric.Settings.Get("size").MustInt(500))
+	b.Sort(metric.Settings.Get("order").MustString("desc"), timeField, "boolean")
+	b.AddDocValueField(timeField)
+
+	if fields := metricFields(metric); len(fields) > 0 {
+		b.AddSourceFields(fields...)
+	}
+
+	if metric.Type == logsType {
+		addDateHistogramAgg(b.Agg(), &BucketAgg{
+			ID:       logsVolumeAggID,
+			Type:     dateHistType,
+			Field:    timeField,
+			Settings: simplejson.New(),
+		}, timeFrom, timeTo)
+	}
+}
+
+// addTopHitsAgg adds a top hits aggregation returning the fields of the
+// first document of every bucket, the latest one by default.
+func addTopHitsAgg(aggBuilder es.AggBuilder, metric *MetricAgg, timeField string) {
+	aggBuilder.Metric(metric.ID, topHitsType, "", func(a *es.MetricAggregation) {
+		orderBy := metric.Settings.Get("orderBy").MustString(timeField)
+		order := metric.Settings.Get("order").MustString("desc")
+
+		a.Settings = map[string]interface{}{
+			"size": 1,
+			"sort": []map[string]interface{}{
+				{orderBy: map[string]string{"order": order}},
+			},
+			"_source": map[string]interface{}{"includes": metricFields(metric)},
+		}
+	})
+}
+
+// metricFields returns the fields of a metric, the fields setting or the
+// field of the metric.
+func metricFields(metric *MetricAgg) []string {
+	if fields, err := metric.Settings.Get("fields").StringArray(); err == nil && len(fields) > 0 {
+		return fields
+	}
+
+	if metric.Field != "" && metric.Type == topHitsType {
+		return []string{metric.Field}
+	}
+
+	return nil
+}
+
+var defaultPercents = []interface{}{25, 50, 75, 95, 99}
+
+// metricAggSettings returns the settings of a metric aggregation with the
+// default percents of the query editor, and without an empty sigma.
+func metricAggSettings(metric *MetricAgg) map[string]interface{} {
+	settings := make(map[string]interface{})
+	for k, v := range metric.Settings.MustMap() {
+		settings[k] = v
+	}
+
+	switch metric.Type {
+	case percentilesType:
+		if len(metric.Settings.Get("percents").MustArray()) == 0 {
+			settings["percents"] = defaultPercents
+		}
+	case extendedStatsType:
+		if sigma, ok := settings["sigma"].(string); ok {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(sigma), 64); err == nil {
+				settings["sigma"] = f
+			} else {
+				delete(settings, "sigma")
+			}
+		}
+	}
+
+	return settings
+}
+
 func addDateHistogramAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, timeFrom, timeTo string) es.AggBuilder {
 	aggBuilder.DateHistogram(bucketAgg.ID, bucketAgg.Field, func(a *es.DateHistogramAgg, b es.AggBuilder) {
 		a.Interval = bucketAgg.Settings.Get("interval").MustString("auto")
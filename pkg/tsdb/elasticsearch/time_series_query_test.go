@@ -302,6 +302,93 @@ func TestExecuteTimeSeriesQuery(t *testing.T) {
 			So(sr.Size, ShouldEqual, 1337)
 		})
 
+		Convey("With raw document metric order and fields", func() {
+			c := newFakeClient(5)
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [],
+				"metrics": [{ "id": "1", "type": "raw_document", "settings": { "order": "asc", "fields": ["message", "host"] } }]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			So(sr.Sort["@timestamp"], ShouldResemble, map[string]string{"order": "asc", "unmapped_type": "boolean"})
+			So(sr.CustomProps["_source"], ShouldResemble, map[string]interface{}{"includes": []string{"message", "host"}})
+			So(sr.CustomProps["docvalue_fields"], ShouldResemble, []string{"@timestamp"})
+			So(sr.Aggs, ShouldHaveLength, 0)
+		})
+
+		Convey("With logs metric", func() {
+			c := newFakeClient(5)
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [],
+				"metrics": [{ "id": "1", "type": "logs" }]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			So(sr.Size, ShouldEqual, 500)
+			So(sr.Sort["@timestamp"], ShouldResemble, map[string]string{"order": "desc", "unmapped_type": "boolean"})
+			So(sr.Aggs[0].Key, ShouldEqual, "logs_volume")
+			dateHistogramAgg := sr.Aggs[0].Aggregation.Aggregation.(*es.DateHistogramAgg)
+			So(dateHistogramAgg.Field, ShouldEqual, "@timestamp")
+			So(dateHistogramAgg.Interval, ShouldEqual, "$__interval")
+			So(dateHistogramAgg.ExtendedBounds.Min, ShouldEqual, fromStr)
+		})
+
+		Convey("With top hits metric", func() {
+			c := newFakeClient(5)
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "2" }],
+				"metrics": [{ "id": "1", "type": "top_hits", "field": "cpu", "settings": { "order": "asc" } }]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			topHitsAgg := sr.Aggs[0].Aggregation.Aggs[0]
+			So(topHitsAgg.Aggregation.Type, ShouldEqual, "top_hits")
+			metricAgg := topHitsAgg.Aggregation.Aggregation.(*es.MetricAggregation)
+			So(metricAgg.Field, ShouldEqual, "")
+			So(metricAgg.Settings["size"], ShouldEqual, 1)
+			So(metricAgg.Settings["sort"], ShouldResemble, []map[string]interface{}{{"@timestamp": map[string]string{"order": "asc"}}})
+			So(metricAgg.Settings["_source"], ShouldResemble, map[string]interface{}{"includes": []string{"cpu"}})
+		})
+
+		Convey("With metric percentiles without percents", func() {
+			c := newFakeClient(5)
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "3" }],
+				"metrics": [{ "id": "1", "type": "percentiles", "field": "@load_time" }]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			metricAgg := sr.Aggs[0].Aggregation.Aggs[0].Aggregation.Aggregation.(*es.MetricAggregation)
+			So(metricAgg.Settings["percents"], ShouldResemble, []interface{}{25, 50, 75, 95, 99})
+		})
+
+		Convey("With extended stats sigma", func() {
+			c := newFakeClient(5)
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "3" }],
+				"metrics": [
+					{ "id": "1", "type": "extended_stats", "field": "latency", "settings": { "sigma": "2" } },
+					{ "id": "2", "type": "extended_stats", "field": "latency", "settings": { "sigma": "" } }
+				]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			aggs := sr.Aggs[0].Aggregation.Aggs
+			So(aggs[0].Aggregation.Aggregation.(*es.MetricAggregation).Settings["sigma"], ShouldEqual, 2)
+			_, hasSigma := aggs[1].Aggregation.Aggregation.(*es.MetricAggregation).Settings["sigma"]
+			So(hasSigma, ShouldBeFalse)
+		})
+
 		Convey("With date histogram agg", func() {
 			c := newFakeClient(5)
 			_, err := executeTsdbQuery(c, `{
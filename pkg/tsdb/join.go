@@ -0,0 +1,203 @@
+package tsdb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// joinTransformation joins the series of the results on time into one
+// table with a time column and a column per series. Times missing in a
+// series have no value.
+type joinTransformation struct {
+	inputs transformationInputs
+	as     string
+}
+
+func newJoinTransformation(model *simplejson.Json) (Transformation, error) {
+	inputs, err := newTransformationInputs(model)
+	if err != nil {
+		return nil, err
+	}
+
+	return &joinTransformation{inputs: inputs, as: model.Get("as").MustString()}, nil
+}
+
+func (t *joinTransformation) Transform(req *TsdbQuery, resp *Response) error {
+	results := t.inputs.results(resp)
+	if len(results) == 0 {
+		return nil
+	}
+
+	table := &Table{Columns: []TableColumn{{Text: "time"}}, Rows: make([]RowValues, 0)}
+	rowsByTime := make(map[float64]RowValues)
+	times := make([]float64, 0)
+
+	seriesCount := 0
+	for _, result := range results {
+		seriesCount += len(result.Series)
+	}
+
+	column := 1
+	for _, result := range results {
+		for _, series := range result.Series {
+			table.Columns = append(table.Columns, TableColumn{Text: series.Name})
+
+			for _, point := range series.Points {
+				if !point[1].Valid {
+					continue
+				}
+
+				row, exists := rowsByTime[point[1].Float64]
+				if !exists {
+					row = make(RowValues, seriesCount+1)
+					row[0] = point[1].Float64
+					rowsByTime[point[1].Float64] = row
+					times = append(times, point[1].Float64)
+				}
+
+				if point[0].Valid {
+					row[column] = point[0].Float64
+				}
+			}
+
+			column++
+		}
+	}
+
+	sort.Float64s(times)
+	for _, timestamp := range times {
+		table.Rows = append(table.Rows, rowsByTime[timestamp])
+	}
+
+	replaceResults(resp, results, &QueryResult{Series: make(TimeSeriesSlice, 0), Tables: []*Table{table}}, t.as)
+	return nil
+}
+
+// outerJoinByTagTransformation joins the series of the results on their
+// tags and time into one table with the tag columns, a time column and a
+// column per result, e.g. to compare the requests and errors of each host
+// in one table.
+type outerJoinByTagTransformation struct {
+	inputs transformationInputs
+	tags   []string
+	as     string
+}
+
+func newOuterJoinByTagTransformation(model *simplejson.Json) (Transformation, error) {
+	inputs, err := newTransformationInputs(model)
+	if err != nil {
+		return nil, err
+	}
+
+	transformation := &outerJoinByTagTransformation{inputs: inputs, as: model.Get("as").MustString()}
+
+	if _, exists := model.CheckGet("tags"); exists {
+		if transformation.tags, err = model.Get("tags").StringArray(); err != nil {
+			return nil, fmt.Errorf("tags must be a list of tag names")
+		}
+	}
+
+	return transformation, nil
+}
+
+type tagJoinRow struct {
+	tags   []string
+	time   float64
+	values RowValues
+}
+
+func (t *outerJoinByTagTransformation) Transform(req *TsdbQuery, resp *Response) error {
+	results := t.inputs.results(resp)
+	if len(results) == 0 {
+		return nil
+	}
+
+	tags := t.tags
+	if tags == nil {
+		tags = seriesTagNames(results)
+	}
+
+	table := &Table{Columns: make([]TableColumn, 0), Rows: make([]RowValues, 0)}
+	for _, tag := range tags {
+		table.Columns = append(table.Columns, TableColumn{Text: tag})
+	}
+	table.Columns = append(table.Columns, TableColumn{Text: "time"})
+	for _, result := range results {
+		table.Columns = append(table.Columns, TableColumn{Text: result.RefId})
+	}
+
+	rowsByKey := make(map[string]*tagJoinRow)
+	rows := make([]*tagJoinRow, 0)
+
+	for column, result := range results {
+		for _, series := range result.Series {
+			tagValues := make([]string, len(tags))
+			for i, tag := range tags {
+				tagValues[i] = series.Tags[tag]
+			}
+
+			for _, point := range series.Points {
+				if !point[1].Valid {
+					continue
+				}
+
+				key := fmt.Sprintf("%s\x00%v", strings.Join(tagValues, "\x00"), point[1].Float64)
+				row, exists := rowsByKey[key]
+				if !exists {
+					row = &tagJoinRow{tags: tagValues, time: point[1].Float64, values: make(RowValues, len(results))}
+					rowsByKey[key] = row
+					rows = append(rows, row)
+				}
+
+				if point[0].Valid {
+					row.values[column] = point[0].Float64
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for k := range tags {
+			if rows[i].tags[k] != rows[j].tags[k] {
+				return rows[i].tags[k] < rows[j].tags[k]
+			}
+		}
+		return rows[i].time < rows[j].time
+	})
+
+	for _, row := range rows {
+		values := make(RowValues, 0, len(table.Columns))
+		for _, tag := range row.tags {
+			values = append(values, tag)
+		}
+		values = append(values, row.time)
+		values = append(values, row.values...)
+		table.Rows = append(table.Rows, values)
+	}
+
+	replaceResults(resp, results, &QueryResult{Series: make(TimeSeriesSlice, 0), Tables: []*Table{table}}, t.as)
+	return nil
+}
+
+// seriesTagNames returns the sorted names of all tags of the series.
+func seriesTagNames(results []*QueryResult) []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, result := range results {
+		for _, series := range result.Series {
+			for name := range series.Tags {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,420 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/tsdb"
+)
+
+// LokiExecutor queries the query_range API of Loki. Log queries return the
+// number of log lines per interval as series, and the lines as a table for
+// the logs format. Metric queries like count_over_time return series.
+type LokiExecutor struct {
+	httpClient *http.Client
+}
+
+func NewLokiExecutor(dsInfo *models.DataSource) (tsdb.TsdbQueryEndpoint, error) {
+	httpClient, err := dsInfo.GetHttpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &LokiExecutor{httpClient: httpClient}, nil
+}
+
+var (
+	plog               log.Logger
+	legendFormat       *regexp.Regexp
+	selectorRegexp     *regexp.Regexp
+	intervalCalculator tsdb.IntervalCalculator
+)
+
+func init() {
+	plog = log.New("tsdb.loki")
+	tsdb.RegisterTsdbQueryEndpoint("loki", NewLokiExecutor)
+	legendFormat = regexp.MustCompile(`\{\{\s*(.+?)\s*\}\}`)
+	selectorRegexp = regexp.MustCompile(`^\s*\{[^{]*\}`)
+	intervalCalculator = tsdb.NewIntervalCalculator(&tsdb.IntervalOptions{MinInterval: time.Second * 1})
+}
+
+func (e *LokiExecutor) Query(ctx context.Context, dsInfo *models.DataSource, tsdbQuery *tsdb.TsdbQuery) (*tsdb.Response, error) {
+	result := &tsdb.Response{
+		Results: map[string]*tsdb.QueryResult{},
+	}
+
+	queries, err := parseQuery(dsInfo, tsdbQuery.Queries, tsdbQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, query := range queries {
+		queryResult, err := e.executeQuery(ctx, dsInfo, query)
+		if err != nil {
+			return nil, err
+		}
+		queryResult.RefId = query.RefId
+		result.Results[query.RefId] = queryResult
+	}
+
+	return result, nil
+}
+
+// executeQuery runs the query. The lines of log queries are limited, so
+// their volume is counted by Loki with a count_over_time query and the
+// lines are only requested for the logs format.
+func (e *LokiExecutor) executeQuery(ctx context.Context, dsInfo *models.DataSource, query *LokiQuery) (*tsdb.QueryResult, error) {
+	if !selectorRegexp.MatchString(query.Expr) {
+		return e.fetch(ctx, dsInfo, query, query.Expr)
+	}
+
+	queryRes, err := e.fetch(ctx, dsInfo, query, logVolumeExpr(query))
+	if err != nil || queryRes.Error != nil {
+		return queryRes, err
+	}
+
+	for _, series := range queryRes.Series {
+		fillLogVolume(series, query)
+	}
+
+	if query.Format != "logs" {
+		return queryRes, nil
+	}
+
+	logsRes, err := e.fetch(ctx, dsInfo, query, query.Expr)
+	if err != nil || logsRes.Error != nil {
+		return logsRes, err
+	}
+	queryRes.Tables = logsRes.Tables
+
+	return queryRes, nil
+}
+
+func (e *LokiExecutor) fetch(ctx context.Context, dsInfo *models.DataSource, query *LokiQuery, expr string) (*tsdb.QueryResult, error) {
+	req, err := e.createRequest(dsInfo, query, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	plog.Debug("Sending query", "start", query.Start, "end", query.End, "step", query.Step, "query", expr)
+
+	res, err := ctxhttp.Do(ctx, e.httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseResponse(res, query)
+}
+
+func parseQuery(dsInfo *models.DataSource, queries []*tsdb.Query, queryContext *tsdb.TsdbQuery) ([]*LokiQuery, error) {
+	qs := []*LokiQuery{}
+	for _, queryModel := range queries {
+		expr := queryModel.Model.Get("expr").MustString()
+		if expr == "" {
+			return nil, fmt.Errorf("Loki query %s is missing an expression", queryModel.RefId)
+		}
+
+		format := queryModel.Model.Get("format").MustString("time_series")
+		if format != "time_series" && format != "logs" {
+			return nil, fmt.Errorf("Unsupported Loki query format %s", format)
+		}
+
+		start, err := queryContext.TimeRange.ParseFrom()
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := queryContext.TimeRange.ParseTo()
+		if err != nil {
+			return nil, err
+		}
+
+		dsInterval, err := tsdb.GetIntervalFrom(dsInfo, queryModel.Model, time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		interval := intervalCalculator.Calculate(queryContext.TimeRange, dsInterval)
+
+		qs = append(qs, &LokiQuery{
+			Expr:         toLogQL(expr),
+			Format:       format,
+			LegendFormat: queryModel.Model.Get("legendFormat").MustString(),
+			Limit:        queryModel.Model.Get("limit").MustInt64(1000),
+			Direction:    queryModel.Model.Get("direction").MustString("backward"),
+			Step:         interval.Value,
+			Start:        start,
+			End:          end,
+			RefId:        queryModel.RefId,
+		})
+	}
+
+	return qs, nil
+}
+
+// toLogQL turns queries with a search after the stream selector, like
+// `{job="app"} error` of the Explore query field, into a LogQL line filter.
+func toLogQL(expr string) string {
+	selector := selectorRegexp.FindString(expr)
+	if selector == "" {
+		return expr
+	}
+
+	search := strings.TrimSpace(strings.TrimPrefix(expr, selector))
+	if search == "" || strings.HasPrefix(search, "|") || strings.HasPrefix(search, "!") {
+		return expr
+	}
+
+	return strings.TrimSpace(selector) + " |~ " + strconv.Quote(search)
+}
+
+// logVolumeExpr counts the lines of a log query per step, e.g.
+// `count_over_time({job="app"} |= "error" [1m])`.
+func logVolumeExpr(query *LokiQuery) string {
+	step := query.Step
+	if step < time.Second {
+		step = time.Second
+	}
+
+	return fmt.Sprintf("count_over_time(%s [%ds])", query.Expr, int64(step/time.Second))
+}
+
+func (e *LokiExecutor) createRequest(dsInfo *models.DataSource, query *LokiQuery, expr string) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.Url)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "loki/api/v1/query_range")
+
+	params := url.Values{}
+	params.Set("query", expr)
+	params.Set("start", strconv.FormatInt(query.Start.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(query.End.UnixNano(), 10))
+	params.Set("step", strconv.FormatFloat(query.Step.Seconds(), 'f', -1, 64))
+	params.Set("limit", strconv.FormatInt(query.Limit, 10))
+	params.Set("direction", query.Direction)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		plog.Info("Failed to create request", "error", err)
+		return nil, fmt.Errorf("Failed to create request. error: %v", err)
+	}
+
+	if dsInfo.BasicAuth {
+		req.SetBasicAuth(dsInfo.BasicAuthUser, dsInfo.BasicAuthPassword)
+	}
+
+	return req, nil
+}
+
+func parseResponse(res *http.Response, query *LokiQuery) (*tsdb.QueryResult, error) {
+	queryRes := tsdb.NewQueryResult()
+
+	body, err := ioutil.ReadAll(res.Body)
+	defer res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode/100 != 2 {
+		plog.Info("Request failed", "status", res.Status, "body", string(body))
+		queryRes.Error = fmt.Errorf("Loki request failed: %s %s", res.Status, strings.TrimSpace(string(body)))
+		return queryRes, nil
+	}
+
+	var data lokiResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		plog.Info("Failed to unmarshal loki response", "error", err, "status", res.Status, "body", string(body))
+		return nil, err
+	}
+
+	switch data.Data.ResultType {
+	case "streams":
+		var streams []lokiStream
+		if err := json.Unmarshal(data.Data.Result, &streams); err != nil {
+			return nil, err
+		}
+
+		table, err := logsTable(streams, query)
+		if err != nil {
+			return nil, err
+		}
+		queryRes.Tables = append(queryRes.Tables, table)
+	case "matrix":
+		var matrix []lokiMatrixSeries
+		if err := json.Unmarshal(data.Data.Result, &matrix); err != nil {
+			return nil, err
+		}
+
+		for _, v := range matrix {
+			series, err := matrixSeries(v, query)
+			if err != nil {
+				return nil, err
+			}
+			queryRes.Series = append(queryRes.Series, series)
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported result type: %s", data.Data.ResultType)
+	}
+
+	return queryRes, nil
+}
+
+// fillLogVolume adds a zero for the steps without lines, which Loki leaves
+// out, so alerts can fire when an application stops logging. The points
+// returned by Loki are kept as they are, zeros only fill the gaps of at
+// least a step between them.
+func fillLogVolume(series *tsdb.TimeSeries, query *LokiQuery) {
+	stepMs := float64(query.Step / time.Millisecond)
+	if stepMs < 1000 {
+		stepMs = 1000
+	}
+
+	points := make(tsdb.TimeSeriesPoints, 0, len(series.Points))
+	next := float64(query.Start.UnixNano() / int64(time.Millisecond))
+	for _, point := range series.Points {
+		for ; next <= point[1].Float64-stepMs; next += stepMs {
+			points = append(points, tsdb.NewTimePoint(null.FloatFrom(0), next))
+		}
+		points = append(points, point)
+		next = point[1].Float64 + stepMs
+	}
+
+	end := float64(query.End.UnixNano() / int64(time.Millisecond))
+	for ; next <= end; next += stepMs {
+		points = append(points, tsdb.NewTimePoint(null.FloatFrom(0), next))
+	}
+
+	series.Points = points
+}
+
+type logRow struct {
+	timestamp int64
+	values    tsdb.RowValues
+}
+
+// logsTable returns the lines of all streams in one table with a column
+// per label, ordered like the direction of the query.
+func logsTable(streams []lokiStream, query *LokiQuery) (*tsdb.Table, error) {
+	labels := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, stream := range streams {
+		for name := range stream.Stream {
+			if !seen[name] {
+				seen[name] = true
+				labels = append(labels, name)
+			}
+		}
+	}
+	sort.Strings(labels)
+
+	table := &tsdb.Table{Columns: []tsdb.TableColumn{{Text: "time"}}, Rows: make([]tsdb.RowValues, 0)}
+	for _, name := range labels {
+		table.Columns = append(table.Columns, tsdb.TableColumn{Text: name})
+	}
+	table.Columns = append(table.Columns, tsdb.TableColumn{Text: "line"})
+
+	rows := make([]logRow, 0)
+	for _, stream := range streams {
+		for _, value := range stream.Values {
+			nanoseconds, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid log line timestamp %s", value[0])
+			}
+
+			row := tsdb.RowValues{float64(nanoseconds / int64(time.Millisecond))}
+			for _, name := range labels {
+				row = append(row, stream.Stream[name])
+			}
+			row = append(row, value[1])
+
+			rows = append(rows, logRow{timestamp: nanoseconds, values: row})
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if query.Direction == "forward" {
+			return rows[i].timestamp < rows[j].timestamp
+		}
+		return rows[i].timestamp > rows[j].timestamp
+	})
+
+	for _, row := range rows {
+		table.Rows = append(table.Rows, row.values)
+	}
+
+	return table, nil
+}
+
+func matrixSeries(v lokiMatrixSeries, query *LokiQuery) (*tsdb.TimeSeries, error) {
+	series := &tsdb.TimeSeries{
+		Name:   formatLegend(v.Metric, query),
+		Tags:   v.Metric,
+		Points: make(tsdb.TimeSeriesPoints, 0),
+	}
+
+	for _, value := range v.Values {
+		seconds, ok := value[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("Invalid timestamp %v", value[0])
+		}
+
+		text, ok := value[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("Invalid value %v", value[1])
+		}
+
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value %s", text)
+		}
+
+		series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(f), seconds*1000))
+	}
+
+	return series, nil
+}
+
+// formatLegend names a series after its labels, like {job="app"}, or with
+// the legend format of the query, like {{job}}.
+func formatLegend(labels map[string]string, query *LokiQuery) string {
+	if query.LegendFormat == "" {
+		names := make([]string, 0, len(labels))
+		for name := range labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		pairs := make([]string, 0, len(names))
+		for _, name := range names {
+			pairs = append(pairs, fmt.Sprintf("%s=%q", name, labels[name]))
+		}
+
+		return "{" + strings.Join(pairs, ", ") + "}"
+	}
+
+	return legendFormat.ReplaceAllStringFunc(query.LegendFormat, func(in string) string {
+		name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(in, "{{"), "}}"))
+		if value, exists := labels[name]; exists {
+			return value
+		}
+
+		return in
+	})
+}
@@ -0,0 +1,184 @@
+package loki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLoki(t *testing.T) {
+	Convey("Loki", t, func() {
+		now := time.Unix(1500000000, 0)
+		nanoseconds := func(seconds int64) string {
+			return fmt.Sprintf("%d", now.Add(time.Duration(seconds)*time.Second).UnixNano())
+		}
+
+		var requests []url.Values
+		var requestPath string
+		status := http.StatusOK
+		body := ""
+		volume := ""
+
+		ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			requestPath = r.URL.Path
+			requests = append(requests, r.URL.Query())
+			rw.WriteHeader(status)
+			if strings.HasPrefix(r.URL.Query().Get("query"), "count_over_time(") && volume != "" {
+				fmt.Fprint(rw, volume)
+				return
+			}
+			fmt.Fprint(rw, body)
+		}))
+		defer ts.Close()
+
+		dsInfo := &models.DataSource{Id: 1, Type: "loki", Url: ts.URL, JsonData: simplejson.New()}
+		exec, err := NewLokiExecutor(dsInfo)
+		So(err, ShouldBeNil)
+
+		query := func(model string) (*tsdb.Response, error) {
+			json, err := simplejson.NewJson([]byte(model))
+			So(err, ShouldBeNil)
+
+			return exec.Query(context.TODO(), dsInfo, &tsdb.TsdbQuery{
+				TimeRange: tsdb.NewFakeTimeRange("5m", "now", now),
+				Queries:   []*tsdb.Query{{RefId: "A", Model: json}},
+			})
+		}
+
+		streams := `{"status": "success", "data": {"resultType": "streams", "result": [
+			{"stream": {"job": "app", "level": "error"}, "values": [["` + nanoseconds(-10) + `", "timeout"], ["` + nanoseconds(-70) + `", "refused"]]},
+			{"stream": {"job": "app", "level": "info"}, "values": [["` + nanoseconds(-5) + `", "started"]]}
+		]}}`
+
+		volume = `{"status": "success", "data": {"resultType": "matrix", "result": [
+			{"metric": {"job": "app", "level": "error"}, "values": [[1499999820, "1500"], [1500000000, "2"]]},
+			{"metric": {"job": "app", "level": "info"}, "values": [[1500000000, "1"]]}
+		]}}`
+
+		Convey("Sends the query range request", func() {
+			body = streams
+			_, err := query(`{"expr": "{job=\"app\"} timeout", "limit": 100, "format": "logs"}`)
+
+			So(err, ShouldBeNil)
+			So(requests, ShouldHaveLength, 2)
+			So(requestPath, ShouldEqual, "/loki/api/v1/query_range")
+
+			request := requests[1]
+			So(request.Get("query"), ShouldEqual, `{job="app"} |~ "timeout"`)
+			So(request.Get("start"), ShouldEqual, nanoseconds(-300))
+			So(request.Get("end"), ShouldEqual, nanoseconds(0))
+			So(request.Get("limit"), ShouldEqual, "100")
+			So(request.Get("direction"), ShouldEqual, "backward")
+		})
+
+		Convey("Counts the log volume with a metric query", func() {
+			body = streams
+			resp, err := query(`{"expr": "{job=\"app\"}", "interval": "1m"}`)
+
+			So(err, ShouldBeNil)
+			So(requests, ShouldHaveLength, 1)
+			So(requests[0].Get("query"), ShouldEqual, `count_over_time({job="app"} [60s])`)
+			So(requests[0].Get("step"), ShouldEqual, "60")
+
+			result := resp.Results["A"]
+			So(result.Tables, ShouldHaveLength, 0)
+			So(result.Series, ShouldHaveLength, 2)
+			So(result.Series[0].Name, ShouldEqual, `{job="app", level="error"}`)
+			So(result.Series[0].Tags, ShouldResemble, map[string]string{"job": "app", "level": "error"})
+
+			Convey("Counts more lines than the limit", func() {
+				So(result.Series[0].Points[2][0].Float64, ShouldEqual, 1500)
+			})
+
+			Convey("Counts zero for steps without lines", func() {
+				So(result.Series[0].Points, ShouldHaveLength, 6)
+				So(result.Series[0].Points[0][0].Float64, ShouldEqual, 0)
+				So(result.Series[0].Points[0][0].Valid, ShouldBeTrue)
+				So(result.Series[0].Points[5][0].Float64, ShouldEqual, 2)
+				So(result.Series[0].Points[5][1].Float64, ShouldEqual, 1500000000000)
+			})
+		})
+
+		Convey("Keeps log volume points that are not aligned to the start", func() {
+			volume = `{"status": "success", "data": {"resultType": "matrix", "result": [
+				{"metric": {"job": "app"}, "values": [[1499999790.5, "4"], [1499999910.5, "7"]]}
+			]}}`
+			resp, err := query(`{"expr": "{job=\"app\"}", "interval": "1m"}`)
+
+			So(err, ShouldBeNil)
+			points := resp.Results["A"].Series[0].Points
+			So(points, ShouldHaveLength, 5)
+			So(points[1][0].Float64, ShouldEqual, 4)
+			So(points[1][1].Float64, ShouldEqual, 1499999790500)
+			So(points[2][0].Float64, ShouldEqual, 0)
+			So(points[3][0].Float64, ShouldEqual, 7)
+			So(points[3][1].Float64, ShouldEqual, 1499999910500)
+			So(points[4][0].Float64, ShouldEqual, 0)
+		})
+
+		Convey("Returns the log lines as a table for the logs format", func() {
+			body = streams
+			resp, err := query(`{"expr": "{job=\"app\"}", "format": "logs", "legendFormat": "{{level}}"}`)
+
+			So(err, ShouldBeNil)
+			result := resp.Results["A"]
+			So(result.Series, ShouldHaveLength, 2)
+			So(result.Series[1].Name, ShouldEqual, "info")
+
+			table := result.Tables[0]
+			So(table.Columns, ShouldResemble, []tsdb.TableColumn{{Text: "time"}, {Text: "job"}, {Text: "level"}, {Text: "line"}})
+			So(table.Rows, ShouldHaveLength, 3)
+			So(table.Rows[0], ShouldResemble, tsdb.RowValues{float64(now.Add(-5*time.Second).Unix() * 1000), "app", "info", "started"})
+			So(table.Rows[2][3], ShouldEqual, "refused")
+		})
+
+		Convey("Returns the series of metric queries", func() {
+			volume = ""
+			body = `{"status": "success", "data": {"resultType": "matrix", "result": [
+				{"metric": {"job": "app"}, "values": [[1500000000, "12"], [1500000060.5, "3"]]}
+			]}}`
+			resp, err := query(`{"expr": "count_over_time({job=\"app\"}[1m])"}`)
+
+			So(err, ShouldBeNil)
+			So(requests, ShouldHaveLength, 1)
+			So(requests[0].Get("query"), ShouldEqual, `count_over_time({job="app"}[1m])`)
+			series := resp.Results["A"].Series[0]
+			So(series.Name, ShouldEqual, `{job="app"}`)
+			So(series.Points[1][0].Float64, ShouldEqual, 3)
+			So(series.Points[1][1].Float64, ShouldEqual, 1500000060500)
+		})
+
+		Convey("Returns the error of failed queries", func() {
+			status = http.StatusBadRequest
+			body = "parse error at line 1"
+			volume = ""
+			resp, err := query(`{"expr": "{job=app}"}`)
+
+			So(err, ShouldBeNil)
+			So(resp.Results["A"].Error.Error(), ShouldContainSubstring, "parse error at line 1")
+		})
+
+		Convey("Rejects queries without an expression", func() {
+			_, err := query(`{}`)
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Converting search queries to LogQL", t, func() {
+		So(toLogQL(`{job="app"}`), ShouldEqual, `{job="app"}`)
+		So(toLogQL(`{job="app"} error|warn`), ShouldEqual, `{job="app"} |~ "error|warn"`)
+		So(toLogQL(`{job="app"} |= "error"`), ShouldEqual, `{job="app"} |= "error"`)
+		So(toLogQL(`rate({job="app"}[5m])`), ShouldEqual, `rate({job="app"}[5m])`)
+	})
+}
@@ -0,0 +1,43 @@
+package loki
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type LokiQuery struct {
+	Expr         string
+	Format       string
+	LegendFormat string
+	Limit        int64
+	Direction    string
+	Step         time.Duration
+	Start        time.Time
+	End          time.Time
+	RefId        string
+}
+
+type lokiResponse struct {
+	Status string   `json:"status"`
+	Data   lokiData `json:"data"`
+	Error  string   `json:"error"`
+}
+
+type lokiData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// lokiStream is a stream of log lines with the same labels, each value is
+// a pair of a nanosecond epoch and the line.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiMatrixSeries is a series of a metric query like count_over_time, each
+// value is a pair of a second epoch and the value.
+type lokiMatrixSeries struct {
+	Metric map[string]string `json:"metric"`
+	Values [][2]interface{}  `json:"values"`
+}
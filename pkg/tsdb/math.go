@@ -0,0 +1,477 @@
+package tsdb
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// MathValue is the result of a math expression. It is either a scalar or
+// a list of series.
+type MathValue struct {
+	IsScalar bool
+	Scalar   null.Float
+	Series   TimeSeriesSlice
+}
+
+// MathExpr is a parsed math expression, see ParseMathExpression.
+type MathExpr interface {
+	Eval(vars map[string]TimeSeriesSlice) (*MathValue, error)
+	Vars() []string
+}
+
+type numberExpr struct {
+	value float64
+}
+
+func (e *numberExpr) Eval(vars map[string]TimeSeriesSlice) (*MathValue, error) {
+	return &MathValue{IsScalar: true, Scalar: null.FloatFrom(e.value)}, nil
+}
+
+func (e *numberExpr) Vars() []string {
+	return nil
+}
+
+type varExpr struct {
+	name string
+}
+
+func (e *varExpr) Eval(vars map[string]TimeSeriesSlice) (*MathValue, error) {
+	series, exist := vars[e.name]
+	if !exist {
+		return nil, fmt.Errorf("Unknown variable $%s", e.name)
+	}
+
+	return &MathValue{Series: series}, nil
+}
+
+func (e *varExpr) Vars() []string {
+	return []string{e.name}
+}
+
+type unaryExpr struct {
+	expr MathExpr
+}
+
+func (e *unaryExpr) Eval(vars map[string]TimeSeriesSlice) (*MathValue, error) {
+	value, err := e.expr.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapValue(value, func(v float64) null.Float { return null.FloatFrom(-v) }), nil
+}
+
+func (e *unaryExpr) Vars() []string {
+	return e.expr.Vars()
+}
+
+type binaryExpr struct {
+	op    string
+	left  MathExpr
+	right MathExpr
+}
+
+func (e *binaryExpr) Eval(vars map[string]TimeSeriesSlice) (*MathValue, error) {
+	left, err := e.left.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := e.right.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return binaryOp(e.op, left, right), nil
+}
+
+func (e *binaryExpr) Vars() []string {
+	return append(e.left.Vars(), e.right.Vars()...)
+}
+
+type funcExpr struct {
+	name string
+	arg  MathExpr
+}
+
+func (e *funcExpr) Eval(vars map[string]TimeSeriesSlice) (*MathValue, error) {
+	value, err := e.arg.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.name == "abs" {
+		return mapValue(value, func(v float64) null.Float { return null.FloatFrom(math.Abs(v)) }), nil
+	}
+
+	if value.IsScalar {
+		return value, nil
+	}
+
+	// reduce every series to a single point, keeping its labels
+	reducer, params := ParseReducer(e.name)
+	result := &MathValue{Series: make(TimeSeriesSlice, 0, len(value.Series))}
+	for _, series := range value.Series {
+		timestamp := null.FloatFrom(0)
+		if len(series.Points) > 0 {
+			timestamp = series.Points[len(series.Points)-1][1]
+		}
+
+		result.Series = append(result.Series, &TimeSeries{
+			Name:   series.Name,
+			Tags:   series.Tags,
+			Points: TimeSeriesPoints{NewTimePoint(ReduceSeries(reducer, params, series), timestamp.Float64)},
+		})
+	}
+
+	return result, nil
+}
+
+func (e *funcExpr) Vars() []string {
+	return e.arg.Vars()
+}
+
+var mathFuncs = []string{"abs", "avg", "sum", "min", "max", "count", "last", "median", "diff", "percent_diff", "count_non_null", "stddev", "rate"}
+
+func isMathFunc(name string) bool {
+	if percentileShorthand.MatchString(name) {
+		return true
+	}
+
+	for _, f := range mathFuncs {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+func mapValue(value *MathValue, fn func(v float64) null.Float) *MathValue {
+	apply := func(v null.Float) null.Float {
+		if !v.Valid {
+			return v
+		}
+		return fn(v.Float64)
+	}
+
+	if value.IsScalar {
+		return &MathValue{IsScalar: true, Scalar: apply(value.Scalar)}
+	}
+
+	result := &MathValue{Series: make(TimeSeriesSlice, 0, len(value.Series))}
+	for _, series := range value.Series {
+		points := make(TimeSeriesPoints, 0, len(series.Points))
+		for _, point := range series.Points {
+			points = append(points, TimePoint{apply(point[0]), point[1]})
+		}
+		result.Series = append(result.Series, &TimeSeries{Name: series.Name, Tags: series.Tags, Points: points})
+	}
+
+	return result
+}
+
+func applyOp(op string, a null.Float, b null.Float) null.Float {
+	if !a.Valid || !b.Valid {
+		return null.FloatFromPtr(nil)
+	}
+
+	switch op {
+	case "+":
+		return null.FloatFrom(a.Float64 + b.Float64)
+	case "-":
+		return null.FloatFrom(a.Float64 - b.Float64)
+	case "*":
+		return null.FloatFrom(a.Float64 * b.Float64)
+	case "/":
+		if b.Float64 == 0 {
+			return null.FloatFromPtr(nil)
+		}
+		return null.FloatFrom(a.Float64 / b.Float64)
+	}
+
+	return null.FloatFromPtr(nil)
+}
+
+// binaryOp applies an operator to two values. Series are matched by their
+// tags, a single series is combined with every series of the other side and
+// points are joined on their timestamp. A series with a single point (for
+// example the result of a reducer) is combined with every point.
+func binaryOp(op string, left *MathValue, right *MathValue) *MathValue {
+	if left.IsScalar && right.IsScalar {
+		return &MathValue{IsScalar: true, Scalar: applyOp(op, left.Scalar, right.Scalar)}
+	}
+
+	if right.IsScalar {
+		return mapValue(left, func(v float64) null.Float { return applyOp(op, null.FloatFrom(v), right.Scalar) })
+	}
+
+	if left.IsScalar {
+		return mapValue(right, func(v float64) null.Float { return applyOp(op, left.Scalar, null.FloatFrom(v)) })
+	}
+
+	result := &MathValue{Series: make(TimeSeriesSlice, 0)}
+
+	switch {
+	case len(right.Series) == 1:
+		for _, series := range left.Series {
+			result.Series = append(result.Series, joinSeries(op, series, right.Series[0], series))
+		}
+	case len(left.Series) == 1:
+		for _, series := range right.Series {
+			result.Series = append(result.Series, joinSeries(op, left.Series[0], series, series))
+		}
+	default:
+		rightByLabels := make(map[string]*TimeSeries)
+		for _, series := range right.Series {
+			rightByLabels[models.AlertInstanceLabelsHash(series.Tags)] = series
+		}
+
+		for _, series := range left.Series {
+			if match, exist := rightByLabels[models.AlertInstanceLabelsHash(series.Tags)]; exist {
+				result.Series = append(result.Series, joinSeries(op, series, match, series))
+			}
+		}
+	}
+
+	return result
+}
+
+func joinSeries(op string, left *TimeSeries, right *TimeSeries, naming *TimeSeries) *TimeSeries {
+	result := &TimeSeries{Name: naming.Name, Tags: naming.Tags, Points: make(TimeSeriesPoints, 0)}
+
+	switch {
+	case len(right.Points) == 1:
+		for _, point := range left.Points {
+			result.Points = append(result.Points, TimePoint{applyOp(op, point[0], right.Points[0][0]), point[1]})
+		}
+	case len(left.Points) == 1:
+		for _, point := range right.Points {
+			result.Points = append(result.Points, TimePoint{applyOp(op, left.Points[0][0], point[0]), point[1]})
+		}
+	default:
+		rightValues := make(map[float64]null.Float)
+		for _, point := range right.Points {
+			rightValues[point[1].Float64] = point[0]
+		}
+
+		for _, point := range left.Points {
+			if value, exist := rightValues[point[1].Float64]; exist {
+				result.Points = append(result.Points, TimePoint{applyOp(op, point[0], value), point[1]})
+			}
+		}
+	}
+
+	return result
+}
+
+// ParseMathExpression parses expressions like `$A / $B * 100` or
+// `(max($A) - min($A)) / avg($A)`.
+func ParseMathExpression(text string) (MathExpr, error) {
+	p := &mathParser{tokens: tokenizeMathExpression(text)}
+
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("Unexpected %q in math expression", p.tokens[p.pos])
+	}
+
+	return expr, nil
+}
+
+func tokenizeMathExpression(text string) []string {
+	tokens := make([]string, 0)
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("+-*/(),", r):
+			tokens = append(tokens, string(r))
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("+-*/(),", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		}
+	}
+
+	return tokens
+}
+
+type mathParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *mathParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *mathParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *mathParser) parseExpr() (MathExpr, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "+" || p.peek() == "-" {
+		op := p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *mathParser) parseTerm() (MathExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "*" || p.peek() == "/" {
+		op := p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *mathParser) parseUnary() (MathExpr, error) {
+	if p.peek() == "-" {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{expr: expr}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *mathParser) parsePrimary() (MathExpr, error) {
+	token := p.next()
+
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("Unexpected end of math expression")
+	case token == "(":
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("Missing closing parenthesis in math expression")
+		}
+		return expr, nil
+	case strings.HasPrefix(token, "$"):
+		name := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(token, "$"), "{"), "}")
+		if name == "" {
+			return nil, fmt.Errorf("Missing variable name in math expression")
+		}
+		return &varExpr{name: name}, nil
+	}
+
+	if value, err := strconv.ParseFloat(token, 64); err == nil {
+		return &numberExpr{value: value}, nil
+	}
+
+	if !isMathFunc(token) {
+		return nil, fmt.Errorf("Unknown function %q in math expression", token)
+	}
+
+	if p.next() != "(" {
+		return nil, fmt.Errorf("Function %s requires arguments", token)
+	}
+
+	arg, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.next() != ")" {
+		return nil, fmt.Errorf("Function %s takes a single argument", token)
+	}
+
+	return &funcExpr{name: token, arg: arg}, nil
+}
+
+// mathTransformation adds the result of a math expression on the series of
+// the results of a request, e.g. `$A / $B * 100`, as a new result.
+type mathTransformation struct {
+	expression string
+	as         string
+	expr       MathExpr
+}
+
+func newMathTransformation(model *simplejson.Json) (Transformation, error) {
+	t := &mathTransformation{
+		expression: model.Get("expression").MustString(),
+		as:         model.Get("as").MustString("math"),
+	}
+
+	expr, err := ParseMathExpression(t.expression)
+	if err != nil {
+		return nil, err
+	}
+	t.expr = expr
+
+	return t, nil
+}
+
+func (t *mathTransformation) Transform(req *TsdbQuery, resp *Response) error {
+	vars := make(map[string]TimeSeriesSlice)
+	for _, name := range t.expr.Vars() {
+		result, exists := resp.Results[name]
+		if !exists {
+			return fmt.Errorf("Math expression references unknown query $%s", name)
+		}
+		vars[name] = result.Series
+	}
+
+	value, err := t.expr.Eval(vars)
+	if err != nil {
+		return err
+	}
+
+	result := NewQueryResult()
+	result.RefId = t.as
+	if value.IsScalar {
+		result.Series = append(result.Series, NewTimeSeries(t.expression, TimeSeriesPoints{NewTimePoint(value.Scalar, 0)}))
+	} else {
+		result.Series = value.Series
+	}
+
+	resp.Results[t.as] = result
+	return nil
+}
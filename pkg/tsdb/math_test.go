@@ -0,0 +1,134 @@
+package tsdb
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMathExpression(t *testing.T) {
+	Convey("Test math expressions", t, func() {
+		vars := map[string]TimeSeriesSlice{
+			"A": {
+				&TimeSeries{Name: "errors", Tags: map[string]string{"host": "a"}, Points: NewTimeSeriesPointsFromArgs(5, 1, 10, 2)},
+				&TimeSeries{Name: "errors", Tags: map[string]string{"host": "b"}, Points: NewTimeSeriesPointsFromArgs(1, 1, 2, 2)},
+			},
+			"B": {
+				&TimeSeries{Name: "requests", Tags: map[string]string{"host": "b"}, Points: NewTimeSeriesPointsFromArgs(10, 1, 0, 2)},
+				&TimeSeries{Name: "requests", Tags: map[string]string{"host": "a"}, Points: NewTimeSeriesPointsFromArgs(100, 1, 200, 2)},
+			},
+			"C": {
+				NewTimeSeries("total", NewTimeSeriesPointsFromArgs(50, 1, 20, 2, 30, 3)),
+			},
+		}
+
+		eval := func(text string) *MathValue {
+			expr, err := ParseMathExpression(text)
+			So(err, ShouldBeNil)
+
+			value, err := expr.Eval(vars)
+			So(err, ShouldBeNil)
+			return value
+		}
+
+		Convey("Should respect operator precedence", func() {
+			value := eval("1 + 2 * 3 - -4 / 2")
+			So(value.IsScalar, ShouldBeTrue)
+			So(value.Scalar.Float64, ShouldEqual, 9)
+
+			value = eval("(1 + 2) * 3")
+			So(value.Scalar.Float64, ShouldEqual, 9)
+		})
+
+		Convey("Should match series by tags", func() {
+			value := eval("$A / $B * 100")
+			So(len(value.Series), ShouldEqual, 2)
+
+			So(value.Series[0].Tags["host"], ShouldEqual, "a")
+			So(value.Series[0].Points[0][0].Float64, ShouldEqual, 5)
+			So(value.Series[0].Points[1][0].Float64, ShouldEqual, 5)
+
+			So(value.Series[1].Tags["host"], ShouldEqual, "b")
+			So(value.Series[1].Points[0][0].Float64, ShouldEqual, 10)
+			So(value.Series[1].Points[1][0].Valid, ShouldBeFalse)
+		})
+
+		Convey("Should combine every series with a single series", func() {
+			value := eval("${A} - $C")
+			So(len(value.Series), ShouldEqual, 2)
+			So(len(value.Series[0].Points), ShouldEqual, 2)
+			So(value.Series[0].Points[0][0].Float64, ShouldEqual, -45)
+			So(value.Series[0].Points[1][0].Float64, ShouldEqual, -10)
+		})
+
+		Convey("Should combine reduced series with every point", func() {
+			value := eval("$C - avg($C)")
+			So(len(value.Series), ShouldEqual, 1)
+			So(len(value.Series[0].Points), ShouldEqual, 3)
+			So(value.Series[0].Points[0][0].Float64, ShouldEqual, 50-float64(100)/3)
+		})
+
+		Convey("Should reduce series with functions", func() {
+			value := eval("abs(min($C) - max($C))")
+			So(len(value.Series), ShouldEqual, 1)
+			So(len(value.Series[0].Points), ShouldEqual, 1)
+			So(value.Series[0].Points[0][0].Float64, ShouldEqual, 30)
+			So(value.Series[0].Points[0][1].Float64, ShouldEqual, 3)
+
+			value = eval("p50($C) + stddev($C) * 0")
+			So(value.Series[0].Points[0][0].Float64, ShouldEqual, 30)
+		})
+
+		Convey("Should return variables used", func() {
+			expr, err := ParseMathExpression("$A / ($B + avg($C))")
+			So(err, ShouldBeNil)
+			So(expr.Vars(), ShouldResemble, []string{"A", "B", "C"})
+		})
+
+		Convey("Should fail on invalid expressions", func() {
+			for _, text := range []string{"", "$A +", "($A", "foo($A)", "avg $A", "$A $B", "$"} {
+				_, err := ParseMathExpression(text)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestMathTransformation(t *testing.T) {
+	Convey("Math transformation", t, func() {
+		resp := &Response{
+			Results: map[string]*QueryResult{
+				"A": {RefId: "A", Series: TimeSeriesSlice{NewTimeSeries("errors", NewTimeSeriesPointsFromArgs(5, 1, 20, 2))}},
+				"B": {RefId: "B", Series: TimeSeriesSlice{NewTimeSeries("requests", NewTimeSeriesPointsFromArgs(100, 1, 100, 2))}},
+			},
+		}
+
+		newTransformation := func(model string) (Transformation, error) {
+			json, err := simplejson.NewJson([]byte(model))
+			So(err, ShouldBeNil)
+			return NewTransformation(json)
+		}
+
+		Convey("Adds the result of the expression", func() {
+			transformation, err := newTransformation(`{"type": "math", "expression": "$A / $B * 100", "as": "ratio"}`)
+			So(err, ShouldBeNil)
+
+			So(transformation.Transform(&TsdbQuery{}, resp), ShouldBeNil)
+			So(resp.Results, ShouldHaveLength, 3)
+			So(resp.Results["ratio"].Series[0].Points[1][0].Float64, ShouldEqual, 20)
+		})
+
+		Convey("Fails on unknown results", func() {
+			transformation, err := newTransformation(`{"type": "math", "expression": "$A / $C"}`)
+			So(err, ShouldBeNil)
+
+			So(transformation.Transform(&TsdbQuery{}, resp), ShouldNotBeNil)
+		})
+
+		Convey("Fails on invalid expressions", func() {
+			_, err := newTransformation(`{"type": "math", "expression": "$A / ("}`)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
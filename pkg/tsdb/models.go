@@ -7,8 +7,10 @@ import (
 )
 
 type TsdbQuery struct {
-	TimeRange *TimeRange
-	Queries   []*Query
+	TimeRange       *TimeRange
+	Queries         []*Query
+	SkipCache       bool
+	Transformations []Transformation
 }
 
 type Query struct {
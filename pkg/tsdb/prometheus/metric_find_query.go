@@ -0,0 +1,278 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/tsdb"
+	api "github.com/prometheus/client_golang/api"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	epLabels      = "/api/v1/labels"
+	epLabelValues = "/api/v1/label/:name/values"
+	epSeries      = "/api/v1/series"
+)
+
+var (
+	labelNamesQuery  = regexp.MustCompile(`^label_names\(\)\s*$`)
+	labelValuesQuery = regexp.MustCompile(`^label_values\((?:(.+),\s*)?([a-zA-Z_][a-zA-Z0-9_]*)\)\s*$`)
+	metricNamesQuery = regexp.MustCompile(`^metrics\((.+)\)\s*$`)
+	queryResultQuery = regexp.MustCompile(`^query_result\((.+)\)\s*$`)
+)
+
+type suggestData struct {
+	Text  string
+	Value string
+}
+
+// executeMetricFindQuery resolves a template variable query like
+// label_values(up, job) with the metadata endpoints of the Prometheus api,
+// so that variables can be resolved on the server. Queries that are not a
+// function return the series matching the query.
+func (e *PrometheusExecutor) executeMetricFindQuery(ctx context.Context, dsInfo *models.DataSource, tsdbQuery *tsdb.TsdbQuery) (*tsdb.Response, error) {
+	result := &tsdb.Response{
+		Results: make(map[string]*tsdb.QueryResult),
+	}
+	firstQuery := tsdbQuery.Queries[0]
+	queryResult := &tsdb.QueryResult{Meta: simplejson.New(), RefId: firstQuery.RefId}
+
+	query := strings.TrimSpace(firstQuery.Model.Get("query").MustString())
+	if query == "" {
+		return nil, fmt.Errorf("query is missing")
+	}
+
+	client, err := e.getApiClient(dsInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	if tsdbQuery.TimeRange != nil {
+		params.Set("start", strconv.FormatInt(tsdbQuery.TimeRange.GetFromAsSecondsEpoch(), 10))
+		params.Set("end", strconv.FormatInt(tsdbQuery.TimeRange.GetToAsSecondsEpoch(), 10))
+	}
+
+	var data []suggestData
+	if labelNamesQuery.MatchString(query) {
+		data, err = labelNames(ctx, client, params)
+	} else if match := labelValuesQuery.FindStringSubmatch(query); match != nil {
+		if match[1] == "" {
+			data, err = labelValues(ctx, client, match[2], params)
+		} else {
+			data, err = seriesLabelValues(ctx, client, match[1], match[2], params)
+		}
+	} else if match := metricNamesQuery.FindStringSubmatch(query); match != nil {
+		data, err = metricNames(ctx, client, match[1], params)
+	} else if match := queryResultQuery.FindStringSubmatch(query); match != nil {
+		data, err = e.queryResultValues(ctx, dsInfo, tsdbQuery, match[1])
+	} else {
+		data, err = seriesNames(ctx, client, query, params)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	transformToSuggestTable(data, queryResult)
+	result.Results[firstQuery.RefId] = queryResult
+	return result, nil
+}
+
+func labelNames(ctx context.Context, client api.Client, params url.Values) ([]suggestData, error) {
+	var names []string
+	if err := apiRequest(ctx, client, epLabels, nil, params, &names); err != nil {
+		return nil, err
+	}
+
+	return newSuggestData(names), nil
+}
+
+func labelValues(ctx context.Context, client api.Client, label string, params url.Values) ([]suggestData, error) {
+	var values []string
+	if err := apiRequest(ctx, client, epLabelValues, map[string]string{"name": label}, params, &values); err != nil {
+		return nil, err
+	}
+
+	return newSuggestData(values), nil
+}
+
+// seriesLabelValues returns the values of a label of the series matching the
+// selector.
+func seriesLabelValues(ctx context.Context, client api.Client, selector string, label string, params url.Values) ([]suggestData, error) {
+	series, err := findSeries(ctx, client, selector, params)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, metric := range series {
+		value, exists := metric[model.LabelName(label)]
+		if exists && !seen[string(value)] {
+			seen[string(value)] = true
+			values = append(values, string(value))
+		}
+	}
+
+	sort.Strings(values)
+	return newSuggestData(values), nil
+}
+
+func metricNames(ctx context.Context, client api.Client, pattern string, params url.Values) ([]suggestData, error) {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if err := apiRequest(ctx, client, epLabelValues, map[string]string{"name": model.MetricNameLabel}, params, &names); err != nil {
+		return nil, err
+	}
+
+	matches := make([]string, 0)
+	for _, name := range names {
+		if regex.MatchString(name) {
+			matches = append(matches, name)
+		}
+	}
+
+	return newSuggestData(matches), nil
+}
+
+func seriesNames(ctx context.Context, client api.Client, selector string, params url.Values) ([]suggestData, error) {
+	series, err := findSeries(ctx, client, selector, params)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]suggestData, 0, len(series))
+	for _, metric := range series {
+		data = append(data, suggestData{Text: metric.String(), Value: metric.String()})
+	}
+
+	return data, nil
+}
+
+// queryResultValues returns a row per sample of an instant query at the end of
+// the time range, with the metric, value and timestamp of the sample.
+func (e *PrometheusExecutor) queryResultValues(ctx context.Context, dsInfo *models.DataSource, tsdbQuery *tsdb.TsdbQuery, expr string) ([]suggestData, error) {
+	client, err := e.getClient(dsInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := tsdbQuery.TimeRange.ParseTo()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := client.Query(ctx, expr, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples model.Vector
+	switch data := value.(type) {
+	case model.Vector:
+		samples = data
+	case *model.Scalar:
+		samples = model.Vector{&model.Sample{Metric: model.Metric{}, Value: data.Value, Timestamp: data.Timestamp}}
+	default:
+		return nil, fmt.Errorf("Unsupported result format: %s", value.Type().String())
+	}
+
+	data := make([]suggestData, 0, len(samples))
+	for _, sample := range samples {
+		text := fmt.Sprintf("%s %s %d", sample.Metric.String(), sample.Value.String(), sample.Timestamp)
+		data = append(data, suggestData{Text: text, Value: text})
+	}
+
+	return data, nil
+}
+
+func findSeries(ctx context.Context, client api.Client, selector string, params url.Values) ([]model.Metric, error) {
+	seriesParams := url.Values{"match[]": []string{selector}}
+	for name, values := range params {
+		seriesParams[name] = values
+	}
+
+	var series []model.Metric
+	if err := apiRequest(ctx, client, epSeries, nil, seriesParams, &series); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// apiRequest sends a request to an endpoint of the Prometheus api and
+// decodes the data of the response into result.
+func apiRequest(ctx context.Context, client api.Client, ep string, args map[string]string, params url.Values, result interface{}) error {
+	u := client.URL(ep, args)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	plog.Debug("Sending metadata request", "url", u.String())
+
+	resp, body, err := client.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Status    string          `json:"status"`
+		Data      json.RawMessage `json:"data"`
+		ErrorType string          `json:"errorType"`
+		Error     string          `json:"error"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil || response.Status != "success" {
+		if response.Error != "" {
+			return fmt.Errorf("%s: %s", response.ErrorType, response.Error)
+		}
+		return fmt.Errorf("Request to %s failed with status %d", ep, resp.StatusCode)
+	}
+
+	return json.Unmarshal(response.Data, result)
+}
+
+func newSuggestData(values []string) []suggestData {
+	data := make([]suggestData, 0, len(values))
+	for _, value := range values {
+		data = append(data, suggestData{Text: value, Value: value})
+	}
+
+	return data
+}
+
+func transformToSuggestTable(data []suggestData, result *tsdb.QueryResult) {
+	table := &tsdb.Table{
+		Columns: make([]tsdb.TableColumn, 2),
+		Rows:    make([]tsdb.RowValues, 0),
+	}
+	table.Columns[0].Text = "text"
+	table.Columns[1].Text = "value"
+
+	for _, r := range data {
+		values := make([]interface{}, 2)
+		values[0] = r.Text
+		values[1] = r.Value
+		table.Rows = append(table.Rows, values)
+	}
+	result.Tables = append(result.Tables, table)
+	result.Meta.Set("rowCount", len(data))
+}
@@ -3,7 +3,10 @@ package prometheus
 import (
 	"context"
 	"fmt"
+	"math"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -60,7 +63,20 @@ func init() {
 	intervalCalculator = tsdb.NewIntervalCalculator(&tsdb.IntervalOptions{MinInterval: time.Second * 1})
 }
 
+// maxDataPoints is the maximum number of points per series Prometheus
+// returns for range queries.
+const maxDataPoints = 11000
+
 func (e *PrometheusExecutor) getClient(dsInfo *models.DataSource) (apiv1.API, error) {
+	client, err := e.getApiClient(dsInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiv1.NewAPI(client), nil
+}
+
+func (e *PrometheusExecutor) getApiClient(dsInfo *models.DataSource) (api.Client, error) {
 	cfg := api.Config{
 		Address:      dsInfo.Url,
 		RoundTripper: e.Transport,
@@ -74,12 +90,7 @@ func (e *PrometheusExecutor) getClient(dsInfo *models.DataSource) (apiv1.API, er
 		}
 	}
 
-	client, err := api.NewClient(cfg)
-	if err != nil {
-		return nil, err
-	}
-
-	return apiv1.NewAPI(client), nil
+	return api.NewClient(cfg)
 }
 
 func (e *PrometheusExecutor) Query(ctx context.Context, dsInfo *models.DataSource, tsdbQuery *tsdb.TsdbQuery) (*tsdb.Response, error) {
@@ -87,6 +98,10 @@ func (e *PrometheusExecutor) Query(ctx context.Context, dsInfo *models.DataSourc
 		Results: map[string]*tsdb.QueryResult{},
 	}
 
+	if len(tsdbQuery.Queries) > 0 && tsdbQuery.Queries[0].Model.Get("type").MustString("") == "metricFindQuery" {
+		return e.executeMetricFindQuery(ctx, dsInfo, tsdbQuery)
+	}
+
 	client, err := e.getClient(dsInfo)
 	if err != nil {
 		return nil, err
@@ -98,21 +113,26 @@ func (e *PrometheusExecutor) Query(ctx context.Context, dsInfo *models.DataSourc
 	}
 
 	for _, query := range queries {
-		timeRange := apiv1.Range{
-			Start: query.Start,
-			End:   query.End,
-			Step:  query.Step,
-		}
-
-		plog.Debug("Sending query", "start", timeRange.Start, "end", timeRange.End, "step", timeRange.Step, "query", query.Expr)
-
 		span, ctx := opentracing.StartSpanFromContext(ctx, "alerting.prometheus")
 		span.SetTag("expr", query.Expr)
 		span.SetTag("start_unixnano", query.Start.UnixNano())
 		span.SetTag("stop_unixnano", query.End.UnixNano())
 		defer span.Finish()
 
-		value, err := client.QueryRange(ctx, query.Expr, timeRange)
+		var value model.Value
+		if query.Instant {
+			plog.Debug("Sending instant query", "time", query.End, "query", query.Expr)
+			value, err = client.Query(ctx, query.Expr, query.End)
+		} else {
+			timeRange := apiv1.Range{
+				Start: query.Start,
+				End:   query.End,
+				Step:  query.Step,
+			}
+
+			plog.Debug("Sending query", "start", timeRange.Start, "end", timeRange.End, "step", timeRange.Step, "query", query.Expr)
+			value, err = client.QueryRange(ctx, query.Expr, timeRange)
+		}
 
 		if err != nil {
 			return nil, err
@@ -155,7 +175,9 @@ func parseQuery(dsInfo *models.DataSource, queries []*tsdb.Query, queryContext *
 			return nil, err
 		}
 
-		format := queryModel.Model.Get("legendFormat").MustString("")
+		legend := queryModel.Model.Get("legendFormat").MustString("")
+		format := queryModel.Model.Get("format").MustString("time_series")
+		instant := queryModel.Model.Get("instant").MustBool(false)
 
 		start, err := queryContext.TimeRange.ParseFrom()
 		if err != nil {
@@ -176,10 +198,21 @@ func parseQuery(dsInfo *models.DataSource, queries []*tsdb.Query, queryContext *
 		interval := intervalCalculator.Calculate(queryContext.TimeRange, dsInterval)
 		step := time.Duration(int64(interval.Value) * intervalFactor)
 
+		// Prometheus rejects range queries with too many points per series.
+		if minStep := end.Sub(start) / maxDataPoints; step < minStep {
+			step = (minStep/time.Second + 1) * time.Second
+		}
+
+		if !instant {
+			start, end = alignTimeRange(start, end, step)
+		}
+
 		qs = append(qs, &PrometheusQuery{
 			Expr:         expr,
 			Step:         step,
-			LegendFormat: format,
+			LegendFormat: legend,
+			Format:       format,
+			Instant:      instant,
 			Start:        start,
 			End:          end,
 			RefId:        queryModel.RefId,
@@ -189,30 +222,183 @@ func parseQuery(dsInfo *models.DataSource, queries []*tsdb.Query, queryContext *
 	return qs, nil
 }
 
+// alignTimeRange aligns the start and end of a range query down to multiples
+// of the step, so that the same points are requested while the time range
+// moves and the responses can be cached. The end is never moved into the
+// future, where there are no samples yet.
+func alignTimeRange(start time.Time, end time.Time, step time.Duration) (time.Time, time.Time) {
+	if step <= 0 {
+		return start, end
+	}
+
+	alignedStart := start.UnixNano() / int64(step) * int64(step)
+	alignedEnd := end.UnixNano() / int64(step) * int64(step)
+
+	return time.Unix(0, alignedStart), time.Unix(0, alignedEnd)
+}
+
 func parseResponse(value model.Value, query *PrometheusQuery) (*tsdb.QueryResult, error) {
 	queryRes := tsdb.NewQueryResult()
 
-	data, ok := value.(model.Matrix)
-	if !ok {
+	if query.Format == "table" {
+		table, err := transformToTable(value)
+		if err != nil {
+			return queryRes, err
+		}
+
+		queryRes.Tables = append(queryRes.Tables, table)
+		return queryRes, nil
+	}
+
+	switch data := value.(type) {
+	case model.Matrix:
+		for _, v := range data {
+			series := newTimeSeries(v.Metric, query)
+			for _, k := range v.Values {
+				series.Points = append(series.Points, tsdb.NewTimePoint(sampleValue(k.Value), float64(k.Timestamp.Unix()*1000)))
+			}
+
+			queryRes.Series = append(queryRes.Series, series)
+		}
+	case model.Vector:
+		for _, v := range data {
+			series := newTimeSeries(v.Metric, query)
+			series.Points = append(series.Points, tsdb.NewTimePoint(sampleValue(v.Value), float64(v.Timestamp)))
+
+			queryRes.Series = append(queryRes.Series, series)
+		}
+	case *model.Scalar:
+		queryRes.Series = append(queryRes.Series, &tsdb.TimeSeries{
+			Name:   query.Expr,
+			Tags:   map[string]string{},
+			Points: tsdb.TimeSeriesPoints{tsdb.NewTimePoint(sampleValue(data.Value), float64(data.Timestamp))},
+		})
+	default:
 		return queryRes, fmt.Errorf("Unsupported result format: %s", value.Type().String())
 	}
 
-	for _, v := range data {
-		series := tsdb.TimeSeries{
-			Name: formatLegend(v.Metric, query),
-			Tags: map[string]string{},
+	if query.Format == "heatmap" {
+		transformToHeatmap(queryRes.Series)
+	}
+
+	return queryRes, nil
+}
+
+func newTimeSeries(metric model.Metric, query *PrometheusQuery) *tsdb.TimeSeries {
+	series := &tsdb.TimeSeries{
+		Name: formatLegend(metric, query),
+		Tags: map[string]string{},
+	}
+
+	for k, v := range metric {
+		series.Tags[string(k)] = string(v)
+	}
+
+	return series
+}
+
+// sampleValue returns null for values that cannot be encoded as json.
+func sampleValue(value model.SampleValue) null.Float {
+	v := float64(value)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return null.FloatFromPtr(nil)
+	}
+
+	return null.FloatFrom(v)
+}
+
+// transformToTable returns a table with a time column, a column per label
+// and a value column, with a row per sample of the result.
+func transformToTable(value model.Value) (*tsdb.Table, error) {
+	samples := make([]*model.Sample, 0)
+
+	switch data := value.(type) {
+	case model.Matrix:
+		for _, v := range data {
+			for _, k := range v.Values {
+				samples = append(samples, &model.Sample{Metric: v.Metric, Value: k.Value, Timestamp: k.Timestamp})
+			}
+		}
+	case model.Vector:
+		samples = append(samples, data...)
+	case *model.Scalar:
+		samples = append(samples, &model.Sample{Metric: model.Metric{}, Value: data.Value, Timestamp: data.Timestamp})
+	default:
+		return nil, fmt.Errorf("Unsupported result format: %s", value.Type().String())
+	}
+
+	labels := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, sample := range samples {
+		for name := range sample.Metric {
+			if !seen[string(name)] {
+				seen[string(name)] = true
+				labels = append(labels, string(name))
+			}
+		}
+	}
+	sort.Strings(labels)
+
+	table := &tsdb.Table{
+		Columns: []tsdb.TableColumn{{Text: "Time"}},
+		Rows:    make([]tsdb.RowValues, 0),
+	}
+	for _, label := range labels {
+		table.Columns = append(table.Columns, tsdb.TableColumn{Text: label})
+	}
+	table.Columns = append(table.Columns, tsdb.TableColumn{Text: "Value"})
+
+	for _, sample := range samples {
+		row := make(tsdb.RowValues, 0, len(table.Columns))
+		row = append(row, float64(sample.Timestamp))
+		for _, label := range labels {
+			row = append(row, string(sample.Metric[model.LabelName(label)]))
 		}
 
-		for k, v := range v.Metric {
-			series.Tags[string(k)] = string(v)
+		if value := sampleValue(sample.Value); value.Valid {
+			row = append(row, value.Float64)
+		} else {
+			row = append(row, nil)
 		}
 
-		for _, k := range v.Values {
-			series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(float64(k.Value)), float64(k.Timestamp.Unix()*1000)))
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
+
+// transformToHeatmap sorts the series of a histogram by their bucket bound
+// and converts the cumulative bucket counts to the counts of each bucket.
+func transformToHeatmap(series tsdb.TimeSeriesSlice) {
+	sort.SliceStable(series, func(i, j int) bool {
+		return bucketBound(series[i]) < bucketBound(series[j])
+	})
+
+	for i := len(series) - 1; i > 0; i-- {
+		current := series[i].Points
+		previous := series[i-1].Points
+		if len(current) != len(previous) {
+			continue
 		}
 
-		queryRes.Series = append(queryRes.Series, &series)
+		for j := range current {
+			if current[j][0].Valid && previous[j][0].Valid {
+				current[j][0] = null.FloatFrom(current[j][0].Float64 - previous[j][0].Float64)
+			}
+		}
 	}
+}
 
-	return queryRes, nil
+func bucketBound(series *tsdb.TimeSeries) float64 {
+	le, exists := series.Tags[model.BucketLabel]
+	if !exists {
+		le = series.Name
+	}
+
+	bound, err := strconv.ParseFloat(le, 64)
+	if err != nil {
+		return math.Inf(1)
+	}
+
+	return bound
 }
@@ -1,12 +1,19 @@
 package prometheus
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/tsdb"
 
+	"github.com/grafana/grafana/pkg/components/null"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	p "github.com/prometheus/common/model"
 	. "github.com/smartystreets/goconvey/convey"
@@ -156,5 +163,212 @@ func TestPrometheus(t *testing.T) {
 			})
 		})
 
+		Convey("parsing query model with a time range", func() {
+			json := `{"expr": "go_goroutines", "format": "table", "instant": true}`
+			jsonModel, _ := simplejson.NewJson([]byte(json))
+			now := time.Unix(1500000007, 0)
+			queryContext := &tsdb.TsdbQuery{TimeRange: tsdb.NewFakeTimeRange("1h", "now", now)}
+
+			Convey("aligns range queries to the step", func() {
+				jsonModel.Del("instant")
+				models, err := parseQuery(dsInfo, []*tsdb.Query{{Model: jsonModel}}, queryContext)
+
+				So(err, ShouldBeNil)
+				So(models[0].Step, ShouldEqual, time.Second*15)
+				So(models[0].Start.Unix(), ShouldEqual, 1499996400)
+				So(models[0].End.Unix(), ShouldEqual, 1500000000)
+			})
+
+			Convey("keeps the end of instant queries", func() {
+				models, err := parseQuery(dsInfo, []*tsdb.Query{{Model: jsonModel}}, queryContext)
+
+				So(err, ShouldBeNil)
+				So(models[0].Instant, ShouldBeTrue)
+				So(models[0].Format, ShouldEqual, "table")
+				So(models[0].End.Unix(), ShouldEqual, 1500000007)
+			})
+
+			Convey("limits the number of points", func() {
+				queryContext.TimeRange = tsdb.NewFakeTimeRange("2160h", "now", now)
+				jsonModel.Set("interval", "1s")
+				models, err := parseQuery(dsInfo, []*tsdb.Query{{Model: jsonModel}}, queryContext)
+
+				So(err, ShouldBeNil)
+				So(models[0].Step, ShouldBeGreaterThanOrEqualTo, 90*24*time.Hour/maxDataPoints)
+			})
+		})
+
+		Convey("parsing responses", func() {
+			metric := p.Metric{"__name__": "up", "job": "app", "instance": "web-1"}
+
+			Convey("converts instant vectors to series", func() {
+				vector := p.Vector{&p.Sample{Metric: metric, Value: 1, Timestamp: 1500000000500}}
+				res, err := parseResponse(vector, &PrometheusQuery{LegendFormat: "{{instance}}"})
+
+				So(err, ShouldBeNil)
+				So(res.Series, ShouldHaveLength, 1)
+				So(res.Series[0].Name, ShouldEqual, "web-1")
+				So(res.Series[0].Points, ShouldResemble, tsdb.TimeSeriesPoints{tsdb.NewTimePoint(null.FloatFrom(1), 1500000000500)})
+			})
+
+			Convey("converts scalars to series", func() {
+				res, err := parseResponse(&p.Scalar{Value: 42, Timestamp: 1500000000000}, &PrometheusQuery{Expr: "vector(42)"})
+
+				So(err, ShouldBeNil)
+				So(res.Series[0].Name, ShouldEqual, "vector(42)")
+				So(res.Series[0].Points[0][0].Float64, ShouldEqual, 42)
+			})
+
+			Convey("converts results to tables", func() {
+				matrix := p.Matrix{
+					&p.SampleStream{Metric: metric, Values: []p.SamplePair{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: p.SampleValue(math.NaN())}}},
+					&p.SampleStream{Metric: p.Metric{"__name__": "up", "job": "db"}, Values: []p.SamplePair{{Timestamp: 1000, Value: 0}}},
+				}
+				res, err := parseResponse(matrix, &PrometheusQuery{Format: "table"})
+
+				So(err, ShouldBeNil)
+				So(res.Series, ShouldHaveLength, 0)
+				table := res.Tables[0]
+				So(table.Columns, ShouldResemble, []tsdb.TableColumn{{Text: "Time"}, {Text: "__name__"}, {Text: "instance"}, {Text: "job"}, {Text: "Value"}})
+				So(table.Rows, ShouldResemble, []tsdb.RowValues{
+					{float64(1000), "up", "web-1", "app", float64(1)},
+					{float64(2000), "up", "web-1", "app", nil},
+					{float64(1000), "up", "", "db", float64(0)},
+				})
+			})
+
+			Convey("converts histograms to heatmap buckets", func() {
+				bucket := func(le string, values ...float64) *p.SampleStream {
+					stream := &p.SampleStream{Metric: p.Metric{"le": p.LabelValue(le)}}
+					for i, v := range values {
+						stream.Values = append(stream.Values, p.SamplePair{Timestamp: p.Time(i * 1000), Value: p.SampleValue(v)})
+					}
+					return stream
+				}
+				matrix := p.Matrix{bucket("+Inf", 10, 20), bucket("0.5", 4, 5), bucket("0.1", 1, 2)}
+				res, err := parseResponse(matrix, &PrometheusQuery{Format: "heatmap", LegendFormat: "{{le}}"})
+
+				So(err, ShouldBeNil)
+				So(res.Series[0].Name, ShouldEqual, "0.1")
+				So(res.Series[1].Name, ShouldEqual, "0.5")
+				So(res.Series[2].Name, ShouldEqual, "+Inf")
+				So(res.Series[0].Points[1][0].Float64, ShouldEqual, 2)
+				So(res.Series[1].Points[1][0].Float64, ShouldEqual, 3)
+				So(res.Series[2].Points[1][0].Float64, ShouldEqual, 15)
+			})
+		})
+	})
+
+	Convey("Querying Prometheus", t, func() {
+		var requestPath string
+		var request url.Values
+		body := ""
+
+		ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			requestPath = r.URL.Path
+			request = r.URL.Query()
+			rw.Header().Set("Content-Type", "application/json")
+			if body == "" {
+				rw.WriteHeader(422)
+				fmt.Fprint(rw, `{"status": "error", "errorType": "bad_data", "error": "parse error"}`)
+				return
+			}
+			fmt.Fprint(rw, `{"status": "success", "data": `+body+`}`)
+		}))
+		defer ts.Close()
+
+		dsInfo := &models.DataSource{Url: ts.URL, JsonData: simplejson.New()}
+		exec, err := NewPrometheusExecutor(dsInfo)
+		So(err, ShouldBeNil)
+
+		query := func(model string) (*tsdb.Response, error) {
+			json, err := simplejson.NewJson([]byte(model))
+			So(err, ShouldBeNil)
+
+			return exec.Query(context.TODO(), dsInfo, &tsdb.TsdbQuery{
+				TimeRange: tsdb.NewFakeTimeRange("1h", "now", time.Unix(1500000000, 0)),
+				Queries:   []*tsdb.Query{{RefId: "A", Model: json}},
+			})
+		}
+
+		Convey("sends instant queries", func() {
+			body = `{"resultType": "vector", "result": [{"metric": {"job": "app"}, "value": [1500000000, "3"]}]}`
+			resp, err := query(`{"expr": "up", "instant": true, "format": "table"}`)
+
+			So(err, ShouldBeNil)
+			So(requestPath, ShouldEqual, "/api/v1/query")
+			So(request.Get("query"), ShouldEqual, "up")
+			So(resp.Results["A"].Tables[0].Rows, ShouldResemble, []tsdb.RowValues{{float64(1500000000000), "app", float64(3)}})
+		})
+
+		metricFindQuery := func(expr string) ([]tsdb.RowValues, error) {
+			resp, err := query(`{"type": "metricFindQuery", "query": "` + expr + `"}`)
+			if err != nil {
+				return nil, err
+			}
+			return resp.Results["A"].Tables[0].Rows, nil
+		}
+
+		Convey("resolves label names", func() {
+			body = `["__name__", "job"]`
+			rows, err := metricFindQuery("label_names()")
+
+			So(err, ShouldBeNil)
+			So(requestPath, ShouldEqual, "/api/v1/labels")
+			So(rows, ShouldResemble, []tsdb.RowValues{{"__name__", "__name__"}, {"job", "job"}})
+		})
+
+		Convey("resolves label values", func() {
+			body = `["app", "db"]`
+			rows, err := metricFindQuery("label_values(job)")
+
+			So(err, ShouldBeNil)
+			So(requestPath, ShouldEqual, "/api/v1/label/job/values")
+			So(request.Get("start"), ShouldEqual, "1499996400")
+			So(rows, ShouldHaveLength, 2)
+		})
+
+		Convey("resolves label values of a metric", func() {
+			body = `[{"__name__": "up", "job": "db"}, {"__name__": "up", "job": "app"}, {"__name__": "up", "job": "app"}]`
+			rows, err := metricFindQuery("label_values(up, job)")
+
+			So(err, ShouldBeNil)
+			So(requestPath, ShouldEqual, "/api/v1/series")
+			So(request.Get("match[]"), ShouldEqual, "up")
+			So(rows, ShouldResemble, []tsdb.RowValues{{"app", "app"}, {"db", "db"}})
+		})
+
+		Convey("resolves metric names", func() {
+			body = `["go_goroutines", "up"]`
+			rows, err := metricFindQuery("metrics(^go_)")
+
+			So(err, ShouldBeNil)
+			So(requestPath, ShouldEqual, "/api/v1/label/__name__/values")
+			So(rows, ShouldResemble, []tsdb.RowValues{{"go_goroutines", "go_goroutines"}})
+		})
+
+		Convey("resolves series", func() {
+			body = `[{"__name__": "up", "job": "app"}]`
+			rows, err := metricFindQuery("up")
+
+			So(err, ShouldBeNil)
+			So(rows[0][0], ShouldEqual, `up{job="app"}`)
+		})
+
+		Convey("resolves query results", func() {
+			body = `{"resultType": "vector", "result": [{"metric": {"job": "app"}, "value": [1500000000, "3"]}]}`
+			rows, err := metricFindQuery("query_result(sum(up) by (job))")
+
+			So(err, ShouldBeNil)
+			So(requestPath, ShouldEqual, "/api/v1/query")
+			So(rows[0][0], ShouldEqual, `{job="app"} 3 1500000000000`)
+		})
+
+		Convey("returns the error of failed requests", func() {
+			_, err := metricFindQuery("label_values(job)")
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "parse error")
+		})
 	})
 }
@@ -6,6 +6,8 @@ type PrometheusQuery struct {
 	Expr         string
 	Step         time.Duration
 	LegendFormat string
+	Format       string
+	Instant      bool
 	Start        time.Time
 	End          time.Time
 	RefId        string
@@ -0,0 +1,270 @@
+package tsdb
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/components/null"
+)
+
+var percentileShorthand = regexp.MustCompile(`^p(\d+(\.\d+)?)$`)
+
+// ParseReducer returns the reducer and its params for a reducer name.
+// Percentiles can be given in short form, e.g. `p95`.
+func ParseReducer(name string) (string, []float64) {
+	if match := percentileShorthand.FindStringSubmatch(name); match != nil {
+		p, _ := strconv.ParseFloat(match[1], 64)
+		return "percentile", []float64{p}
+	}
+
+	return name, nil
+}
+
+// ReduceSeries reduces a series to a single value. Params holds the
+// arguments of parameterised reducers, e.g. the percentile for
+// `percentile` or the threshold for `count_above`.
+func ReduceSeries(reducer string, params []float64, series *TimeSeries) null.Float {
+	if len(series.Points) == 0 {
+		return null.FloatFromPtr(nil)
+	}
+
+	value := float64(0)
+	allNull := true
+
+	switch reducer {
+	case "avg":
+		validPointsCount := 0
+		for _, point := range series.Points {
+			if point[0].Valid {
+				value += point[0].Float64
+				validPointsCount += 1
+				allNull = false
+			}
+		}
+		if validPointsCount > 0 {
+			value = value / float64(validPointsCount)
+		}
+	case "sum":
+		for _, point := range series.Points {
+			if point[0].Valid {
+				value += point[0].Float64
+				allNull = false
+			}
+		}
+	case "min":
+		value = math.MaxFloat64
+		for _, point := range series.Points {
+			if point[0].Valid {
+				allNull = false
+				if value > point[0].Float64 {
+					value = point[0].Float64
+				}
+			}
+		}
+	case "max":
+		value = -math.MaxFloat64
+		for _, point := range series.Points {
+			if point[0].Valid {
+				allNull = false
+				if value < point[0].Float64 {
+					value = point[0].Float64
+				}
+			}
+		}
+	case "count":
+		value = float64(len(series.Points))
+		allNull = false
+	case "last":
+		points := series.Points
+		for i := len(points) - 1; i >= 0; i-- {
+			if points[i][0].Valid {
+				value = points[i][0].Float64
+				allNull = false
+				break
+			}
+		}
+	case "median":
+		var values []float64
+		for _, v := range series.Points {
+			if v[0].Valid {
+				allNull = false
+				values = append(values, v[0].Float64)
+			}
+		}
+		if len(values) >= 1 {
+			sort.Float64s(values)
+			length := len(values)
+			if length%2 == 1 {
+				value = values[(length-1)/2]
+			} else {
+				value = (values[(length/2)-1] + values[length/2]) / 2
+			}
+		}
+	case "diff":
+		var (
+			points = series.Points
+			first  float64
+			i      int
+		)
+		// get the newest point
+		for i = len(points) - 1; i >= 0; i-- {
+			if points[i][0].Valid {
+				allNull = false
+				first = points[i][0].Float64
+				break
+			}
+		}
+		// get the oldest point
+		points = points[0:i]
+		for i := 0; i < len(points); i++ {
+			if points[i][0].Valid {
+				allNull = false
+				value = first - points[i][0].Float64
+				break
+			}
+		}
+	case "percent_diff":
+		var (
+			points = series.Points
+			first  float64
+			i      int
+		)
+		// get the newest point
+		for i = len(points) - 1; i >= 0; i-- {
+			if points[i][0].Valid {
+				allNull = false
+				first = points[i][0].Float64
+				break
+			}
+		}
+		// get the oldest point
+		points = points[0:i]
+		for i := 0; i < len(points); i++ {
+			if points[i][0].Valid {
+				allNull = false
+				val := (first - points[i][0].Float64) / points[i][0].Float64 * 100
+				value = math.Abs(val)
+				break
+			}
+		}
+	case "count_non_null":
+		for _, v := range series.Points {
+			if v[0].Valid {
+				value++
+			}
+		}
+
+		if value > 0 {
+			allNull = false
+		}
+	case "percentile":
+		values := validValues(series)
+		if len(values) > 0 && len(params) > 0 {
+			allNull = false
+			value = percentile(values, params[0])
+		}
+	case "stddev":
+		values := validValues(series)
+		if len(values) > 0 {
+			allNull = false
+			_, value = MeanAndStdDev(values)
+		}
+	case "rate":
+		var (
+			first, last *TimePoint
+			increase    float64
+		)
+		for i := range series.Points {
+			point := &series.Points[i]
+			if !point[0].Valid {
+				continue
+			}
+
+			if last != nil {
+				// a decreasing counter has been reset and restarted from zero
+				if point[0].Float64 >= last[0].Float64 {
+					increase += point[0].Float64 - last[0].Float64
+				} else {
+					increase += point[0].Float64
+				}
+			} else {
+				first = point
+			}
+			last = point
+		}
+
+		if first != nil && last[1].Float64 > first[1].Float64 {
+			allNull = false
+			value = increase / ((last[1].Float64 - first[1].Float64) / 1000)
+		}
+	case "count_above":
+		values := validValues(series)
+		if len(values) > 0 && len(params) > 0 {
+			allNull = false
+			for _, v := range values {
+				if v > params[0] {
+					value++
+				}
+			}
+		}
+	}
+
+	if allNull {
+		return null.FloatFromPtr(nil)
+	}
+
+	return null.FloatFrom(value)
+}
+
+func validValues(series *TimeSeries) []float64 {
+	values := make([]float64, 0, len(series.Points))
+	for _, point := range series.Points {
+		if point[0].Valid {
+			values = append(values, point[0].Float64)
+		}
+	}
+	return values
+}
+
+// percentile returns the p-th percentile of values, interpolating linearly
+// between the closest ranks.
+func percentile(values []float64, p float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		return sorted[0]
+	}
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// MeanAndStdDev returns the mean and the population standard deviation of
+// values.
+func MeanAndStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	sum := float64(0)
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	variance := float64(0)
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
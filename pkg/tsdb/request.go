@@ -8,11 +8,38 @@ import (
 
 type HandleRequestFunc func(ctx context.Context, dsInfo *models.DataSource, req *TsdbQuery) (*Response, error)
 
+// QueryCache answers requests from earlier responses, next executes the
+// request against the datasource.
+type QueryCache interface {
+	HandleRequest(ctx context.Context, dsInfo *models.DataSource, req *TsdbQuery, next HandleRequestFunc) (*Response, error)
+}
+
+var queryCache QueryCache
+
+// SetQueryCache sets the cache used by HandleRequest, nil disables caching.
+func SetQueryCache(cache QueryCache) {
+	queryCache = cache
+}
+
 func HandleRequest(ctx context.Context, dsInfo *models.DataSource, req *TsdbQuery) (*Response, error) {
 	endpoint, err := getTsdbQueryEndpointFor(dsInfo)
 	if err != nil {
 		return nil, err
 	}
 
-	return endpoint.Query(ctx, dsInfo, req)
+	var resp *Response
+	if queryCache != nil && !req.SkipCache {
+		resp, err = queryCache.HandleRequest(ctx, dsInfo, req, endpoint.Query)
+	} else {
+		resp, err = endpoint.Query(ctx, dsInfo, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyTransformations(req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
 }
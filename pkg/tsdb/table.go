@@ -0,0 +1,243 @@
+package tsdb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// TableSeriesOptions tell how the rows of table results, e.g. from SQL or
+// raw document queries, are turned into series. Rows with the same values
+// in the group by columns form one series.
+type TableSeriesOptions struct {
+	ValueColumn string
+	TimeColumn  string
+	GroupBy     []string
+}
+
+func NewTableSeriesOptions(tableJson *simplejson.Json) (*TableSeriesOptions, error) {
+	options := &TableSeriesOptions{
+		ValueColumn: tableJson.Get("valueColumn").MustString(),
+		TimeColumn:  tableJson.Get("timeColumn").MustString("time"),
+	}
+
+	if groupBy, err := tableJson.Get("groupBy").StringArray(); err == nil {
+		options.GroupBy = groupBy
+	} else if _, hasGroupBy := tableJson.CheckGet("groupBy"); hasGroupBy {
+		return nil, fmt.Errorf("Table group by must be a list of column names")
+	}
+
+	return options, nil
+}
+
+// TableToTimeSeries turns the rows of the table into series. Without group
+// by columns the rows are grouped by their text columns, so every row of a
+// table with one text column is a series of its own. Rows without a time
+// column get the end of the time range as time.
+func TableToTimeSeries(table *Table, options *TableSeriesOptions, timeRange *TimeRange) (TimeSeriesSlice, error) {
+	result := make(TimeSeriesSlice, 0)
+	if len(table.Rows) == 0 {
+		return result, nil
+	}
+
+	timeIndex := -1
+	groupByIndexes := make(map[int]bool)
+	for i, column := range table.Columns {
+		if column.Text == options.TimeColumn {
+			timeIndex = i
+		}
+		for _, name := range options.GroupBy {
+			if column.Text == name {
+				groupByIndexes[i] = true
+			}
+		}
+	}
+
+	if len(groupByIndexes) != len(options.GroupBy) {
+		return nil, fmt.Errorf("Table is missing group by columns %v", options.GroupBy)
+	}
+
+	valueIndex, err := findTableValueColumn(table, options, timeIndex)
+	if err != nil {
+		return nil, err
+	}
+	valueColumn := table.Columns[valueIndex].Text
+
+	seriesByKey := make(map[string]*TimeSeries)
+	for _, row := range table.Rows {
+		if len(row) != len(table.Columns) {
+			continue
+		}
+
+		value, err := tableValueToFloat(valueColumn, row[valueIndex])
+		if err != nil {
+			return nil, err
+		}
+
+		timestamp := float64(timeRange.GetToAsMsEpoch())
+		if timeIndex >= 0 {
+			if timestamp, err = tableTimeToEpochMs(row[timeIndex]); err != nil {
+				return nil, err
+			}
+		}
+
+		tags := make(map[string]string)
+		for i, column := range table.Columns {
+			if i == valueIndex || i == timeIndex {
+				continue
+			}
+			if len(groupByIndexes) > 0 && !groupByIndexes[i] {
+				continue
+			}
+			if text, ok := tableValueToText(row[i], len(groupByIndexes) > 0); ok {
+				tags[column.Text] = text
+			}
+		}
+
+		key := tableSeriesName(valueColumn, tags)
+		series, exists := seriesByKey[key]
+		if !exists {
+			series = &TimeSeries{Name: key, Points: make(TimeSeriesPoints, 0)}
+			if len(tags) > 0 {
+				series.Tags = tags
+			}
+			seriesByKey[key] = series
+			result = append(result, series)
+		}
+
+		series.Points = append(series.Points, NewTimePoint(value, timestamp))
+	}
+
+	return result, nil
+}
+
+// findTableValueColumn returns the index of the value column, the first
+// numeric column when no value column is set.
+func findTableValueColumn(table *Table, options *TableSeriesOptions, timeIndex int) (int, error) {
+	if options.ValueColumn != "" {
+		for i, column := range table.Columns {
+			if column.Text == options.ValueColumn {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("Table is missing value column %s", options.ValueColumn)
+	}
+
+	// rows that do not match the columns are skipped, like when reading the
+	// series
+	firstRow := make(RowValues, 0)
+	for _, row := range table.Rows {
+		if len(row) == len(table.Columns) {
+			firstRow = row
+			break
+		}
+	}
+
+	for i, column := range table.Columns {
+		if i >= len(firstRow) {
+			break
+		}
+		if i == timeIndex || containsString(options.GroupBy, column.Text) {
+			continue
+		}
+		if value, err := tableValueToFloat(column.Text, firstRow[i]); err == nil && value.Valid {
+			return i, nil
+		}
+	}
+
+	return -1, fmt.Errorf("Could not find a numeric value column in table, set the value column")
+}
+
+func tableValueToFloat(columnName string, value interface{}) (null.Float, error) {
+	switch typedValue := value.(type) {
+	case string:
+		return parseTableFloat(columnName, typedValue)
+	case []byte:
+		return parseTableFloat(columnName, string(typedValue))
+	}
+
+	return ConvertSqlValueColumnToFloat(columnName, value)
+}
+
+func parseTableFloat(columnName string, value string) (null.Float, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return null.NewFloat(0, false), fmt.Errorf("Value column must have numeric values, column: %s value: %s", columnName, value)
+	}
+
+	return null.FloatFrom(f), nil
+}
+
+// tableTimeToEpochMs converts the time of a row, epochs or RFC3339 times
+// like in documents of raw queries.
+func tableTimeToEpochMs(value interface{}) (float64, error) {
+	switch typedValue := value.(type) {
+	case time.Time:
+		return float64(typedValue.UnixNano()) / float64(time.Millisecond), nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, typedValue)
+		if err != nil {
+			return 0, fmt.Errorf("Could not parse time column value %s", typedValue)
+		}
+		return float64(t.UnixNano()) / float64(time.Millisecond), nil
+	}
+
+	epoch, err := ConvertSqlValueColumnToFloat("time", value)
+	if err != nil || !epoch.Valid {
+		return 0, fmt.Errorf("Could not parse time column value %v", value)
+	}
+
+	return EpochPrecisionToMs(epoch.Float64), nil
+}
+
+// tableValueToText returns the text of a value for the labels of a series.
+// Only text values identify a series unless the column is grouped by.
+func tableValueToText(value interface{}, grouped bool) (string, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, true
+	case []byte:
+		return string(typedValue), true
+	case nil:
+		return "", grouped
+	}
+
+	if grouped {
+		return fmt.Sprintf("%v", value), true
+	}
+
+	return "", false
+}
+
+func tableSeriesName(valueColumn string, tags map[string]string) string {
+	if len(tags) == 0 {
+		return valueColumn
+	}
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+tags[k])
+	}
+
+	return fmt.Sprintf("%s{%s}", valueColumn, strings.Join(pairs, ", "))
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
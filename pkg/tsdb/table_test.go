@@ -0,0 +1,149 @@
+package tsdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTableToTimeSeries(t *testing.T) {
+	Convey("Converting table results to series", t, func() {
+		now := time.Unix(1500000000, 0)
+		timeRange := NewFakeTimeRange("5m", "now", now)
+
+		tableOptions := func(json string) *TableSeriesOptions {
+			model, err := simplejson.NewJson([]byte(json))
+			So(err, ShouldBeNil)
+			options, err := NewTableSeriesOptions(model)
+			So(err, ShouldBeNil)
+			return options
+		}
+
+		Convey("Can read table options", func() {
+			options := tableOptions(`{"valueColumn": "revenue", "groupBy": ["region", "shop"]}`)
+
+			So(options.ValueColumn, ShouldEqual, "revenue")
+			So(options.TimeColumn, ShouldEqual, "time")
+			So(options.GroupBy, ShouldResemble, []string{"region", "shop"})
+		})
+
+		Convey("Rejects a group by that is not a list", func() {
+			model, _ := simplejson.NewJson([]byte(`{"groupBy": "region"}`))
+			_, err := NewTableSeriesOptions(model)
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Uses the first numeric column and the end of the time range without a time column", func() {
+			table := &Table{
+				Columns: []TableColumn{{Text: "name"}, {Text: "count"}},
+				Rows:    []RowValues{{"errors", int64(12)}},
+			}
+
+			series, err := TableToTimeSeries(table, tableOptions(`{}`), timeRange)
+
+			So(err, ShouldBeNil)
+			So(series, ShouldHaveLength, 1)
+			So(series[0].Name, ShouldEqual, "count{name=errors}")
+			So(series[0].Points[0][0].Float64, ShouldEqual, 12)
+			So(series[0].Points[0][1].Float64, ShouldEqual, now.Unix()*1000)
+		})
+
+		Convey("Groups rows by the group by columns", func() {
+			table := &Table{
+				Columns: []TableColumn{{Text: "time"}, {Text: "region"}, {Text: "shop"}, {Text: "revenue"}},
+				Rows: []RowValues{
+					{float64(1000), "eu", "berlin", "10.5"},
+					{float64(1000), "us", "boston", "20"},
+					{float64(2000), "eu", "paris", "30"},
+				},
+			}
+
+			series, err := TableToTimeSeries(table, tableOptions(`{"valueColumn": "revenue", "groupBy": ["region"]}`), timeRange)
+
+			So(err, ShouldBeNil)
+			So(series, ShouldHaveLength, 2)
+			So(series[0].Name, ShouldEqual, "revenue{region=eu}")
+			So(series[0].Tags, ShouldResemble, map[string]string{"region": "eu"})
+			So(series[0].Points, ShouldHaveLength, 2)
+			So(series[0].Points[0][0].Float64, ShouldEqual, 10.5)
+			So(series[0].Points[1][1].Float64, ShouldEqual, 2000)
+			So(series[1].Name, ShouldEqual, "revenue{region=us}")
+		})
+
+		Convey("Rows without text columns form one series", func() {
+			table := &Table{
+				Columns: []TableColumn{{Text: "time"}, {Text: "value"}},
+				Rows: []RowValues{
+					{int64(1000), float64(1)},
+					{int64(2000), nil},
+				},
+			}
+
+			series, err := TableToTimeSeries(table, tableOptions(`{}`), timeRange)
+
+			So(err, ShouldBeNil)
+			So(series, ShouldHaveLength, 1)
+			So(series[0].Name, ShouldEqual, "value")
+			So(series[0].Tags, ShouldBeNil)
+			So(series[0].Points, ShouldHaveLength, 2)
+			So(series[0].Points[1][0].Valid, ShouldBeFalse)
+		})
+
+		Convey("Parses RFC3339 times of documents", func() {
+			table := &Table{
+				Columns: []TableColumn{{Text: "@timestamp"}, {Text: "latency"}},
+				Rows:    []RowValues{{"2017-07-14T02:40:00Z", float64(250)}},
+			}
+
+			series, err := TableToTimeSeries(table, tableOptions(`{"timeColumn": "@timestamp"}`), timeRange)
+
+			So(err, ShouldBeNil)
+			So(series[0].Points[0][1].Float64, ShouldEqual, 1500000000*1000)
+		})
+
+		Convey("Fails without a numeric value column", func() {
+			table := &Table{
+				Columns: []TableColumn{{Text: "name"}},
+				Rows:    []RowValues{{"errors"}},
+			}
+
+			_, err := TableToTimeSeries(table, tableOptions(`{}`), timeRange)
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Skips rows that do not match the columns", func() {
+			table := &Table{
+				Columns: []TableColumn{{Text: "host"}, {Text: "value"}},
+				Rows:    []RowValues{{"web-1"}, {"web-2", float64(5)}},
+			}
+
+			series, err := TableToTimeSeries(table, tableOptions(`{}`), timeRange)
+
+			So(err, ShouldBeNil)
+			So(series, ShouldHaveLength, 1)
+			So(series[0].Tags, ShouldResemble, map[string]string{"host": "web-2"})
+		})
+
+		Convey("Fails for a missing group by column", func() {
+			table := &Table{
+				Columns: []TableColumn{{Text: "value"}},
+				Rows:    []RowValues{{float64(1)}},
+			}
+
+			_, err := TableToTimeSeries(table, tableOptions(`{"groupBy": ["region"]}`), timeRange)
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("An empty table has no series", func() {
+			series, err := TableToTimeSeries(&Table{}, tableOptions(`{}`), timeRange)
+
+			So(err, ShouldBeNil)
+			So(series, ShouldHaveLength, 0)
+		})
+	})
+}
@@ -127,3 +127,18 @@ func EpochPrecisionToMs(value float64) float64 {
 
 	return value
 }
+
+// ParseTimeShift parses durations like 1h, 7d or 2w.
+func ParseTimeShift(shift string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(shift, suffix) {
+			count, err := strconv.Atoi(strings.TrimSuffix(shift, suffix))
+			if err != nil {
+				return 0, err
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	return time.ParseDuration(shift)
+}
@@ -0,0 +1,305 @@
+package tsdb
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// Transformation changes the response of a request after the datasource
+// answered it, e.g. joins or renames series. Transformations of a request
+// run in order on the response of the previous one.
+type Transformation interface {
+	Transform(req *TsdbQuery, resp *Response) error
+}
+
+type TransformationFactory func(model *simplejson.Json) (Transformation, error)
+
+var transformationFactories = make(map[string]TransformationFactory)
+
+func init() {
+	RegisterTransformation("join", newJoinTransformation)
+	RegisterTransformation("outer_join_by_tag", newOuterJoinByTagTransformation)
+	RegisterTransformation("rename", newRenameTransformation)
+	RegisterTransformation("filter_by_name", newFilterByNameTransformation)
+	RegisterTransformation("time_shift", newTimeShiftTransformation)
+	RegisterTransformation("table_to_timeseries", newTableToTimeSeriesTransformation)
+	RegisterTransformation("math", newMathTransformation)
+}
+
+func RegisterTransformation(typeName string, factory TransformationFactory) {
+	transformationFactories[typeName] = factory
+}
+
+// NewTransformation creates the transformation of the model, the type of
+// the model selects the transformation.
+func NewTransformation(model *simplejson.Json) (Transformation, error) {
+	typeName := model.Get("type").MustString()
+
+	factory, exists := transformationFactories[typeName]
+	if !exists {
+		return nil, fmt.Errorf("Unknown transformation type %q", typeName)
+	}
+
+	transformation, err := factory(model)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s transformation: %v", typeName, err)
+	}
+
+	return transformation, nil
+}
+
+// NewTransformations creates the transformations of a list of models like
+// the transformations of a query request.
+func NewTransformations(models []interface{}) ([]Transformation, error) {
+	transformations := make([]Transformation, 0, len(models))
+
+	for _, model := range models {
+		transformation, err := NewTransformation(simplejson.NewFromAny(model))
+		if err != nil {
+			return nil, err
+		}
+		transformations = append(transformations, transformation)
+	}
+
+	return transformations, nil
+}
+
+// applyTransformations runs the transformations of the request on the
+// response. Responses with failed queries are returned as they are so the
+// error reaches the caller.
+func applyTransformations(req *TsdbQuery, resp *Response) error {
+	if len(req.Transformations) == 0 || resp == nil {
+		return nil
+	}
+
+	for _, result := range resp.Results {
+		if result.Error != nil || result.ErrorString != "" {
+			return nil
+		}
+	}
+
+	for _, transformation := range req.Transformations {
+		if err := transformation.Transform(req, resp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// transformationInputs are the refIds of the results a transformation
+// works on, all results when the model has none.
+type transformationInputs []string
+
+func newTransformationInputs(model *simplejson.Json) (transformationInputs, error) {
+	if _, exists := model.CheckGet("refIds"); !exists {
+		return nil, nil
+	}
+
+	refIds, err := model.Get("refIds").StringArray()
+	if err != nil {
+		return nil, fmt.Errorf("refIds must be a list of query refIds")
+	}
+
+	return transformationInputs(refIds), nil
+}
+
+// results returns the selected results ordered by refId, or in the order
+// of the refIds of the model.
+func (inputs transformationInputs) results(resp *Response) []*QueryResult {
+	results := make([]*QueryResult, 0)
+
+	if inputs == nil {
+		refIds := make([]string, 0, len(resp.Results))
+		for refId := range resp.Results {
+			refIds = append(refIds, refId)
+		}
+		sort.Strings(refIds)
+
+		for _, refId := range refIds {
+			results = append(results, resp.Results[refId])
+		}
+		return results
+	}
+
+	for _, refId := range inputs {
+		if result, exists := resp.Results[refId]; exists {
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+// replaceResults replaces the input results with the output of a
+// transformation, named after the first input unless it has a name.
+func replaceResults(resp *Response, inputs []*QueryResult, output *QueryResult, as string) {
+	if as == "" && len(inputs) > 0 {
+		as = inputs[0].RefId
+	}
+
+	for _, result := range inputs {
+		delete(resp.Results, result.RefId)
+	}
+
+	output.RefId = as
+	resp.Results[as] = output
+}
+
+// renameTransformation replaces the names of series matching the pattern,
+// the replacement can reference groups of the pattern like $1.
+type renameTransformation struct {
+	inputs      transformationInputs
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func newRenameTransformation(model *simplejson.Json) (Transformation, error) {
+	inputs, err := newTransformationInputs(model)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := regexp.Compile(model.Get("pattern").MustString())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %v", err)
+	}
+
+	return &renameTransformation{
+		inputs:      inputs,
+		pattern:     pattern,
+		replacement: model.Get("replacement").MustString(),
+	}, nil
+}
+
+func (t *renameTransformation) Transform(req *TsdbQuery, resp *Response) error {
+	for _, result := range t.inputs.results(resp) {
+		for _, series := range result.Series {
+			series.Name = t.pattern.ReplaceAllString(series.Name, t.replacement)
+		}
+	}
+
+	return nil
+}
+
+// filterByNameTransformation keeps the series with names matching the
+// pattern, or drops them when exclude is set.
+type filterByNameTransformation struct {
+	inputs  transformationInputs
+	pattern *regexp.Regexp
+	exclude bool
+}
+
+func newFilterByNameTransformation(model *simplejson.Json) (Transformation, error) {
+	inputs, err := newTransformationInputs(model)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := regexp.Compile(model.Get("pattern").MustString())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %v", err)
+	}
+
+	return &filterByNameTransformation{
+		inputs:  inputs,
+		pattern: pattern,
+		exclude: model.Get("exclude").MustBool(),
+	}, nil
+}
+
+func (t *filterByNameTransformation) Transform(req *TsdbQuery, resp *Response) error {
+	for _, result := range t.inputs.results(resp) {
+		filtered := make(TimeSeriesSlice, 0, len(result.Series))
+		for _, series := range result.Series {
+			if t.pattern.MatchString(series.Name) != t.exclude {
+				filtered = append(filtered, series)
+			}
+		}
+		result.Series = filtered
+	}
+
+	return nil
+}
+
+// timeShiftTransformation moves the points of series by the shift, e.g. to
+// compare the results of a query for last week with the current week.
+type timeShiftTransformation struct {
+	inputs transformationInputs
+	shift  time.Duration
+}
+
+func newTimeShiftTransformation(model *simplejson.Json) (Transformation, error) {
+	inputs, err := newTransformationInputs(model)
+	if err != nil {
+		return nil, err
+	}
+
+	shift, err := ParseTimeShift(model.Get("shift").MustString())
+	if err != nil {
+		return nil, fmt.Errorf("invalid shift: %v", err)
+	}
+
+	return &timeShiftTransformation{inputs: inputs, shift: shift}, nil
+}
+
+func (t *timeShiftTransformation) Transform(req *TsdbQuery, resp *Response) error {
+	shiftMs := float64(t.shift / time.Millisecond)
+
+	for _, result := range t.inputs.results(resp) {
+		for _, series := range result.Series {
+			for i := range series.Points {
+				if series.Points[i][1].Valid {
+					series.Points[i][1].Float64 += shiftMs
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// tableToTimeSeriesTransformation turns the tables of results into series,
+// like the table options of alert queries.
+type tableToTimeSeriesTransformation struct {
+	inputs  transformationInputs
+	options *TableSeriesOptions
+}
+
+func newTableToTimeSeriesTransformation(model *simplejson.Json) (Transformation, error) {
+	inputs, err := newTransformationInputs(model)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := NewTableSeriesOptions(model)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tableToTimeSeriesTransformation{inputs: inputs, options: options}, nil
+}
+
+func (t *tableToTimeSeriesTransformation) Transform(req *TsdbQuery, resp *Response) error {
+	timeRange := req.TimeRange
+	if timeRange == nil {
+		timeRange = NewTimeRange("now", "now")
+	}
+
+	for _, result := range t.inputs.results(resp) {
+		for _, table := range result.Tables {
+			series, err := TableToTimeSeries(table, t.options, timeRange)
+			if err != nil {
+				return fmt.Errorf("Could not convert tables of %s: %v", result.RefId, err)
+			}
+			result.Series = append(result.Series, series...)
+		}
+		result.Tables = nil
+	}
+
+	return nil
+}
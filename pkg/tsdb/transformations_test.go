@@ -0,0 +1,143 @@
+package tsdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTransformations(t *testing.T) {
+	Convey("Transforming responses", t, func() {
+		transform := func(model string, resp *Response) error {
+			json, err := simplejson.NewJson([]byte(model))
+			So(err, ShouldBeNil)
+
+			transformation, err := NewTransformation(json)
+			So(err, ShouldBeNil)
+
+			req := &TsdbQuery{TimeRange: NewFakeTimeRange("5m", "now", time.Unix(1500000000, 0))}
+			return transformation.Transform(req, resp)
+		}
+
+		resp := &Response{
+			Results: map[string]*QueryResult{
+				"A": {RefId: "A", Series: TimeSeriesSlice{
+					&TimeSeries{Name: "web-1.requests", Tags: map[string]string{"host": "web-1"}, Points: NewTimeSeriesPointsFromArgs(10, 1000, 20, 2000)},
+					&TimeSeries{Name: "web-2.requests", Tags: map[string]string{"host": "web-2"}, Points: NewTimeSeriesPointsFromArgs(30, 1000)},
+				}},
+				"B": {RefId: "B", Series: TimeSeriesSlice{
+					&TimeSeries{Name: "web-1.errors", Tags: map[string]string{"host": "web-1"}, Points: NewTimeSeriesPointsFromArgs(1, 2000, 2, 3000)},
+				}},
+			},
+		}
+
+		Convey("Rejects unknown transformations", func() {
+			json, _ := simplejson.NewJson([]byte(`{"type": "pivot"}`))
+			_, err := NewTransformation(json)
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Rejects invalid options", func() {
+			_, err := NewTransformations([]interface{}{
+				map[string]interface{}{"type": "rename", "pattern": "("},
+			})
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Renames series with a regex", func() {
+			So(transform(`{"type": "rename", "refIds": ["A"], "pattern": "^(.*)\\.requests$", "replacement": "$1"}`, resp), ShouldBeNil)
+
+			So(resp.Results["A"].Series[0].Name, ShouldEqual, "web-1")
+			So(resp.Results["A"].Series[1].Name, ShouldEqual, "web-2")
+			So(resp.Results["B"].Series[0].Name, ShouldEqual, "web-1.errors")
+		})
+
+		Convey("Filters series by name", func() {
+			So(transform(`{"type": "filter_by_name", "pattern": "web-1"}`, resp), ShouldBeNil)
+
+			So(resp.Results["A"].Series, ShouldHaveLength, 1)
+			So(resp.Results["B"].Series, ShouldHaveLength, 1)
+		})
+
+		Convey("Excludes series by name", func() {
+			So(transform(`{"type": "filter_by_name", "pattern": "web-1", "exclude": true}`, resp), ShouldBeNil)
+
+			So(resp.Results["A"].Series, ShouldHaveLength, 1)
+			So(resp.Results["A"].Series[0].Name, ShouldEqual, "web-2.requests")
+			So(resp.Results["B"].Series, ShouldHaveLength, 0)
+		})
+
+		Convey("Shifts the time of points", func() {
+			So(transform(`{"type": "time_shift", "refIds": ["B"], "shift": "1d"}`, resp), ShouldBeNil)
+
+			So(resp.Results["B"].Series[0].Points[0][1].Float64, ShouldEqual, 2000+24*60*60*1000)
+			So(resp.Results["A"].Series[0].Points[0][1].Float64, ShouldEqual, 1000)
+		})
+
+		Convey("Joins series on time", func() {
+			So(transform(`{"type": "join", "as": "joined"}`, resp), ShouldBeNil)
+
+			So(resp.Results, ShouldHaveLength, 1)
+			table := resp.Results["joined"].Tables[0]
+			So(table.Columns, ShouldResemble, []TableColumn{{Text: "time"}, {Text: "web-1.requests"}, {Text: "web-2.requests"}, {Text: "web-1.errors"}})
+			So(table.Rows, ShouldResemble, []RowValues{
+				{float64(1000), float64(10), float64(30), nil},
+				{float64(2000), float64(20), nil, float64(1)},
+				{float64(3000), nil, nil, float64(2)},
+			})
+		})
+
+		Convey("Outer joins series by tag", func() {
+			So(transform(`{"type": "outer_join_by_tag", "refIds": ["A", "B"], "tags": ["host"]}`, resp), ShouldBeNil)
+
+			So(resp.Results, ShouldHaveLength, 1)
+			table := resp.Results["A"].Tables[0]
+			So(table.Columns, ShouldResemble, []TableColumn{{Text: "host"}, {Text: "time"}, {Text: "A"}, {Text: "B"}})
+			So(table.Rows, ShouldResemble, []RowValues{
+				{"web-1", float64(1000), float64(10), nil},
+				{"web-1", float64(2000), float64(20), float64(1)},
+				{"web-1", float64(3000), nil, float64(2)},
+				{"web-2", float64(1000), float64(30), nil},
+			})
+		})
+
+		Convey("Converts tables to series", func() {
+			resp.Results["C"] = &QueryResult{RefId: "C", Tables: []*Table{{
+				Columns: []TableColumn{{Text: "time"}, {Text: "host"}, {Text: "load"}},
+				Rows:    []RowValues{{float64(1000), "web-1", float64(0.5)}},
+			}}}
+
+			So(transform(`{"type": "table_to_timeseries", "refIds": ["C"], "valueColumn": "load"}`, resp), ShouldBeNil)
+
+			So(resp.Results["C"].Tables, ShouldBeNil)
+			So(resp.Results["C"].Series, ShouldHaveLength, 1)
+			So(resp.Results["C"].Series[0].Name, ShouldEqual, "load{host=web-1}")
+			So(resp.Results["C"].Series[0].Points[0][0], ShouldResemble, null.FloatFrom(0.5))
+		})
+	})
+
+	Convey("Handling requests with transformations", t, func() {
+		fakeExecutor := registerFakeExecutor()
+		fakeExecutor.Return("A", TimeSeriesSlice{&TimeSeries{Name: "server1.cpu"}, &TimeSeries{Name: "server2.cpu"}})
+
+		transformations, err := NewTransformations([]interface{}{
+			map[string]interface{}{"type": "filter_by_name", "pattern": "server1"},
+			map[string]interface{}{"type": "rename", "pattern": "\\.cpu$", "replacement": ""},
+		})
+		So(err, ShouldBeNil)
+
+		req := &TsdbQuery{Queries: []*Query{{RefId: "A"}}, Transformations: transformations}
+		res, err := HandleRequest(context.TODO(), &models.DataSource{Id: 1, Type: "test"}, req)
+
+		So(err, ShouldBeNil)
+		So(res.Results["A"].Series, ShouldHaveLength, 1)
+		So(res.Results["A"].Series[0].Name, ShouldEqual, "server1")
+	})
+}
@@ -61,6 +61,44 @@ func TestMetricQuery(t *testing.T) {
 	})
 }
 
+func TestQueryCacheHook(t *testing.T) {
+	Convey("Given a query cache", t, func() {
+		cache := &fakeQueryCache{}
+		SetQueryCache(cache)
+		defer SetQueryCache(nil)
+
+		fakeExecutor := registerFakeExecutor()
+		fakeExecutor.Return("A", TimeSeriesSlice{&TimeSeries{Name: "argh"}})
+
+		Convey("Requests go through the cache", func() {
+			req := &TsdbQuery{Queries: []*Query{{RefId: "A"}}}
+			res, err := HandleRequest(context.TODO(), &models.DataSource{Id: 1, Type: "test"}, req)
+
+			So(err, ShouldBeNil)
+			So(cache.requests, ShouldEqual, 1)
+			So(res.Results["A"].Series[0].Name, ShouldEqual, "argh")
+		})
+
+		Convey("Requests skipping the cache go to the data source", func() {
+			req := &TsdbQuery{Queries: []*Query{{RefId: "A"}}, SkipCache: true}
+			res, err := HandleRequest(context.TODO(), &models.DataSource{Id: 1, Type: "test"}, req)
+
+			So(err, ShouldBeNil)
+			So(cache.requests, ShouldEqual, 0)
+			So(res.Results["A"].Series[0].Name, ShouldEqual, "argh")
+		})
+	})
+}
+
+type fakeQueryCache struct {
+	requests int
+}
+
+func (c *fakeQueryCache) HandleRequest(ctx context.Context, dsInfo *models.DataSource, req *TsdbQuery, next HandleRequestFunc) (*Response, error) {
+	c.requests++
+	return next(ctx, dsInfo, req)
+}
+
 func registerFakeExecutor() *FakeExecutor {
 	executor, _ := NewFakeExecutor(nil)
 	RegisterTsdbQueryEndpoint("test", func(dsInfo *models.DataSource) (TsdbQueryEndpoint, error) {
@@ -307,6 +307,7 @@ export class AlertTabCtrl {
     switch (evt.name) {
       case 'action': {
         conditionModel.source.reducer.type = evt.action.value;
+        conditionModel.source.reducer.params = null;
         conditionModel.reducerPart = alertDef.createReducerPart(conditionModel.source.reducer);
         break;
       }
@@ -379,6 +380,16 @@ export class AlertTabCtrl {
       }
       case 'no_value': {
         evaluator.params = [];
+        break;
+      }
+      case 'zscore':
+      case 'holt_winters': {
+        evaluator.params = [evaluator.params[0] || 3];
+        break;
+      }
+      case 'percent_change': {
+        evaluator.params = [evaluator.params[0], '1d'];
+        break;
       }
     }
 
@@ -18,10 +18,18 @@ export class AlertNotificationEditCtrl {
       httpMethod: 'POST',
       autoResolve: true,
       uploadImage: true,
+      groupBy: '',
     },
     isDefault: false,
   };
   getFrequencySuggestion: any;
+  preview: any;
+  groupByOptions = [
+    { text: 'None', value: '' },
+    { text: 'Dashboard', value: 'dashboard' },
+    { text: 'Folder', value: 'folder' },
+    { text: 'Dashboard tags', value: 'tag' },
+  ];
 
   /** @ngInject */
   constructor(private $routeParams, private backendSrv, private $location, private $templateCache, navModelSrv) {
@@ -118,6 +126,19 @@ export class AlertNotificationEditCtrl {
       appEvents.emit('alert-success', ['Test notification sent', '']);
     });
   }
+
+  previewTemplates() {
+    const payload = {
+      name: this.model.name,
+      type: this.model.type,
+      settings: this.model.settings,
+      preview: true,
+    };
+
+    this.backendSrv.post(`/api/alert-notifications/test`, payload).then(res => {
+      this.preview = { title: res.title, body: res.body };
+    });
+  }
 }
 
 coreModule.controller('AlertNotificationEditCtrl', AlertNotificationEditCtrl);
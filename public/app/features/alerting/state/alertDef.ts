@@ -31,6 +31,9 @@ const evalFunctions = [
   { text: 'IS OUTSIDE RANGE', value: 'outside_range' },
   { text: 'IS WITHIN RANGE', value: 'within_range' },
   { text: 'HAS NO VALUE', value: 'no_value' },
+  { text: 'DEVIATES FROM MEAN BY', value: 'zscore' },
+  { text: 'CHANGED BY', value: 'percent_change' },
+  { text: 'DEVIATES FROM FORECAST BY', value: 'holt_winters' },
 ];
 
 const evalOperators = [{ text: 'OR', value: 'or' }, { text: 'AND', value: 'and' }];
@@ -46,8 +49,17 @@ const reducerTypes = [
   { text: 'diff()', value: 'diff' },
   { text: 'percent_diff()', value: 'percent_diff' },
   { text: 'count_non_null()', value: 'count_non_null' },
+  { text: 'stddev()', value: 'stddev' },
+  { text: 'rate()', value: 'rate' },
+  { text: 'percentile()', value: 'percentile' },
+  { text: 'count_above()', value: 'count_above' },
 ];
 
+const reducerParams = {
+  percentile: { params: [{ name: 'percentile', type: 'number' }], defaultParams: [95] },
+  count_above: { params: [{ name: 'threshold', type: 'number' }], defaultParams: [0] },
+};
+
 const noDataModes = [
   { text: 'Alerting', value: 'alerting' },
   { text: 'No Data', value: 'no_data' },
@@ -58,7 +70,8 @@ const noDataModes = [
 const executionErrorModes = [{ text: 'Alerting', value: 'alerting' }, { text: 'Keep Last State', value: 'keep_state' }];
 
 function createReducerPart(model) {
-  const def = new QueryPartDef({ type: model.type, defaultParams: [] });
+  const params = reducerParams[model.type] || { params: [], defaultParams: [] };
+  const def = new QueryPartDef({ type: model.type, params: params.params, defaultParams: params.defaultParams });
   return new QueryPart(model, def);
 }
 